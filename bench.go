@@ -0,0 +1,188 @@
+// File: bench.go
+package main
+
+import (
+	"flag"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// benchTiming accumulates the time spent on a file or extension
+type benchTiming struct {
+	name     string
+	duration time.Duration
+	files    int
+}
+
+// runBench times repeated dry runs of a directory tree and reports where the
+// time goes, without modifying anything
+func runBench(args []string) int {
+	var (
+		targetDir  string
+		runs       int
+		detectOnly bool
+		top        int
+	)
+
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Target directory to benchmark")
+	flags.IntVar(&runs, "runs", 3, "Number of runs to time")
+	flags.BoolVar(&detectOnly, "detect-only", false, "Only time the walk and file detection, as pathfix list does")
+	flags.IntVar(&top, "top", 10, "Number of slowest files and extensions to report")
+	options := processorFlags(flags)
+	profiling := profileFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if runs < 1 {
+		msg.Fprintf(os.Stderr, "--runs must be at least 1\n")
+		return exitUsage
+	}
+
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+
+	stopProfiles, err := profiling.start()
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	defer stopProfiles()
+
+	// Each result is charged the time since the previous one, which covers
+	// walking to the file as well as processing it
+	files := make(map[string]*benchTiming)
+	exts := make(map[string]*benchTiming)
+	var last time.Time
+	options.DryRun = true
+	options.ListOnly = detectOnly
+	options.OnResult = func(result models.FileResult) {
+		now := time.Now()
+		elapsed := now.Sub(last)
+		last = now
+		if result.Action == models.ActionSkipped {
+			return
+		}
+		ext := strings.ToLower(path.Ext(result.Path))
+		if ext == "" {
+			ext = path.Base(result.Path)
+		}
+		for _, timing := range []*benchTiming{benchEntry(files, result.Path), benchEntry(exts, ext)} {
+			timing.duration += elapsed
+			timing.files++
+		}
+	}
+
+	var durations []time.Duration
+	var stats models.Stats
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < runs; i++ {
+		p := processor.NewProcessor(absPath, options)
+		if p.ConfigError() != nil {
+			return exitUsage
+		}
+		start := time.Now()
+		last = start
+		stats, err = p.Process()
+		if runFailed(err) {
+			msg.Fprintf(os.Stderr, "Error processing directory: %v\n", err)
+			return exitFailure
+		}
+		durations = append(durations, time.Since(start))
+	}
+	runtime.ReadMemStats(&after)
+
+	// Report the spread of the runs
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	median := durations[len(durations)/2]
+	mode := "dry run"
+	if detectOnly {
+		mode = "detection only"
+	}
+	msg.Printf("Benchmarked %d runs over %d files (%s, no files written)\n", runs, stats.Processed+stats.Skipped, mode)
+	msg.Printf("  fastest %v, median %v, slowest %v\n", roundDuration(durations[0]), roundDuration(median), roundDuration(durations[len(durations)-1]))
+	if seconds := median.Seconds(); seconds > 0 {
+		msg.Printf("  %.0f files/s, %.1f MB allocated per run\n",
+			float64(stats.Processed+stats.Skipped)/seconds, float64(after.TotalAlloc-before.TotalAlloc)/float64(runs)/(1<<20))
+	}
+
+	// Hot spots, averaged over the runs
+	var total time.Duration
+	for _, timing := range exts {
+		total += timing.duration
+	}
+	printBenchTimings(msg.Sprintf("Slowest files"), files, top, runs, 0)
+	printBenchTimings(msg.Sprintf("Time by extension"), exts, top, runs, total)
+	return exitClean
+}
+
+// benchEntry returns the timing for name, adding it if needed
+func benchEntry(timings map[string]*benchTiming, name string) *benchTiming {
+	timing, ok := timings[name]
+	if !ok {
+		timing = &benchTiming{name: name}
+		timings[name] = timing
+	}
+	return timing
+}
+
+// printBenchTimings prints the top entries by time per run, with their share
+// of total when it is not zero
+func printBenchTimings(title string, timings map[string]*benchTiming, top, runs int, total time.Duration) {
+	if len(timings) == 0 || top <= 0 {
+		return
+	}
+	sorted := make([]*benchTiming, 0, len(timings))
+	width := 0
+	for _, timing := range timings {
+		sorted = append(sorted, timing)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].duration != sorted[j].duration {
+			return sorted[i].duration > sorted[j].duration
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	if len(sorted) > top {
+		sorted = sorted[:top]
+	}
+	for _, timing := range sorted {
+		if len(timing.name) > width {
+			width = len(timing.name)
+		}
+	}
+
+	msg.Printf("\n%s:\n", title)
+	for _, timing := range sorted {
+		perRun := roundDuration(timing.duration / time.Duration(runs))
+		if total > 0 {
+			msg.Printf("  %-*s  %5.1f%%  %v  (%d files)\n", width, timing.name,
+				100*float64(timing.duration)/float64(total), perRun, timing.files/runs)
+		} else {
+			msg.Printf("  %-*s  %v\n", width, timing.name, perRun)
+		}
+	}
+}
+
+// roundDuration rounds a duration for display
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,61 @@
+// File: bot.go
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/pathfix/pkg/bot"
+)
+
+// runBot fixes headers on a new branch and opens a pull request for them
+func runBot(args []string) int {
+	var (
+		targetDir string
+		options   bot.Options
+	)
+
+	flags := flag.NewFlagSet("bot", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Directory inside the git work tree to process")
+	flags.StringVar(&options.Provider, "provider", bot.ProviderGitHub, "Hosting provider: github or gitlab")
+	flags.StringVar(&options.APIURL, "api-url", "", "API base URL for self-hosted instances")
+	flags.StringVar(&options.Repo, "repo", "", "Repository as owner/name (GitHub) or project path or ID (GitLab)")
+	flags.StringVar(&options.Token, "token", "", "API token (default $GITHUB_TOKEN or $GITLAB_TOKEN)")
+	flags.StringVar(&options.Remote, "remote", bot.DefaultRemote, "Git remote to push the branch to")
+	flags.StringVar(&options.Branch, "branch", bot.DefaultBranch, "Branch to create for the fixes")
+	flags.StringVar(&options.Base, "base", "", "Branch the pull request targets (default: the current branch; required when HEAD is detached)")
+	flags.StringVar(&options.Title, "title", bot.DefaultTitle, "Commit message and pull request title")
+	processorOptions := processorFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if options.Token == "" {
+		switch options.Provider {
+		case bot.ProviderGitHub:
+			options.Token = os.Getenv("GITHUB_TOKEN")
+		case bot.ProviderGitLab:
+			options.Token = os.Getenv("GITLAB_TOKEN")
+		}
+	}
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+
+	options.Processor = *processorOptions
+	result, err := bot.Run(absPath, options)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	if result.URL == "" {
+		msg.Printf("All headers are up to date. No pull request was opened.\n")
+		return exitClean
+	}
+	msg.Printf("Updated %d files on %s\n", result.Stats.Updated, result.Branch)
+	msg.Printf("Opened %s\n", result.URL)
+	return exitClean
+}
@@ -0,0 +1,46 @@
+// File: cleanstate.go
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// runCleanState deletes the state directory of a target directory
+func runCleanState(args []string) int {
+	var targetDir string
+
+	flags := flag.NewFlagSet("clean-state", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Target directory whose state to delete")
+	options := processorFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+	p := processor.NewProcessor(absPath, options)
+	if p.ConfigError() != nil {
+		return exitUsage
+	}
+
+	dir := p.State()
+	if _, err := os.Stat(dir.Path()); errors.Is(err, fs.ErrNotExist) {
+		msg.Printf("No state directory at %s\n", dir.Path())
+		return exitClean
+	}
+	if err := dir.Clean(); err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	msg.Printf("Removed %s\n", dir.Path())
+	return exitClean
+}
@@ -0,0 +1,17 @@
+// File: cmd/pathfix-vet/main.go
+
+// Command pathfix-vet checks pathfix headers as a go vet tool:
+//
+//	go install github.com/yourusername/pathfix/cmd/pathfix-vet
+//	go vet -vettool=$(which pathfix-vet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/yourusername/pathfix/pkg/analyzer"
+)
+
+func main() {
+	unitchecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,175 @@
+// File: commands.go
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/i18n"
+)
+
+// Exit codes shared by all commands
+const (
+	exitClean   = 0 // Nothing to report
+	exitChanges = 1 // Headers were changed or need changing (fix with --error-on-diff)
+	exitFailure = 2 // The run failed, or files failed beyond the allowed threshold
+	exitUsage   = 3 // Invalid flags, arguments or configuration
+)
+
+// command is a pathfix subcommand, selected by the first argument
+type command struct {
+	name        string
+	usage       string // Arguments after the command name in the reference, when not just "[flags]"
+	summary     string
+	description string                  // Longer explanation for the generated reference
+	run         func(args []string) int // Returns the process exit code
+}
+
+// commands lists the subcommands in the order they appear in the usage text
+var commands []command
+
+// The table is filled in init because the commands' usage text refers back to it
+func init() {
+	commands = []command{
+		{
+			name:        "fix",
+			summary:     "Add or update file path headers (the default command)",
+			description: "Walks the directory tree and adds a comment with each source file's path relative to the root, or updates a stale one. Files ignored by .gitignore, hidden files, binaries and unknown file types are skipped.",
+			run:         runFix,
+		},
+		{
+			name:        "multi",
+			usage:       "[flags] <dir>...",
+			summary:     "Fix several independent directory trees in one run",
+			description: "Processes each root directory as a separate run, several at a time, with its own .pathfix.json config when it has one and --config is not given. Prints the statistics of each root and the totals, and can write them as a combined JSON report.",
+			run:         runMulti,
+		},
+		{
+			name:        "serve",
+			summary:     "Run an HTTP API that triggers fix and check runs",
+			description: "Serves an HTTP API that runs fixes and dry-run checks on the configured roots, so other services can trigger them.",
+			run:         runServe,
+		},
+		{
+			name:        "rpc",
+			summary:     "Fix editor buffers over JSON-RPC on stdin and stdout",
+			description: "Reads JSON-RPC requests on stdin and answers on stdout with the header each editor buffer should have, without touching files on disk.",
+			run:         runRPC,
+		},
+		{
+			name:        "list",
+			summary:     "Print the files that would be processed, for use with xargs",
+			description: "Prints the files pathfix would process, one per line. Only the detection checks run, so file contents are not read beyond the binary sniffing window.",
+			run:         runList,
+		},
+		{
+			name:        "stats",
+			summary:     "Report header coverage by directory and language without modifying anything",
+			description: "Reports how many eligible files already have the correct header, in total and by directory and language, as text, JSON or a shields.io badge.",
+			run:         runStats,
+		},
+		{
+			name:        "bot",
+			summary:     "Open a pull request with the fixes when any headers are missing or stale",
+			description: "Commits the fixes to a new branch, pushes it and opens a GitHub pull request or GitLab merge request. Nothing happens when all headers are up to date.",
+			run:         runBot,
+		},
+		{
+			name:        "install-hook",
+			usage:       "[flags] [-- fix flags]",
+			summary:     "Install a git pre-commit hook that checks or fixes the headers of staged files",
+			description: "Adds commands to the pre-commit hook of the git repository, creating the hook if there is none, that run pathfix fix on the files staged for commit beneath the directory. In check mode they stop the commit when a header is missing or stale; in fix mode they also fix the headers, to be staged before committing again. Installing again replaces them. Flags after -- are passed on to fix.",
+			run:         runInstallHook,
+		},
+		{
+			name:        "tui",
+			summary:     "Review proposed changes in a terminal UI and apply them selectively",
+			description: "Shows the proposed changes as a tree in the terminal, where each change can be previewed as a diff and applied or skipped before anything is written.",
+			run:         runTUI,
+		},
+		{
+			name:        "config",
+			usage:       "migrate --config <file> [flags]",
+			summary:     "Upgrade a config file to the current schema",
+			description: "Rewrites a config file written for an older version of pathfix in the current schema and lists each change. Older files still load, since pathfix upgrades them in memory, but migrating keeps them from relying on that.",
+			run:         runConfig,
+		},
+		{
+			name:        "bench",
+			summary:     "Time dry runs of a tree and report the slowest files and extensions",
+			description: "Runs the walk, detection and header checks several times without writing anything and reports the run times, throughput, allocations and where the time went. Combine with --cpuprofile, --memprofile or --trace for detail.",
+			run:         runBench,
+		},
+		{
+			name:        "clean-state",
+			summary:     "Delete the state directory with the run history",
+			description: "Deletes the state directory (.pathfix unless StateDir or --state-dir say otherwise), which holds the run history and other data kept between runs. Directories that pathfix did not create are left alone.",
+			run:         runCleanState,
+		},
+		{
+			name:        "version",
+			summary:     "Print the version, build details and default settings",
+			description: "Prints the version, the commit and date of the build, the Go toolchain, the default settings and the features compiled in.",
+			run:         runVersion,
+		},
+		{
+			name:        "docs",
+			summary:     "Generate the command reference as a manpage or Markdown",
+			description: "Generates this reference from the command and flag definitions, so it always matches the build.",
+			run:         runDocs,
+		},
+	}
+}
+
+// msg prints user-facing messages in the selected locale
+var msg = i18n.NewPrinter(i18n.DetectLocale())
+
+// describeFlags, when set, receives each command's flags instead of them
+// being parsed. The docs command runs every command with it to collect their
+// flag definitions.
+var describeFlags func(flags *flag.FlagSet)
+
+// parseFlags registers the flags shared by every command and parses a
+// command's flags. When parsing stops the command, it returns false and the
+// exit code: clean for -h, usage for invalid flags.
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	flags.Func("locale", "Language of messages, as a `locale` ("+strings.Join(i18n.Locales(), ", ")+"; default from PATHFIX_LOCALE, LC_ALL, LC_MESSAGES or LANG)", func(locale string) error {
+		msg = i18n.NewPrinter(locale)
+		return nil
+	})
+	if describeFlags != nil {
+		describeFlags(flags)
+		return exitClean, false
+	}
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitClean, false
+	}
+	if err != nil {
+		return exitUsage, false
+	}
+	return exitClean, true
+}
+
+// findCommand looks up a subcommand by name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// printUsage describes the commands and the flags of the default command
+func printUsage(flags *flag.FlagSet) {
+	out := flags.Output()
+	msg.Fprintf(out, "Usage: %s [command] [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		msg.Fprintf(out, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	msg.Fprintf(out, "\nFlags:\n")
+	flags.PrintDefaults()
+}
@@ -0,0 +1,74 @@
+// File: config.go
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// runConfig manages config files. Its only action is migrate, which
+// upgrades a config file to the current schema.
+func runConfig(args []string) int {
+	// The docs command describes the migrate flags without an action
+	if describeFlags == nil && (len(args) == 0 || args[0] != "migrate") {
+		msg.Fprintf(os.Stderr, "Usage: pathfix config migrate --config <file> [flags]\n")
+		return exitUsage
+	}
+	if len(args) > 0 {
+		args = args[1:]
+	}
+
+	var (
+		configPath string
+		outPath    string
+		dryRun     bool
+	)
+
+	flags := flag.NewFlagSet("config migrate", flag.ContinueOnError)
+	flags.StringVar(&configPath, "config", "", "Config file to upgrade")
+	flags.StringVar(&outPath, "out", "", "Where to write the upgraded config (default: replace the config file)")
+	flags.BoolVar(&dryRun, "dry-run", false, "Print the upgraded config instead of writing it")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if configPath == "" {
+		msg.Fprintf(os.Stderr, "--config is required\n")
+		return exitUsage
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
+		return exitUsage
+	}
+	migrated, changes, err := processor.MigrateConfig(data)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error migrating %s: %v\n", configPath, err)
+		return exitUsage
+	}
+	if len(changes) == 0 {
+		msg.Printf("%s is already at config version %d\n", configPath, processor.ConfigVersion)
+		return exitClean
+	}
+
+	// The summary goes to stderr so a dry run's stdout is the config alone
+	msg.Fprintf(os.Stderr, "Migrated %s to config version %d:\n", configPath, processor.ConfigVersion)
+	for _, change := range changes {
+		msg.Fprintf(os.Stderr, "  - %s\n", change)
+	}
+	if dryRun {
+		os.Stdout.Write(migrated)
+		return exitClean
+	}
+
+	if outPath == "" {
+		outPath = configPath
+	}
+	if err := os.WriteFile(outPath, migrated, 0644); err != nil {
+		msg.Fprintf(os.Stderr, "Error writing %s: %v\n", outPath, err)
+		return exitFailure
+	}
+	return exitClean
+}
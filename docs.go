@@ -0,0 +1,160 @@
+// File: docs.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// commandReference is a command with the flags it accepts
+type commandReference struct {
+	command
+	flags []*flag.Flag
+}
+
+// runDocs generates the command reference from the command table and the
+// flags each command registers
+func runDocs(args []string) int {
+	var (
+		format  string
+		outPath string
+	)
+
+	flags := flag.NewFlagSet("docs", flag.ContinueOnError)
+	flags.StringVar(&format, "format", "markdown", "Output format: man or markdown")
+	flags.StringVar(&outPath, "out", "", "Write the reference to this file instead of standard output")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "man":
+		writeManPage(&buf, collectReference())
+	case "markdown":
+		writeMarkdown(&buf, collectReference())
+	default:
+		msg.Fprintf(os.Stderr, "Unknown docs format %q (expected man or markdown)\n", format)
+		return exitUsage
+	}
+
+	if outPath == "" {
+		os.Stdout.Write(buf.Bytes())
+		return exitClean
+	}
+	if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
+		msg.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return exitFailure
+	}
+	return exitClean
+}
+
+// collectReference runs each command with describeFlags set, which returns
+// from parseFlags with the command's flags instead of running it
+func collectReference() []commandReference {
+	defer func() { describeFlags = nil }()
+
+	refs := make([]commandReference, 0, len(commands))
+	for _, cmd := range commands {
+		ref := commandReference{command: cmd}
+		describeFlags = func(flags *flag.FlagSet) {
+			flags.VisitAll(func(f *flag.Flag) {
+				ref.flags = append(ref.flags, f)
+			})
+		}
+		cmd.run(nil)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// synopsis returns the arguments shown after the command name
+func (ref commandReference) synopsis() string {
+	if ref.usage != "" {
+		return ref.usage
+	}
+	return "[flags]"
+}
+
+// describeFlag returns a flag's name, the name of its value (empty for
+// boolean flags), its description and its default when that is not empty
+func describeFlag(f *flag.Flag) (name, value, usage, def string) {
+	value, usage = flag.UnquoteUsage(f)
+	switch f.DefValue {
+	case "", "0", "false":
+	default:
+		def = f.DefValue
+	}
+	return "--" + f.Name, value, usage, def
+}
+
+// writeMarkdown writes the reference as a Markdown document
+func writeMarkdown(w io.Writer, refs []commandReference) {
+	fmt.Fprintf(w, "# pathfix command reference\n\n")
+	fmt.Fprintf(w, "<!-- Generated by `pathfix docs --format markdown`. Do not edit. -->\n\n")
+	fmt.Fprintf(w, "Usage: `pathfix [command] [flags]`. Without a command name, pathfix runs `fix`.\n\n")
+	for _, ref := range refs {
+		fmt.Fprintf(w, "- [`%s`](#pathfix-%s): %s\n", ref.name, ref.name, ref.summary)
+	}
+
+	for _, ref := range refs {
+		fmt.Fprintf(w, "\n## pathfix %s\n\n%s\n\n", ref.name, ref.description)
+		fmt.Fprintf(w, "Usage: `pathfix %s %s`\n\n", ref.name, ref.synopsis())
+		for _, f := range ref.flags {
+			name, value, usage, def := describeFlag(f)
+			if value != "" {
+				name += " " + value
+			}
+			fmt.Fprintf(w, "- `%s`: %s", name, usage)
+			if def != "" {
+				fmt.Fprintf(w, " (default: `%s`)", def)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// writeManPage writes the reference as a pathfix(1) manpage in roff
+func writeManPage(w io.Writer, refs []commandReference) {
+	fmt.Fprintf(w, ".TH PATHFIX 1 \"\" \"pathfix %s\" \"User Commands\"\n", roffEscape(buildVersion().Version))
+	fmt.Fprintf(w, ".SH NAME\npathfix \\- add or update file path headers in source files\n")
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B pathfix\n[\\fIcommand\\fR] [\\fIflags\\fR]\n")
+	fmt.Fprintf(w, ".SH DESCRIPTION\nWithout a command name, pathfix runs \\fBfix\\fR.\n")
+	fmt.Fprintf(w, ".SH COMMANDS\n")
+	for _, ref := range refs {
+		fmt.Fprintf(w, ".SS \"pathfix %s\"\n%s\n", ref.name, roffLine(ref.description))
+		fmt.Fprintf(w, ".PP\nUsage: \\fBpathfix %s\\fR %s\n", ref.name, roffEscape(ref.synopsis()))
+		for _, f := range ref.flags {
+			name, value, usage, def := describeFlag(f)
+			fmt.Fprintf(w, ".TP\n\\fB%s\\fR", roffEscape(name))
+			if value != "" {
+				fmt.Fprintf(w, " \\fI%s\\fR", roffEscape(value))
+			}
+			fmt.Fprintf(w, "\n%s", roffLine(usage))
+			if def != "" {
+				fmt.Fprintf(w, " (default: %s)", roffEscape(def))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// roffEscape escapes the backslashes and hyphens in text for roff
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\e")
+	return strings.ReplaceAll(s, "-", "\\-")
+}
+
+// roffLine escapes text that starts a line, where a leading period or quote
+// would otherwise be read as a request
+func roffLine(s string) string {
+	s = roffEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = "\\&" + s
+	}
+	return s
+}
@@ -1,3 +1,18 @@
 module github.com/yourusername/pathfix
 
-go 1.20
+go 1.22.0
+
+require (
+	github.com/fsnotify/fsnotify v1.8.0
+	go.starlark.net v0.0.0-20230612165344-9532f5667272
+	golang.org/x/term v0.15.0
+	golang.org/x/text v0.14.0
+	golang.org/x/tools v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+)
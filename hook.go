@@ -0,0 +1,213 @@
+// File: hook.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The lines that enclose pathfix's part of a pre-commit hook, so that
+// installing again replaces it
+const (
+	hookBegin = "# >>> pathfix >>>"
+	hookEnd   = "# <<< pathfix <<<"
+)
+
+// Hook modes
+const (
+	hookCheck = "check" // Stop the commit if a staged file's header is missing or stale
+	hookFix   = "fix"   // Fix the staged files' headers and stop the commit so they can be staged
+)
+
+// runInstallHook writes a git pre-commit hook that runs pathfix on the
+// staged files
+func runInstallHook(args []string) int {
+	var (
+		targetDir string
+		mode      string
+		printOnly bool
+	)
+
+	flags := flag.NewFlagSet("install-hook", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Directory inside the git work tree whose files the hook processes")
+	flags.StringVar(&mode, "mode", hookCheck, "What the hook does about missing or stale headers: check stops the commit, fix also fixes them for you to stage")
+	flags.BoolVar(&printOnly, "print", false, "Print the hook commands instead of installing them, to add them to a hook manager's configuration")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if mode != hookCheck && mode != hookFix {
+		msg.Fprintf(os.Stderr, "--mode must be %s or %s\n", hookCheck, hookFix)
+		return exitUsage
+	}
+
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+	workTree, err := runGit(absPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		msg.Fprintf(os.Stderr, "%s is not in a git work tree: %v\n", absPath, err)
+		return exitUsage
+	}
+	dir, err := filepath.Rel(evalPath(workTree), evalPath(absPath))
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	// Flags after the hook's own, following --, are passed on to fix
+	block := hookBlock(filepath.ToSlash(dir), mode, flags.Args())
+	if printOnly {
+		fmt.Print(block)
+		return exitClean
+	}
+
+	// core.hooksPath moves the hooks out of .git
+	hookPath, err := runGit(absPath, "rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	if !filepath.IsAbs(hookPath) {
+		hookPath = filepath.Join(absPath, hookPath)
+	}
+	replaced, err := installHook(hookPath, block)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	if replaced {
+		msg.Printf("Updated the pathfix commands in %s\n", hookPath)
+	} else {
+		msg.Printf("Installed the pre-commit hook %s\n", hookPath)
+	}
+	return exitClean
+}
+
+// hookBlock returns the hook commands that run pathfix on the staged files
+// beneath dir, relative to the top of the work tree, where git runs hooks.
+// They run in a subshell, so they leave the variables of a hook they are
+// added to alone, and only fail the hook when the commit has to stop.
+func hookBlock(dir, mode string, fixArgs []string) string {
+	args := []string{"fix", "--dir", shellQuote(dir), "--files-from", "-"}
+	advice := "staged files have missing or stale path headers; run pathfix fix, stage the changes and commit again"
+	if mode == hookCheck {
+		args = append(args, "--check")
+	} else {
+		args = append(args, "--error-on-diff")
+		advice = "updated the path headers of staged files; stage the changes and commit again"
+	}
+	for _, arg := range fixArgs {
+		args = append(args, shellQuote(arg))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(hookBegin + "\n")
+	fmt.Fprintf(&sb, "# Installed by pathfix install-hook --mode %s\n", mode)
+	sb.WriteString("(\n")
+	fmt.Fprintf(&sb, "\tfiles=$(git -c core.quotePath=off diff --cached --name-only --diff-filter=ACMR -- %s) || exit 1\n", shellQuote(dir))
+	sb.WriteString("\t[ -n \"$files\" ] || exit 0\n")
+	fmt.Fprintf(&sb, "\tprintf '%%s\\n' \"$files\" | \"${PATHFIX:-pathfix}\" %s\n", strings.Join(args, " "))
+	sb.WriteString("\tstatus=$?\n")
+	fmt.Fprintf(&sb, "\t[ $status -eq 1 ] && echo 'pathfix: %s' >&2\n", advice)
+	sb.WriteString("\texit $status\n")
+	sb.WriteString(") || exit $?\n")
+	sb.WriteString(hookEnd + "\n")
+	return sb.String()
+}
+
+// installHook adds the block to the hook at path, replacing the block of an
+// earlier installation, and reports whether there was one. A new hook is a
+// shell script; an existing one must be, as the block goes after its first
+// line, before anything that could end it.
+func installHook(path, block string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return false, err
+		}
+		return false, os.WriteFile(path, []byte("#!/bin/sh\n"+block), 0755)
+	} else if err != nil {
+		return false, err
+	}
+
+	hook := string(content)
+	if begin := strings.Index(hook, hookBegin+"\n"); begin >= 0 {
+		end := strings.Index(hook[begin:], hookEnd+"\n")
+		if end < 0 {
+			return false, fmt.Errorf("%s has the start of the pathfix commands but not their end (%s)", path, hookEnd)
+		}
+		hook = hook[:begin] + block + hook[begin+end+len(hookEnd)+1:]
+		return true, writeHook(path, hook)
+	}
+
+	firstLine, rest, _ := strings.Cut(hook, "\n")
+	if !isShellScript(firstLine) {
+		return false, fmt.Errorf("%s is not a shell script; add the commands of pathfix install-hook --print to it yourself", path)
+	}
+	return false, writeHook(path, firstLine+"\n"+block+rest)
+}
+
+// writeHook replaces the content of a hook, keeping its permissions
+func writeHook(path, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), info.Mode().Perm())
+}
+
+// isShellScript reports whether a shebang line runs a POSIX shell
+func isShellScript(shebang string) bool {
+	fields := strings.Fields(strings.TrimPrefix(shebang, "#!"))
+	if !strings.HasPrefix(shebang, "#!") || len(fields) == 0 {
+		return false
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" && len(fields) > 1 {
+		interpreter = fields[1]
+	}
+	switch interpreter {
+	case "sh", "bash", "dash", "ksh", "zsh", "ash":
+		return true
+	}
+	return false
+}
+
+// shellQuote quotes s for a POSIX shell when it needs quoting
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=,:@%+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// evalPath resolves the symbolic links in path, as git does for the top of
+// the work tree, or returns it unchanged if they cannot be resolved
+func evalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// runGit runs a git command in dir and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,91 @@
+// File: list.go
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// runList prints the files that would be processed, one per line
+func runList(args []string) int {
+	var (
+		targetDir string
+		changed   bool
+		null      bool
+		filesFrom string
+	)
+
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Target directory to list")
+	flags.BoolVar(&changed, "changed", false, "Only list files whose header would be added or updated")
+	flags.BoolVar(&null, "null", false, "Separate paths with NUL instead of newline, for xargs -0")
+	flags.BoolVar(&null, "0", false, "Shorthand for --null")
+	flags.StringVar(&filesFrom, "files-from", "", "List only the files named in this file, one per line (- for stdin), instead of walking the directory")
+	options := processorFlags(flags)
+	gitFileFlags(flags, options)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if !gitFilesAllowed(options, filesFrom != "") {
+		return exitUsage
+	}
+
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+
+	if filesFrom != "" {
+		if options.Files, err = readFiles(filesFrom); err != nil {
+			msg.Fprintf(os.Stderr, "Error reading file list: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	// Paths are printed relative to the current directory, like find
+	separator := "\n"
+	if null {
+		separator = "\x00"
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	// Without --changed only detection runs, so content is never read in full
+	action := models.ActionListed
+	options.DryRun = true
+	options.ListOnly = !changed
+	if changed {
+		action = models.ActionUpdated
+	}
+	failed := false
+	options.OnResult = func(result models.FileResult) {
+		switch result.Action {
+		case action:
+			fmt.Fprint(out, filepath.Join(targetDir, filepath.FromSlash(result.Path)), separator)
+		case models.ActionError:
+			msg.Fprintf(os.Stderr, "Error processing file %s: %s\n", result.Path, result.Error)
+			failed = true
+		}
+	}
+
+	p := processor.NewProcessor(absPath, options)
+	if p.ConfigError() != nil {
+		// NewProcessor has already reported the error
+		return exitUsage
+	}
+	if _, err := p.Process(); runFailed(err) {
+		msg.Fprintf(os.Stderr, "Error processing directory: %v\n", err)
+		return exitFailure
+	}
+	if failed {
+		return exitFailure
+	}
+	return exitClean
+}
@@ -0,0 +1,76 @@
+// File: logging.go
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/yourusername/pathfix/pkg/logfile"
+	"github.com/yourusername/pathfix/pkg/systemlog"
+)
+
+// logConfig holds the flags that configure the structured log
+type logConfig struct {
+	path      string
+	maxSize   int64 // Megabytes
+	backups   int
+	systemLog string
+}
+
+// logFlags registers the structured log flags
+func logFlags(flags *flag.FlagSet) *logConfig {
+	config := &logConfig{}
+	flags.StringVar(&config.path, "log-file", "", "Append a JSON line for every file decision and run to this file")
+	flags.Int64Var(&config.maxSize, "log-max-size", 10, "Rotate the log file when it reaches this many megabytes (0 to never rotate)")
+	flags.IntVar(&config.backups, "log-backups", 3, "Rotated log files to keep")
+	flags.StringVar(&config.systemLog, "system-log", "", "Also send run events to the system log: syslog or journald")
+	return config
+}
+
+// open opens the configured logs, returning nil if there are none
+func (c *logConfig) open() (io.WriteCloser, error) {
+	var writers logWriters
+	if c.path != "" {
+		w, err := logfile.Open(c.path, c.maxSize<<20, c.backups)
+		if err != nil {
+			return nil, err
+		}
+		writers = append(writers, w)
+	}
+	if c.systemLog != "" {
+		w, err := systemlog.Open(c.systemLog)
+		if err != nil {
+			writers.Close()
+			return nil, err
+		}
+		writers = append(writers, w)
+	}
+	if len(writers) == 0 {
+		return nil, nil
+	}
+	return writers, nil
+}
+
+// logWriters sends each log line to every configured log. Unlike
+// io.MultiWriter, a failing log does not keep the line from the others.
+type logWriters []io.WriteCloser
+
+func (l logWriters) Write(p []byte) (int, error) {
+	var firstErr error
+	for _, w := range l {
+		if _, err := w.Write(p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return len(p), firstErr
+}
+
+func (l logWriters) Close() error {
+	var firstErr error
+	for _, w := range l {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
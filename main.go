@@ -2,70 +2,504 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
 
+	"golang.org/x/term"
+
+	"github.com/yourusername/pathfix/pkg/models"
 	"github.com/yourusername/pathfix/pkg/processor"
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) > 0 {
+		if cmd, ok := findCommand(args[0]); ok {
+			os.Exit(cmd.run(args[1:]))
+		}
+	}
+
+	// Without a command name, pathfix fixes headers
+	os.Exit(runFix(args))
+}
+
+// runFix adds or updates the file headers of a directory tree
+func runFix(args []string) int {
 	var (
-		targetDir      string
-		dryRun         bool
-		configFilePath string
-		verbose        bool
-		includeHidden  bool
+		targetDir   string
+		dryRun      bool
+		verbose     bool
+		archivePath string
+		outPath     string
+		maxErrors   int
+		strict      bool
+		errorOnDiff bool
+		yes         bool
+		topDirs     int
+		reports     reportFlag
+		filesFrom   string
+		ghSummary   bool
+		remove      bool
+		check       bool
+		jsonOutput  bool
+		printDiffs  bool
+		backup      bool
+		undo        bool
+		progress    bool
+		watch       bool
+		metricsAddr string
+		moved       bool
 	)
 
 	// Parse command line arguments
-	flag.StringVar(&targetDir, "dir", ".", "Target directory to process")
-	flag.BoolVar(&dryRun, "dry-run", false, "Preview changes without modifying files")
-	flag.StringVar(&configFilePath, "config", "", "Path to custom configuration file")
-	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output")
-	flag.BoolVar(&includeHidden, "include-hidden", false, "Process hidden files and directories")
-	flag.Parse()
+	flags := flag.NewFlagSet("fix", flag.ContinueOnError)
+	flags.Usage = func() { printUsage(flags) }
+	flags.StringVar(&targetDir, "dir", ".", "Target directory to process")
+	flags.BoolVar(&dryRun, "dry-run", false, "Preview changes without modifying files")
+	flags.BoolVar(&verbose, "verbose", false, "Enable verbose output")
+	flags.BoolVar(&printDiffs, "diff", false, "Print a unified diff of each change; with --dry-run, review a rollout before any file is touched")
+	flags.BoolVar(&progress, "progress", false, "Count the files first, then show a progress bar on stderr as they are processed (a line every 10% without a terminal)")
+	flags.BoolVar(&watch, "watch", false, "After processing the directory, keep watching it and add headers to files as they are created, renamed or moved into place, until interrupted")
+	flags.StringVar(&metricsAddr, "metrics-listen", "", "With --watch, serve Prometheus metrics at /metrics on this `address`, such as 127.0.0.1:9090")
+	flags.BoolVar(&check, "check", false, "Verify that every file has an up-to-date header without modifying any; list the files that need one and exit with status 1 if there are any")
+	flags.BoolVar(&backup, "backup", false, "Copy each file into the state directory before modifying it, so that --undo can restore it (overrides Backup)")
+	flags.BoolVar(&undo, "undo", false, "Restore the files modified by the last run made with --backup, instead of processing any")
+	flags.BoolVar(&moved, "moved", false, "Only fix headers that name another path, as after a git mv or a move, and list each with the path it named; files without a header are left alone")
+	flags.BoolVar(&remove, "remove", false, "Remove existing path headers instead of adding or updating them")
+	flags.StringVar(&archivePath, "archive", "", "Process the files inside a .tar, .tar.gz, .tgz or .zip archive instead of a directory")
+	flags.StringVar(&filesFrom, "files-from", "", "Process only the files listed in this file, one per line (- for stdin), instead of walking the directory")
+	flags.StringVar(&outPath, "out", "", "Where to write the processed archive (required with --archive unless --dry-run)")
+	flags.IntVar(&maxErrors, "max-errors", 0, "Exit with status 2 if more files than this fail (-1 for no limit)")
+	flags.BoolVar(&strict, "warnings-as-errors", false, "Count files with warnings towards --max-errors")
+	flags.BoolVar(&errorOnDiff, "error-on-diff", false, "Exit with status 1 if any header was changed, or would be in a dry run")
+	flags.IntVar(&topDirs, "top-dirs", 0, "After the summary, list the directories with the most missing or stale headers (0 for none)")
+	flags.Var(&reports, "report", "Write a report of the run as `format[=path]`: csv, html or json, to stdout without a path (repeatable)")
+	flags.Func("output", "Output `format`: text, or json for a JSON report of the statistics and of each file on stdout, with everything else on stderr", func(format string) error {
+		if format != "text" && format != "json" {
+			return errors.New(`must be "text" or "json"`)
+		}
+		jsonOutput = format == "json"
+		return nil
+	})
+	flags.BoolVar(&ghSummary, "github-summary", false, "Append a Markdown summary of the run to $GITHUB_STEP_SUMMARY and group the verbose output in the GitHub Actions log")
+	flags.BoolVar(&yes, "yes", false, "Modify files even when more would change than --max-changes or --max-changes-percent allow, or with --undo, restore files changed after the run")
+	options := processorFlags(flags)
+	gitFileFlags(flags, options)
+	flags.IntVar(&options.MaxChangedFiles, "max-changes", 0, "Ask before modifying more than this many files, or refuse without a terminal (overrides MaxChangedFiles)")
+	flags.Float64Var(&options.MaxChangedPercent, "max-changes-percent", 0, "Ask before modifying more than this percentage of the files, or refuse without a terminal (overrides MaxChangedPercent)")
+	logging := logFlags(flags)
+	profiling := profileFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	// A check is a dry run that fails when any header would change
+	if check {
+		dryRun = true
+		errorOnDiff = true
+	}
+
+	// JSON output is the JSON report on stdout, with the verbose output and
+	// the summary on stderr
+	options.Stdout = os.Stdout
+	if jsonOutput {
+		if reports.toStdout() {
+			msg.Fprintf(os.Stderr, "--output json cannot be used with a --report written to stdout\n")
+			return exitUsage
+		}
+		reports = append(reports, reportSpec{format: "json", path: "-"})
+		options.Stdout = os.Stderr
+	}
 
 	// Convert to absolute path
 	absPath, err := filepath.Abs(targetDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
-		os.Exit(1)
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
 	}
 
 	// Check if the directory exists
 	info, err := os.Stat(absPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error accessing directory %s: %v\n", absPath, err)
-		os.Exit(1)
+		msg.Fprintf(os.Stderr, "Error accessing directory %s: %v\n", absPath, err)
+		return exitUsage
 	}
 
 	if !info.IsDir() {
-		fmt.Fprintf(os.Stderr, "%s is not a directory\n", absPath)
-		os.Exit(1)
+		msg.Fprintf(os.Stderr, "%s is not a directory\n", absPath)
+		return exitUsage
+	}
+
+	if filesFrom != "" {
+		if archivePath != "" {
+			msg.Fprintf(os.Stderr, "--files-from cannot be used with --archive\n")
+			return exitUsage
+		}
+		if options.Files, err = readFiles(filesFrom); err != nil {
+			msg.Fprintf(os.Stderr, "Error reading file list: %v\n", err)
+			return exitUsage
+		}
+	}
+	if progress && archivePath != "" {
+		msg.Fprintf(os.Stderr, "--progress cannot be used with --archive\n")
+		return exitUsage
+	}
+	if !gitFilesAllowed(options, filesFrom != "" || archivePath != "" || watch) {
+		return exitUsage
+	}
+	if moved && remove {
+		msg.Fprintf(os.Stderr, "--moved cannot be used with --remove\n")
+		return exitUsage
+	}
+	if watch && (archivePath != "" || check) {
+		msg.Fprintf(os.Stderr, "--watch cannot be used with --archive or --check\n")
+		return exitUsage
+	}
+	if metricsAddr != "" && !watch {
+		msg.Fprintf(os.Stderr, "--metrics-listen requires --watch\n")
+		return exitUsage
+	}
+
+	// Start profiling before anything expensive happens
+	stopProfiles, err := profiling.start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return exitUsage
+	}
+	defer stopProfiles()
+
+	// Open the structured log
+	logWriter, err := logging.open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return exitUsage
+	}
+	if logWriter != nil {
+		defer logWriter.Close()
+		options.Log = logWriter
 	}
 
 	// Create processor with options
-	p := processor.NewProcessor(absPath, &processor.Options{
-		DryRun:        dryRun,
-		ConfigFile:    configFilePath,
-		Verbose:       verbose,
-		IncludeHidden: includeHidden,
-	})
+	options.DryRun = dryRun
+	options.Verbose = verbose
+	options.Remove = remove
+	options.MovedOnly = moved
+	options.Backup = backup
+	options.History = true
+	options.Diffs = reports.needsDiffs()
+	options.PrintDiffs = printDiffs
+	options.Confirm = confirmChanges
+	if yes {
+		options.Confirm = func(changed, total int) bool { return true }
+	}
+	p := processor.NewProcessor(absPath, options)
+	if p.ConfigError() != nil {
+		// NewProcessor has already reported the error
+		return exitUsage
+	}
+	if undo {
+		return undoLastRun(p, absPath, verbose, yes)
+	}
+
+	// Process the archive or the directory
+	ctx, stop := interruptContext()
+	defer stop()
+	var stats models.Stats
+	if archivePath != "" {
+		if outPath == "" && !dryRun {
+			msg.Fprintf(os.Stderr, "--out is required with --archive\n")
+			return exitUsage
+		}
+		stats, err = p.ProcessArchiveContext(ctx, archivePath, outPath)
+		if runFailed(err) && !errors.Is(err, processor.ErrStopped) && !errors.Is(err, context.Canceled) {
+			msg.Fprintf(os.Stderr, "Error processing archive: %v\n", err)
+			return exitFailure
+		}
+	} else {
+		var bar *progressBar
+		if progress {
+			bar = startProgress(ctx, p, options)
+		}
+		if ghSummary && verbose {
+			fmt.Fprintf(options.Stdout, "::group::pathfix %s\n", absPath)
+		}
+		stats, err = p.ProcessContext(ctx)
+		if ghSummary && verbose {
+			fmt.Fprintf(options.Stdout, "::endgroup::\n")
+		}
+		if bar != nil {
+			bar.finish()
+		}
+		if errors.Is(err, processor.ErrTooManyChanges) {
+			msg.Fprintf(os.Stderr, "Error: %v\n", err)
+			msg.Fprintf(os.Stderr, "No files were modified. Check the configuration, or pass --yes to modify them anyway.\n")
+			return exitFailure
+		}
+		if runFailed(err) && !errors.Is(err, processor.ErrStopped) && !errors.Is(err, context.Canceled) {
+			msg.Fprintf(os.Stderr, "Error processing directory: %v\n", err)
+			return exitFailure
+		}
+	}
+
+	// With --fail-fast or an interrupt, the summary covers the files
+	// processed before the run stopped
+	stopped := errors.Is(err, processor.ErrStopped)
+	if stopped {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	interrupted := errors.Is(err, context.Canceled)
+
+	if err := reports.write(p); err != nil {
+		msg.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return exitFailure
+	}
+	if ghSummary {
+		if err := writeGitHubSummary(p, topDirs); err != nil {
+			msg.Fprintf(os.Stderr, "Error writing GitHub summary: %v\n", err)
+			return exitFailure
+		}
+	}
 
-	// Process the directory
-	stats, err := p.Process()
+	// Print summary, out of the way of a report on stdout
+	summary := os.Stdout
+	if reports.toStdout() {
+		summary = os.Stderr
+	}
+	if check || moved {
+		printUpdatedFiles(summary, p.Results())
+	}
+	msg.Fprintf(summary, "Processed %d files (%d updated, %d skipped, %d errors, %d warnings)\n",
+		stats.Processed, stats.Updated, stats.Skipped, stats.Errors, stats.Warnings)
+	printChangeSize(summary, stats)
+	if stats.Reverted > 0 {
+		msg.Fprintf(summary, "%d files failed validation and were restored\n", stats.Reverted)
+	}
+	if topDirs > 0 {
+		printTopDirectories(summary, p.DirectoryStats(-1, topDirs))
+	}
+
+	switch {
+	case check && stats.Updated > 0:
+		msg.Fprintf(summary, "Check failed: %d of %d files need their header added or updated.\n", stats.Updated, stats.Processed)
+	case check:
+		msg.Fprintf(summary, "Check passed: every header is up to date.\n")
+	case dryRun:
+		msg.Fprintf(summary, "This was a dry run. No files were modified.\n")
+	}
+	if stopped {
+		msg.Fprintf(summary, "Stopped at the first error (--fail-fast); the remaining files were not processed.\n")
+		return exitFailure
+	}
+	if interrupted {
+		msg.Fprintf(summary, "Interrupted; the files in progress were finished, and the remaining files were not processed.\n")
+		return exitFailure
+	}
+	if watch {
+		return watchFiles(ctx, p, options, absPath, verbose, metricsAddr)
+	}
+
+	// Apply the error threshold
+	failures := stats.Errors
+	if strict {
+		failures += stats.Warnings
+	}
+	if maxErrors >= 0 && failures > maxErrors {
+		msg.Fprintf(os.Stderr, "%d files with errors exceed --max-errors %d\n", failures, maxErrors)
+		return exitFailure
+	}
+	if errorOnDiff && stats.Updated > 0 {
+		return exitChanges
+	}
+	return exitClean
+}
+
+// interruptContext returns a context that the first interrupt or
+// termination signal cancels, so that a run can stop between files. Signals
+// after that end the process as usual.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// confirmChanges asks whether to go ahead with a run that exceeds the change
+// limits. Without a terminal to ask on, the run is refused.
+func confirmChanges(changed, total int) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+	msg.Fprintf(os.Stderr, "%d of %d files would be modified. Continue? [y/N] ", changed, total)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// printUpdatedFiles lists the files with a missing or stale header, with
+// the reason for each and the path a stale header named, if another one
+func printUpdatedFiles(w io.Writer, results []models.FileResult) {
+	for _, result := range results {
+		switch {
+		case result.Action != models.ActionUpdated:
+		case result.OldPath != "":
+			msg.Fprintf(w, "%s: %s (was %s)\n", result.Path, result.Reason, result.OldPath)
+		default:
+			fmt.Fprintf(w, "%s: %s\n", result.Path, result.Reason)
+		}
+	}
+}
+
+// printChangeSize prints how many lines and bytes the updates change, if any
+func printChangeSize(w io.Writer, stats models.Stats) {
+	if stats.Updated == 0 {
+		return
+	}
+	msg.Fprintf(w, "  %d lines added, %d removed, %d rewritten (%d bytes added, %d removed)\n",
+		stats.LinesAdded, stats.LinesRemoved, stats.LinesRewritten, stats.BytesAdded, stats.BytesRemoved)
+}
+
+// runFailed reports whether err from a processor run means the run did not
+// complete, as opposed to only some files failing
+func runFailed(err error) bool {
+	var fileErrors processor.FileErrors
+	return err != nil && !errors.As(err, &fileErrors)
+}
+
+// readFiles reads the paths listed in a file, or stdin for "-", resolved
+// against the current directory. An empty list processes no files.
+func readFiles(name string) ([]string, error) {
+	lines, err := readLines(name)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error processing directory: %v\n", err)
-		os.Exit(1)
+		return nil, err
+	}
+	files := make([]string, 0, len(lines))
+	for _, line := range lines {
+		path, err := filepath.Abs(line)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, path)
 	}
+	return files, nil
+}
 
-	// Print summary
-	fmt.Printf("Processed %d files (%d updated, %d skipped, %d errors)\n",
-		stats.Processed, stats.Updated, stats.Skipped, stats.Errors)
+// gitFileFlags registers the flags that take the files to process from git
+func gitFileFlags(flags *flag.FlagSet, options *processor.Options) {
+	flags.BoolVar(&options.Staged, "staged", false, "Process only the files staged for commit, from git, instead of walking the directory")
+	flags.BoolVar(&options.Tracked, "tracked", false, "Process only the files git tracks instead of walking the directory")
+}
 
-	if dryRun {
-		fmt.Println("This was a dry run. No files were modified.")
+// gitFilesAllowed reports a usage error and returns false if --staged and
+// --tracked are combined with each other or, when listed is set, with
+// another way of choosing the files
+func gitFilesAllowed(options *processor.Options, listed bool) bool {
+	if options.Staged && options.Tracked || (options.Staged || options.Tracked) && listed {
+		msg.Fprintf(os.Stderr, "--staged and --tracked cannot be combined with each other, --files-from, --archive or --watch\n")
+		return false
 	}
-}
\ No newline at end of file
+	return true
+}
+
+// processorFlags registers the flags shared by every command that runs the
+// processor and returns the options they populate
+func processorFlags(flags *flag.FlagSet) *processor.Options {
+	options := &processor.Options{}
+	flags.StringVar(&options.ConfigFile, "config", "", "Path to custom configuration file (default: .pathfix.json or .pathfix.yaml in the target directory or above it, up to the top of its git work tree)")
+	flags.StringVar(&options.StateDir, "state-dir", "", "Directory for the run history and other local state (default .pathfix in the target directory)")
+	flags.BoolVar(&options.AllowCommands, "allow-commands", false, "Run the Hooks, Plugins and Validators of a configuration file found in the target directory or above it, rather than named with --config, and let its LicenseFile be outside the target directory")
+	flags.BoolVar(&options.UserConfig, "user-config", true, "Load personal defaults from pathfix/config.json (or config.yaml) in the user config directory ($XDG_CONFIG_HOME or ~/.config)")
+	flags.BoolVar(&options.IncludeHidden, "include-hidden", false, "Process hidden files and directories")
+	flags.BoolVar(&options.GitIgnoreOnly, "gitignore-only", false, "Skip only the files .gitignore ignores, not those of .git/info/exclude or git's core.excludesFile")
+	flags.BoolVar(&options.IncludeDocs, "include-docs", false, "Process documentation files (.md, .markdown, .mdx, .rst, .adoc)")
+	flags.Func("lang", "Only process files of these comma-separated `languages`, such as go,python, or extensions such as .vue (overrides Languages)", languageList(&options.Languages))
+	flags.Func("exclude-lang", "Never process files of these comma-separated `languages` (overrides ExcludeLanguages)", languageList(&options.ExcludeLanguages))
+	flags.BoolVar(&options.IncludeExecutables, "include-executables", false, "Process executable files without an extension, choosing the comment style from the shebang's interpreter")
+	flags.BoolVar(&options.AutoDetect, "auto-detect", false, "Process files of unknown type whose content shows their language, such as a shebang, an editor mode line or an XML prolog")
+	flags.Func("style", "Comment `kind` for the headers of every type that supports both: line or block (overrides Style)", func(style string) error {
+		if style != "line" && style != "block" {
+			return errors.New(`must be "line" or "block"`)
+		}
+		options.Style = style
+		return nil
+	})
+	flags.BoolVar(&options.AfterLicense, "after-license", false, "Put each header below a license or copyright comment at the top of the file instead of above it")
+	flags.BoolVar(&options.MatchCommentStyle, "match-style", false, "Write line or block headers as each file's existing header or comments do, for types that support both")
+	flags.IntVar(&options.SampleSize, "binary-sample-size", 0, "Bytes inspected when detecting binary files (default 8000)")
+	flags.BoolVar(&options.DetectContentType, "detect-content-type", false, "Skip images, archives, executables and PDFs recognized by content signature")
+	flags.BoolVar(&options.FollowSymlinks, "follow-symlinks", false, "Follow symbolic links and junctions instead of skipping them")
+	flags.StringVar(&options.CreatedAfter, "created-after", "", "Only process files git did not track yet at this `date` (YYYY-MM-DD) or revision (overrides CreatedAfter)")
+	flags.BoolVar(&options.IgnoreCase, "ignore-case", false, "Match ignore patterns and header paths case-insensitively")
+	flags.Func("workers", "Number of `files` to process in parallel; 0 uses one per CPU (default 1)", func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return errors.New("must be a number of files, or 0 for one per CPU")
+		}
+		if n == 0 {
+			n = runtime.NumCPU()
+		}
+		options.Workers = n
+		return nil
+	})
+	flags.Var(errorPolicyFlag{&options.FailFast, true}, "fail-fast", "Stop at the first file that fails")
+	flags.Var(errorPolicyFlag{&options.FailFast, false}, "keep-going", "Process the remaining files after a failure (default)")
+	return options
+}
+
+// languageList returns a flag function that adds comma-separated language
+// names or extensions to list, rejecting unknown names
+func languageList(list *[]string) func(string) error {
+	return func(s string) error {
+		known := processor.LanguageNames()
+		for _, name := range strings.Split(s, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if i := sort.SearchStrings(known, name); !strings.HasPrefix(name, ".") && (i == len(known) || known[i] != name) {
+				return fmt.Errorf("unknown language %q (expected one of %s, or an extension such as .go)", name, strings.Join(known, ", "))
+			}
+			*list = append(*list, name)
+		}
+		return nil
+	}
+}
+
+// errorPolicyFlag is a boolean flag that sets the fail-fast option when
+// given, so the last of --fail-fast and --keep-going wins
+type errorPolicyFlag struct {
+	failFast *bool
+	value    bool // The fail-fast setting this flag selects
+}
+
+func (f errorPolicyFlag) String() string {
+	return ""
+}
+
+func (f errorPolicyFlag) Set(s string) error {
+	enabled, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		*f.failFast = f.value
+	}
+	return nil
+}
+
+func (f errorPolicyFlag) IsBoolFlag() bool {
+	return true
+}
@@ -0,0 +1,162 @@
+// File: multi.go
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// runMulti fixes the headers of several independent directory trees in one
+// invocation, each with its own config and statistics
+func runMulti(args []string) int {
+	var (
+		rootsFrom   string
+		parallel    int
+		dryRun      bool
+		verbose     bool
+		ordered     bool
+		reportPath  string
+		maxErrors   int
+		errorOnDiff bool
+		yes         bool
+	)
+
+	flags := flag.NewFlagSet("multi", flag.ContinueOnError)
+	flags.StringVar(&rootsFrom, "roots-from", "", "Read more root directories from this file, one per line (- for stdin)")
+	flags.IntVar(&parallel, "parallel", runtime.NumCPU(), "Number of roots to process at once")
+	flags.BoolVar(&dryRun, "dry-run", false, "Preview changes without modifying files")
+	flags.BoolVar(&verbose, "verbose", false, "Enable verbose output")
+	flags.BoolVar(&ordered, "ordered", false, "Print each root's verbose output once the roots before it are done, so roots appear in the order given")
+	flags.StringVar(&reportPath, "report", "", "Write the combined JSON report to this file (- for stdout)")
+	flags.IntVar(&maxErrors, "max-errors", 0, "Exit with status 2 if more files than this fail over all roots (-1 for no limit)")
+	flags.BoolVar(&errorOnDiff, "error-on-diff", false, "Exit with status 1 if any header was changed, or would be in a dry run")
+	flags.BoolVar(&yes, "yes", false, "Modify files even when more would change than --max-changes or --max-changes-percent allow")
+	options := processorFlags(flags)
+	flags.IntVar(&options.MaxChangedFiles, "max-changes", 0, "Refuse to modify more than this many files in a root (overrides MaxChangedFiles)")
+	flags.Float64Var(&options.MaxChangedPercent, "max-changes-percent", 0, "Refuse to modify more than this percentage of a root's files (overrides MaxChangedPercent)")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	roots := flags.Args()
+	if rootsFrom != "" {
+		more, err := readLines(rootsFrom)
+		if err != nil {
+			msg.Fprintf(os.Stderr, "Error reading roots: %v\n", err)
+			return exitUsage
+		}
+		roots = append(roots, more...)
+	}
+	if len(roots) == 0 {
+		msg.Fprintf(os.Stderr, "Usage: pathfix multi [flags] <dir>...\n")
+		return exitUsage
+	}
+	for i, root := range roots {
+		absPath, err := filepath.Abs(root)
+		if err != nil {
+			msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", root, err)
+			return exitUsage
+		}
+		roots[i] = absPath
+	}
+
+	// Each root is a separate run; there is no terminal prompt for the change
+	// limits, as the roots are processed concurrently
+	options.DryRun = dryRun
+	options.Verbose = verbose
+	options.OrderedOutput = ordered
+	options.History = true
+	if yes {
+		options.Confirm = func(changed, total int) bool { return true }
+	}
+	ctx, stop := interruptContext()
+	defer stop()
+	report := processor.ProcessRootsContext(ctx, roots, *options, parallel)
+
+	if reportPath != "" {
+		var out io.Writer = os.Stdout
+		if reportPath != "-" {
+			file, err := os.Create(reportPath)
+			if err != nil {
+				msg.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+				return exitFailure
+			}
+			defer file.Close()
+			out = file
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			msg.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			return exitFailure
+		}
+	}
+
+	// With the report on stdout, the summary goes to stderr
+	summary := os.Stdout
+	if reportPath == "-" {
+		summary = os.Stderr
+	}
+	failed := 0
+	for _, root := range report.Roots {
+		stats := root.Stats
+		msg.Fprintf(summary, "%s: processed %d files (%d updated, %d skipped, %d errors, %d warnings)\n",
+			root.Root, stats.Processed, stats.Updated, stats.Skipped, stats.Errors, stats.Warnings)
+		if root.Failed {
+			failed++
+			msg.Fprintf(summary, "  failed: %s\n", root.Error)
+		}
+	}
+	stats := report.Stats
+	msg.Fprintf(summary, "Total over %d roots: processed %d files (%d updated, %d skipped, %d errors, %d warnings)\n",
+		len(report.Roots), stats.Processed, stats.Updated, stats.Skipped, stats.Errors, stats.Warnings)
+	printChangeSize(summary, stats)
+	if dryRun {
+		msg.Fprintf(summary, "This was a dry run. No files were modified.\n")
+	}
+
+	if failed > 0 {
+		msg.Fprintf(os.Stderr, "%d of %d roots failed\n", failed, len(report.Roots))
+		return exitFailure
+	}
+	if maxErrors >= 0 && stats.Errors > maxErrors {
+		msg.Fprintf(os.Stderr, "%d files with errors exceed --max-errors %d\n", stats.Errors, maxErrors)
+		return exitFailure
+	}
+	if errorOnDiff && stats.Updated > 0 {
+		return exitChanges
+	}
+	return exitClean
+}
+
+// readLines reads names, one per line, from a file or from stdin for "-",
+// skipping blank lines and lines starting with #
+func readLines(name string) ([]string, error) {
+	var in io.Reader = os.Stdin
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
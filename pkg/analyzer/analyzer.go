@@ -0,0 +1,163 @@
+// File: pkg/analyzer/analyzer.go
+
+// Package analyzer reports Go files whose pathfix header is missing or
+// stale, for use with go vet and other go/analysis drivers
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// Analyzer reports Go files that pathfix would change, with a suggested fix
+// that adds or updates the header
+var Analyzer = &analysis.Analyzer{
+	Name: "pathfix",
+	Doc: `check that Go files carry an up-to-date pathfix header
+
+Header paths are relative to the root directory: -root if given, or else the
+nearest directory above each file with a .pathfix.json, or else with a go.mod.
+The root's .pathfix.json is used unless -config names a config file.`,
+	Run: run,
+}
+
+var (
+	rootFlag   string
+	configFlag string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&rootFlag, "root", "", "Directory that header paths are relative to")
+	Analyzer.Flags.StringVar(&configFlag, "config", "", "Path to the configuration file")
+}
+
+// processors holds a processor per root, shared by the packages analyzed in
+// the same process
+var processors = struct {
+	sync.Mutex
+	byRoot map[string]*processor.Processor
+}{byRoot: make(map[string]*processor.Processor)}
+
+// processorFor returns the processor for a root, loading its config once
+func processorFor(root string) (*processor.Processor, error) {
+	processors.Lock()
+	defer processors.Unlock()
+	if p, ok := processors.byRoot[root]; ok {
+		return p, p.ConfigError()
+	}
+	// Without -config, the processor finds the root's own, as findRoot
+	// stopped at the nearest directory holding one
+	p := processor.NewProcessor(root, &processor.Options{ConfigFile: configFlag, Stderr: io.Discard})
+	processors.byRoot[root] = p
+	return p, p.ConfigError()
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		tokenFile := pass.Fset.File(file.Pos())
+		if tokenFile == nil {
+			continue
+		}
+		filename := tokenFile.Name()
+		if filepath.Ext(filename) != ".go" {
+			continue
+		}
+
+		root := rootFlag
+		if root == "" {
+			root = findRoot(filepath.Dir(filename))
+		}
+		if root == "" {
+			continue
+		}
+		root, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		relPath, err := filepath.Rel(root, filename)
+		if err != nil || !filepath.IsLocal(relPath) {
+			// Files outside the root, such as cgo output, are not checked
+			continue
+		}
+
+		p, err := processorFor(root)
+		if err != nil {
+			return nil, fmt.Errorf("loading pathfix config: %w", err)
+		}
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		if len(content) != tokenFile.Size() {
+			// The file changed since it was parsed
+			continue
+		}
+
+		fixed, result := p.FixBuffer(relPath, content)
+		switch result.Action {
+		case models.ActionUpdated:
+			start, end, text := textEdit(content, fixed)
+			message := "missing pathfix header"
+			fix := "Add the pathfix header"
+			if result.Reason == models.ReasonStaleHeader {
+				message = "stale pathfix header"
+				fix = "Update the pathfix header"
+			}
+			pos, endPos := tokenFile.Pos(start), tokenFile.Pos(end)
+			pass.Report(analysis.Diagnostic{
+				Pos:     tokenFile.LineStart(tokenFile.Line(pos)),
+				Message: message,
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   fix,
+					TextEdits: []analysis.TextEdit{{Pos: pos, End: endPos, NewText: text}},
+				}},
+			})
+		case models.ActionError:
+			pass.Reportf(file.Pos(), "pathfix: %s", result.Error)
+		}
+	}
+	return nil, nil
+}
+
+// textEdit returns the single replacement that turns old into new: the
+// byte range of old that differs and the text that replaces it
+func textEdit(old, new []byte) (start, end int, text []byte) {
+	for start < len(old) && start < len(new) && old[start] == new[start] {
+		start++
+	}
+	suffix := 0
+	for suffix < len(old)-start && suffix < len(new)-start && old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+	return start, len(old) - suffix, bytes.Clone(new[start : len(new)-suffix])
+}
+
+// findRoot returns the nearest directory from dir upwards with a
+// .pathfix.json, or else with a go.mod, or "" if there is neither
+func findRoot(dir string) string {
+	module := ""
+	for {
+		if processor.DiscoverConfig(dir) != "" {
+			return dir
+		}
+		if module == "" {
+			if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+				module = dir
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return module
+		}
+		dir = parent
+	}
+}
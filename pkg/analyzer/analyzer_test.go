@@ -0,0 +1,37 @@
+// File: pkg/analyzer/analyzer_test.go
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	if err := Analyzer.Flags.Set("root", filepath.Join(testdata, "src")); err != nil {
+		t.Fatalf("Failed to set -root: %v", err)
+	}
+	defer Analyzer.Flags.Set("root", "")
+
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "a")
+}
+
+func TestTextEdit(t *testing.T) {
+	testCases := []struct {
+		old, new   string
+		start, end int
+		text       string
+	}{
+		{"package a\n", "// File: a.go\npackage a\n", 0, 0, "// File: a.go\n"},
+		{"// File: b.go\npackage a\n", "// File: a.go\npackage a\n", 9, 10, "a"},
+		{"x", "x", 1, 1, ""},
+	}
+	for _, tc := range testCases {
+		start, end, text := textEdit([]byte(tc.old), []byte(tc.new))
+		if start != tc.start || end != tc.end || string(text) != tc.text {
+			t.Errorf("textEdit(%q, %q) = %d, %d, %q, expected %d, %d, %q", tc.old, tc.new, start, end, text, tc.start, tc.end, tc.text)
+		}
+	}
+}
@@ -0,0 +1 @@
+package a // want "missing pathfix header"
@@ -0,0 +1,3 @@
+// File: old.go // want "stale pathfix header"
+
+package a
@@ -0,0 +1,3 @@
+// File: a/c.go
+
+package a
@@ -0,0 +1,191 @@
+// File: pkg/bot/bot.go
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// Defaults for Options
+const (
+	DefaultRemote = "origin"
+	DefaultBranch = "pathfix/headers"
+	DefaultTitle  = "Add file path headers"
+)
+
+// maxListedFiles caps the file list in the pull request description
+const maxListedFiles = 200
+
+// Options configures a bot run
+type Options struct {
+	Provider  string // ProviderGitHub or ProviderGitLab
+	APIURL    string // API base URL; defaults to the provider's public instance
+	Repo      string // owner/name on GitHub, project path or ID on GitLab
+	Token     string // API token used to open the pull request
+	Remote    string // Git remote the branch is pushed to
+	Branch    string // Branch created for the fixes
+	Base      string // Branch the pull request targets; defaults to the current branch, and is required when HEAD is detached
+	Title     string // Commit message and pull request title
+	Processor processor.Options
+}
+
+// Result describes what a bot run did
+type Result struct {
+	Stats  models.Stats // Statistics of the fixing run, or of the check if nothing needed fixing
+	Branch string       // Branch pushed with the fixes, if any
+	URL    string       // Web URL of the opened pull request, if any
+}
+
+// Run checks the git work tree containing dir and, if any headers are
+// missing or stale, commits the fixes to a new branch, pushes it and opens
+// a pull request. The work tree must be clean; the original branch is
+// checked out again afterwards. If fixing, committing or pushing fails, the
+// changes and the new branch are discarded.
+func Run(dir string, options Options) (Result, error) {
+	var result Result
+	if options.Remote == "" {
+		options.Remote = DefaultRemote
+	}
+	if options.Branch == "" {
+		options.Branch = DefaultBranch
+	}
+	if options.Title == "" {
+		options.Title = DefaultTitle
+	}
+	provider, err := newProvider(options)
+	if err != nil {
+		return result, err
+	}
+
+	// Refuse to mix the fixes with uncommitted work
+	status, err := git(dir, "status", "--porcelain")
+	if err != nil {
+		return result, err
+	}
+	if status != "" {
+		return result, fmt.Errorf("work tree has uncommitted changes")
+	}
+	current, err := git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return result, err
+	}
+	if current == "HEAD" {
+		// A detached HEAD, as in many CI checkouts, names no branch for the
+		// pull request to target, and is restored by its commit
+		if options.Base == "" {
+			return result, fmt.Errorf("HEAD is detached; pass --base to name the branch the pull request targets")
+		}
+		if current, err = git(dir, "rev-parse", "HEAD"); err != nil {
+			return result, err
+		}
+	}
+	if options.Base == "" {
+		options.Base = current
+	}
+
+	// Check first so nothing is touched when the tree is clean
+	checkOptions := options.Processor
+	checkOptions.DryRun = true
+	// Files that fail are reported in the summary rather than blocking the fixes
+	var fileErrors processor.FileErrors
+	result.Stats, err = processor.NewProcessor(dir, &checkOptions).Process()
+	if err != nil && !errors.As(err, &fileErrors) {
+		return result, err
+	}
+	if result.Stats.Updated == 0 {
+		return result, nil
+	}
+
+	if _, err := git(dir, "checkout", "-b", options.Branch); err != nil {
+		return result, err
+	}
+	pushed := false
+	defer func() {
+		if !pushed {
+			// Neither the fixes nor the branch may follow the user back
+			git(dir, "reset", "-q", "--hard")
+		}
+		git(dir, "checkout", "-q", current)
+		if !pushed {
+			git(dir, "branch", "-q", "-D", options.Branch)
+		}
+	}()
+
+	fixOptions := options.Processor
+	fixOptions.DryRun = false
+	p := processor.NewProcessor(dir, &fixOptions)
+	result.Stats, err = p.Process()
+	if err != nil && !errors.As(err, &fileErrors) {
+		return result, err
+	}
+	var updated []string
+	for _, r := range p.Results() {
+		if r.Action == models.ActionUpdated {
+			updated = append(updated, r.Path)
+		}
+	}
+	if len(updated) == 0 {
+		return result, nil
+	}
+
+	if _, err := git(dir, append([]string{"add", "--"}, updated...)...); err != nil {
+		return result, err
+	}
+	if _, err := git(dir, "commit", "-m", options.Title); err != nil {
+		return result, err
+	}
+	if _, err := git(dir, "push", options.Remote, options.Branch); err != nil {
+		return result, err
+	}
+	pushed = true
+	result.Branch = options.Branch
+
+	result.URL, err = provider.createPullRequest(pullRequest{
+		Title: options.Title,
+		Body:  Summary(result.Stats, updated),
+		Head:  options.Branch,
+		Base:  options.Base,
+	})
+	return result, err
+}
+
+// Summary describes a fixing run for a pull request description
+func Summary(stats models.Stats, updated []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "pathfix found %d files with missing or stale path headers and updated them.\n\n", stats.Updated)
+	fmt.Fprintf(&sb, "| Processed | Updated | Skipped | Errors |\n|---|---|---|---|\n| %d | %d | %d | %d |\n",
+		stats.Processed, stats.Updated, stats.Skipped, stats.Errors)
+	if stats.Errors > 0 {
+		sb.WriteString("\nSome files could not be processed; run pathfix locally with --verbose for details.\n")
+	}
+
+	sb.WriteString("\n<details>\n<summary>Updated files</summary>\n\n")
+	for i, path := range updated {
+		if i == maxListedFiles {
+			fmt.Fprintf(&sb, "- ... and %d more\n", len(updated)-maxListedFiles)
+			break
+		}
+		fmt.Fprintf(&sb, "- `%s`\n", path)
+	}
+	sb.WriteString("\n</details>\n")
+	return sb.String()
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
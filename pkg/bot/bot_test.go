@@ -0,0 +1,214 @@
+// File: pkg/bot/bot_test.go
+package bot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestProviders(t *testing.T) {
+	tests := []struct {
+		provider string
+		path     string
+		auth     string
+		response string
+		fields   map[string]string
+	}{
+		{
+			provider: ProviderGitHub,
+			path:     "/repos/acme/app/pulls",
+			auth:     "Authorization",
+			response: `{"html_url": "https://example.com/pr/1"}`,
+			fields:   map[string]string{"head": "fix", "base": "main", "title": "Title"},
+		},
+		{
+			provider: ProviderGitLab,
+			path:     "/projects/acme%2Fapp/merge_requests",
+			auth:     "Private-Token",
+			response: `{"web_url": "https://example.com/pr/1"}`,
+			fields:   map[string]string{"source_branch": "fix", "target_branch": "main", "title": "Title"},
+		},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.EscapedPath() != test.path {
+				t.Errorf("%s: request to %s, expected %s", test.provider, r.URL.EscapedPath(), test.path)
+			}
+			if !strings.Contains(r.Header.Get(test.auth), "secret") {
+				t.Errorf("%s: %s header = %q, expected the token", test.provider, test.auth, r.Header.Get(test.auth))
+			}
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			for key, want := range test.fields {
+				if body[key] != want {
+					t.Errorf("%s: request %s = %q, expected %q", test.provider, key, body[key], want)
+				}
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(test.response))
+		}))
+
+		p, err := newProvider(Options{Provider: test.provider, APIURL: server.URL, Repo: "acme/app", Token: "secret"})
+		if err != nil {
+			t.Fatalf("newProvider(%s) failed: %v", test.provider, err)
+		}
+		url, err := p.createPullRequest(pullRequest{Title: "Title", Body: "Body", Head: "fix", Base: "main"})
+		if err != nil || url != "https://example.com/pr/1" {
+			t.Errorf("%s: createPullRequest() = %q, %v, expected the pull request URL", test.provider, url, err)
+		}
+		server.Close()
+	}
+}
+
+func TestProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Validation Failed"}`, http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	p, _ := newProvider(Options{Provider: ProviderGitHub, APIURL: server.URL, Repo: "acme/app", Token: "secret"})
+	if _, err := p.createPullRequest(pullRequest{}); err == nil || !strings.Contains(err.Error(), "Validation Failed") {
+		t.Errorf("createPullRequest() error = %v, expected the API message", err)
+	}
+
+	if _, err := newProvider(Options{Provider: "bitbucket", Repo: "acme/app", Token: "secret"}); err == nil {
+		t.Errorf("newProvider(bitbucket) succeeded, expected an error")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	updated := make([]string, maxListedFiles+5)
+	for i := range updated {
+		updated[i] = "file.go"
+	}
+	summary := Summary(models.Stats{Processed: 300, Updated: len(updated), Errors: 1}, updated)
+	for _, want := range []string{"205 files", "| 300 | 205 | 0 | 1 |", "could not be processed", "and 5 more"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary() = %q, expected it to contain %q", summary, want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tempDir, err := os.MkdirTemp("", "pathfix-bot-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	t.Setenv("GIT_AUTHOR_NAME", "pathfix")
+	t.Setenv("GIT_AUTHOR_EMAIL", "pathfix@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "pathfix")
+	t.Setenv("GIT_COMMITTER_EMAIL", "pathfix@example.com")
+
+	remote := filepath.Join(tempDir, "remote.git")
+	repo := filepath.Join(tempDir, "repo")
+	setup := [][]string{
+		{"init", "-q", "--bare", remote},
+		{"init", "-q", "-b", "main", repo},
+		{"-C", repo, "remote", "add", "origin", remote},
+	}
+	for _, args := range setup {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", "initial"}} {
+		if _, err := git(repo, args...); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	var request map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"html_url": "https://example.com/pr/1"}`))
+	}))
+	defer server.Close()
+
+	options := Options{Provider: ProviderGitHub, APIURL: server.URL, Repo: "acme/app", Token: "secret"}
+	result, err := Run(repo, options)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.URL != "https://example.com/pr/1" || result.Branch != DefaultBranch || result.Stats.Updated != 1 {
+		t.Errorf("Run() = %+v, expected one file fixed on %s", result, DefaultBranch)
+	}
+	if request["base"] != "main" || !strings.Contains(request["body"], "`main.go`") {
+		t.Errorf("pull request = %v, expected main.go fixed against main", request)
+	}
+
+	// The fix is pushed, and the original branch is left untouched
+	content, err := git(repo, "show", "origin/"+DefaultBranch+":main.go")
+	if err != nil || content != "// File: main.go\npackage main" {
+		t.Errorf("pushed main.go = %q, %v, expected the header", content, err)
+	}
+	if branch, _ := git(repo, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("Run() left %s checked out, expected main", branch)
+	}
+
+	// A clean tree needs no pull request
+	if _, err := git(repo, "merge", "-q", DefaultBranch); err != nil {
+		t.Fatalf("%v", err)
+	}
+	options.Branch = "pathfix/second"
+	result, err = Run(repo, options)
+	if err != nil || result.URL != "" || result.Stats.Updated != 0 {
+		t.Errorf("Run() on a fixed tree = %+v, %v, expected nothing to do", result, err)
+	}
+
+	// A failed push leaves neither the fix nor the branch behind
+	if err := os.WriteFile(filepath.Join(repo, "util.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create util.go: %v", err)
+	}
+	for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", "util"}} {
+		if _, err := git(repo, args...); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	failing := options
+	failing.Remote = filepath.Join(tempDir, "missing.git")
+	if _, err := Run(repo, failing); err == nil {
+		t.Errorf("Run() with a missing remote succeeded, expected an error")
+	}
+	if status, _ := git(repo, "status", "--porcelain"); status != "" {
+		t.Errorf("Run() with a failed push left changes: %q", status)
+	}
+	if branches, _ := git(repo, "branch", "--list", failing.Branch); branches != "" {
+		t.Errorf("Run() with a failed push left branch %s", branches)
+	}
+	if branch, _ := git(repo, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("Run() with a failed push left %s checked out, expected main", branch)
+	}
+
+	// A detached HEAD needs the base named, and is detached again afterwards
+	if _, err := git(repo, "checkout", "-q", "--detach"); err != nil {
+		t.Fatalf("%v", err)
+	}
+	head, _ := git(repo, "rev-parse", "HEAD")
+	options.Branch = "pathfix/detached"
+	if _, err := Run(repo, options); err == nil || !strings.Contains(err.Error(), "--base") {
+		t.Errorf("Run() on a detached HEAD = %v, expected an error asking for --base", err)
+	}
+	options.Base = "main"
+	if result, err := Run(repo, options); err != nil || result.Branch != options.Branch || request["base"] != "main" {
+		t.Errorf("Run() on a detached HEAD with a base = %+v, %v, expected util.go fixed against main", result, err)
+	}
+	if current, _ := git(repo, "rev-parse", "HEAD"); current != head {
+		t.Errorf("Run() left %s checked out, expected the detached %s", current, head)
+	}
+}
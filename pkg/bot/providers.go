@@ -0,0 +1,141 @@
+// File: pkg/bot/providers.go
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported hosting providers
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Public API endpoints used when Options.APIURL is empty
+const (
+	defaultGitHubAPI = "https://api.github.com"
+	defaultGitLabAPI = "https://gitlab.com/api/v4"
+)
+
+// apiTimeout bounds each API request
+const apiTimeout = 30 * time.Second
+
+// pullRequest is a pull (or merge) request to open
+type pullRequest struct {
+	Title string
+	Body  string
+	Head  string // Branch with the changes
+	Base  string // Branch to merge into
+}
+
+// provider opens pull requests on a hosting service
+type provider interface {
+	createPullRequest(pr pullRequest) (string, error)
+}
+
+// newProvider returns the provider selected by the options
+func newProvider(options Options) (provider, error) {
+	if options.Repo == "" {
+		return nil, fmt.Errorf("repository is required")
+	}
+	if options.Token == "" {
+		return nil, fmt.Errorf("API token is required")
+	}
+	client := &http.Client{Timeout: apiTimeout}
+	apiURL := strings.TrimSuffix(options.APIURL, "/")
+
+	switch options.Provider {
+	case ProviderGitHub:
+		if apiURL == "" {
+			apiURL = defaultGitHubAPI
+		}
+		return &gitHub{client: client, apiURL: apiURL, repo: options.Repo, token: options.Token}, nil
+	case ProviderGitLab:
+		if apiURL == "" {
+			apiURL = defaultGitLabAPI
+		}
+		return &gitLab{client: client, apiURL: apiURL, project: options.Repo, token: options.Token}, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q (expected %s or %s)", options.Provider, ProviderGitHub, ProviderGitLab)
+}
+
+// gitHub opens pull requests through the GitHub REST API
+type gitHub struct {
+	client *http.Client
+	apiURL string
+	repo   string // owner/name
+	token  string
+}
+
+func (g *gitHub) createPullRequest(pr pullRequest) (string, error) {
+	body := map[string]string{"title": pr.Title, "body": pr.Body, "head": pr.Head, "base": pr.Base}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+g.token)
+	header.Set("Accept", "application/vnd.github+json")
+
+	var response struct {
+		HTMLURL string `json:"html_url"`
+	}
+	err := postJSON(g.client, g.apiURL+"/repos/"+g.repo+"/pulls", header, body, &response)
+	return response.HTMLURL, err
+}
+
+// gitLab opens merge requests through the GitLab REST API
+type gitLab struct {
+	client  *http.Client
+	apiURL  string
+	project string // Numeric ID or namespace/name
+	token   string
+}
+
+func (g *gitLab) createPullRequest(pr pullRequest) (string, error) {
+	body := map[string]string{
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", g.token)
+
+	var response struct {
+		WebURL string `json:"web_url"`
+	}
+	err := postJSON(g.client, g.apiURL+"/projects/"+url.PathEscape(g.project)+"/merge_requests", header, body, &response)
+	return response.WebURL, err
+}
+
+// postJSON posts body as JSON and decodes a successful response into out
+func postJSON(client *http.Client, endpoint string, header http.Header, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error opening pull request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("error opening pull request: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error reading pull request response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// File: pkg/i18n/de.go
+package i18n
+
+// german holds the German translations
+var german = map[string]string{
+	// Shared
+	"Error: %v\n":                                "Fehler: %v\n",
+	"Error resolving path %s: %v\n":              "Fehler beim Auflösen des Pfads %s: %v\n",
+	"Error accessing directory %s: %v\n":         "Fehler beim Zugriff auf das Verzeichnis %s: %v\n",
+	"%s is not a directory\n":                    "%s ist kein Verzeichnis\n",
+	"Error processing directory: %v\n":           "Fehler beim Verarbeiten des Verzeichnisses: %v\n",
+	"Error reading file list: %v\n":              "Fehler beim Lesen der Dateiliste: %v\n",
+	"Error processing file %s: %s\n":             "Fehler beim Verarbeiten der Datei %s: %s\n",
+	"Usage: %s [command] [flags]\n\nCommands:\n": "Aufruf: %s [Befehl] [Optionen]\n\nBefehle:\n",
+	"\nFlags:\n":                                 "\nOptionen:\n",
+
+	// fix
+	"--out is required with --archive\n":                                                  "--out ist mit --archive erforderlich\n",
+	"--files-from cannot be used with --archive\n":                                        "--files-from kann nicht mit --archive verwendet werden\n",
+	"--output json cannot be used with a --report written to stdout\n":                    "--output json kann nicht mit einem --report auf stdout verwendet werden\n",
+	"Error writing GitHub summary: %v\n":                                                  "Fehler beim Schreiben der GitHub-Zusammenfassung: %v\n",
+	"Error processing archive: %v\n":                                                      "Fehler beim Verarbeiten des Archivs: %v\n",
+	"Processed %d files (%d updated, %d skipped, %d errors, %d warnings)\n":               "%d Dateien verarbeitet (%d aktualisiert, %d übersprungen, %d Fehler, %d Warnungen)\n",
+	"  %d lines added, %d removed, %d rewritten (%d bytes added, %d removed)\n":           "  %d Zeilen hinzugefügt, %d entfernt, %d umgeschrieben (%d Bytes hinzugefügt, %d entfernt)\n",
+	"%d files failed validation and were restored\n":                                      "%d Dateien haben die Validierung nicht bestanden und wurden wiederhergestellt\n",
+	"This was a dry run. No files were modified.\n":                                       "Dies war ein Probelauf. Es wurden keine Dateien geändert.\n",
+	"Check failed: %d of %d files need their header added or updated.\n":                  "Prüfung fehlgeschlagen: bei %d von %d Dateien muss der Header ergänzt oder aktualisiert werden.\n",
+	"Check passed: every header is up to date.\n":                                         "Prüfung bestanden: alle Header sind aktuell.\n",
+	"Stopped at the first error (--fail-fast); the remaining files were not processed.\n": "Beim ersten Fehler angehalten (--fail-fast); die übrigen Dateien wurden nicht verarbeitet.\n",
+	"%d files with errors exceed --max-errors %d\n":                                       "%d Dateien mit Fehlern überschreiten --max-errors %d\n",
+
+	// fix --progress
+	"--progress cannot be used with --archive\n": "--progress kann nicht mit --archive verwendet werden\n",
+	"Counting files...\n":                        "Dateien werden gezählt...\n",
+	"%d%% (%d of %d files)":                      "%d %% (%d von %d Dateien)",
+
+	// fix --moved
+	"--moved cannot be used with --remove\n": "--moved kann nicht mit --remove verwendet werden\n",
+	"%s: %s (was %s)\n":                      "%s: %s (war %s)\n",
+
+	// fix --staged and --tracked
+	"--staged and --tracked cannot be combined with each other, --files-from, --archive or --watch\n": "--staged und --tracked können nicht miteinander, mit --files-from, --archive oder --watch kombiniert werden\n",
+
+	// fix --watch
+	"--watch cannot be used with --archive or --check\n": "--watch kann nicht mit --archive oder --check verwendet werden\n",
+	"Watching %s for new files; press Ctrl-C to stop\n":  "%s wird auf neue Dateien überwacht; Strg-C zum Beenden\n",
+	"--metrics-listen requires --watch\n":                "--metrics-listen erfordert --watch\n",
+	"Error serving metrics: %v\n":                        "Fehler beim Bereitstellen der Metriken: %v\n",
+	"Serving metrics on http://%s/metrics\n":             "Metriken werden unter http://%s/metrics bereitgestellt\n",
+	"Updated: %s\n":                                      "Aktualisiert: %s\n",
+	"Would update: %s\n":                                 "Würde aktualisiert: %s\n",
+
+	"Interrupted; the files in progress were finished, and the remaining files were not processed.\n": "Unterbrochen; die begonnenen Dateien wurden fertig bearbeitet, die übrigen nicht verarbeitet.\n",
+
+	// fix --undo
+	"No backup to restore in %s\n":                  "Keine Sicherung zum Wiederherstellen in %s\n",
+	"Restored: %s\n":                                "Wiederhergestellt: %s\n",
+	"Not restoring %s: it changed after the run\n":  "%s wird nicht wiederhergestellt: die Datei wurde nach dem Lauf geändert\n",
+	"Restored %d files modified by the run of %s\n": "%d vom Lauf am %s geänderte Dateien wiederhergestellt\n",
+	"%d files were left alone; pass --yes to restore them anyway. The backup is kept in %s\n": "%d Dateien wurden nicht angetastet; mit --yes werden sie trotzdem wiederhergestellt. Die Sicherung bleibt in %s erhalten\n",
+
+	// multi
+	"%s: processed %d files (%d updated, %d skipped, %d errors, %d warnings)\n":                  "%s: %d Dateien verarbeitet (%d aktualisiert, %d übersprungen, %d Fehler, %d Warnungen)\n",
+	"Total over %d roots: processed %d files (%d updated, %d skipped, %d errors, %d warnings)\n": "Summe über %d Wurzelverzeichnisse: %d Dateien verarbeitet (%d aktualisiert, %d übersprungen, %d Fehler, %d Warnungen)\n",
+	"  failed: %s\n":            "  fehlgeschlagen: %s\n",
+	"%d of %d roots failed\n":   "%d von %d Wurzelverzeichnissen sind fehlgeschlagen\n",
+	"Error reading roots: %v\n": "Fehler beim Lesen der Wurzelverzeichnisse: %v\n",
+
+	// serve and rpc
+	"At least one --root is required\n":                          "Mindestens ein --root ist erforderlich\n",
+	"Error starting server: %v\n":                                "Fehler beim Starten des Servers: %v\n",
+	"Warning: no token configured, the API is unauthenticated\n": "Warnung: kein Token konfiguriert, die API ist nicht authentifiziert\n",
+	"Serving %d roots on %s\n":                                   "%d Wurzelverzeichnisse werden auf %s bereitgestellt\n",
+	"Error serving: %v\n":                                        "Fehler beim Bereitstellen: %v\n",
+	"Error serving requests: %v\n":                               "Fehler beim Bearbeiten der Anfragen: %v\n",
+
+	// stats
+	"Unknown output format %q (expected text, json or badge)\n": "Unbekanntes Ausgabeformat %q (erwartet: text, json oder badge)\n",
+	"Error writing badge: %v\n":                                 "Fehler beim Schreiben des Badges: %v\n",
+	"Error writing report: %v\n":                                "Fehler beim Schreiben des Berichts: %v\n",
+	"Header coverage: %.1f%% (%d of %d files)\n":                "Kopfzeilenabdeckung: %.1f%% (%d von %d Dateien)\n",
+	"By directory":                "Nach Verzeichnis",
+	"By language":                 "Nach Sprache",
+	"Directories needing headers": "Verzeichnisse mit fehlenden Kopfzeilen",
+	"  %-*s  %d missing, %d stale, %d errors\n": "  %-*s  %d fehlend, %d veraltet, %d Fehler\n",
+
+	// bot
+	"All headers are up to date. No pull request was opened.\n": "Alle Kopfzeilen sind aktuell. Es wurde kein Pull Request geöffnet.\n",
+	"Updated %d files on %s\n":                                  "%d Dateien auf %s aktualisiert\n",
+	"Opened %s\n":                                               "%s geöffnet\n",
+
+	// install-hook
+	"--mode must be %s or %s\n":            "--mode muss %s oder %s sein\n",
+	"%s is not in a git work tree: %v\n":   "%s liegt nicht in einem Git-Arbeitsverzeichnis: %v\n",
+	"Updated the pathfix commands in %s\n": "Die pathfix-Befehle in %s wurden aktualisiert\n",
+	"Installed the pre-commit hook %s\n":   "Der pre-commit-Hook %s wurde installiert\n",
+
+	// tui
+	"pathfix tui requires an interactive terminal\n": "pathfix tui erfordert ein interaktives Terminal\n",
+	"Scanning %s...\n":                "%s wird durchsucht...\n",
+	"Error running terminal UI: %v\n": "Fehler in der Terminal-Oberfläche: %v\n",
+	"No files were modified.\n":       "Es wurden keine Dateien geändert.\n",
+	"Error updating %s: %v\n":         "Fehler beim Aktualisieren von %s: %v\n",
+	"Updated %d files (%d errors)\n":  "%d Dateien aktualisiert (%d Fehler)\n",
+}
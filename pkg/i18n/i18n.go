@@ -0,0 +1,114 @@
+// File: pkg/i18n/i18n.go
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// translations maps each supported locale to its messages, keyed by the
+// English format string. English is the source language and needs no entry.
+var translations = map[string]map[string]string{
+	"de": german,
+}
+
+// messages holds the translations, built once at startup
+var messages = buildCatalog()
+
+func buildCatalog() *catalog.Builder {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for locale, entries := range translations {
+		tag := language.MustParse(locale)
+		for key, translation := range entries {
+			b.SetString(tag, key, translation)
+		}
+	}
+	return b
+}
+
+// Printer formats messages in a locale
+type Printer struct {
+	locale  string
+	printer *message.Printer // nil for English, which is printed as written
+}
+
+// NewPrinter returns a printer for a locale such as "de", "de-AT" or
+// "de_DE.UTF-8". Locales without a translation fall back to English.
+func NewPrinter(locale string) *Printer {
+	tag, err := language.Parse(normalize(locale))
+	if err != nil {
+		return &Printer{locale: "en"}
+	}
+	matched, _, confidence := messages.Matcher().Match(tag)
+	if confidence == language.No {
+		return &Printer{locale: "en"}
+	}
+	base, _ := matched.Base()
+	return &Printer{
+		locale:  base.String(),
+		printer: message.NewPrinter(matched, message.Catalog(messages)),
+	}
+}
+
+// Locale returns the language the printer translates to
+func (p *Printer) Locale() string {
+	return p.locale
+}
+
+// Sprintf formats a message in the printer's locale
+func (p *Printer) Sprintf(format string, args ...interface{}) string {
+	// English bypasses the catalog so numbers keep their plain formatting
+	if p.printer == nil {
+		return fmt.Sprintf(format, args...)
+	}
+	return p.printer.Sprintf(format, args...)
+}
+
+// Printf formats a message to standard output
+func (p *Printer) Printf(format string, args ...interface{}) {
+	fmt.Fprint(os.Stdout, p.Sprintf(format, args...))
+}
+
+// Fprintf formats a message to w
+func (p *Printer) Fprintf(w io.Writer, format string, args ...interface{}) {
+	fmt.Fprint(w, p.Sprintf(format, args...))
+}
+
+// Locales lists the locales with translations, including English
+func Locales() []string {
+	locales := []string{"en"}
+	for locale := range translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// DetectLocale returns the locale selected by the environment: PATHFIX_LOCALE,
+// then the POSIX LC_ALL, LC_MESSAGES and LANG variables
+func DetectLocale() string {
+	for _, name := range []string{"PATHFIX_LOCALE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalize converts a POSIX locale such as "de_DE.UTF-8@euro" to a BCP 47 tag
+func normalize(locale string) string {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	return strings.ReplaceAll(locale, "_", "-")
+}
@@ -0,0 +1,73 @@
+// File: pkg/i18n/i18n_test.go
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewPrinter(t *testing.T) {
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{"", "en"},
+		{"C", "en"},
+		{"POSIX", "en"},
+		{"en_US.UTF-8", "en"},
+		{"fr_FR.UTF-8", "en"},
+		{"not a locale", "en"},
+		{"de", "de"},
+		{"de-AT", "de"},
+		{"de_DE.UTF-8", "de"},
+		{"de_DE@euro", "de"},
+	}
+
+	for _, tt := range tests {
+		if got := NewPrinter(tt.locale).Locale(); got != tt.expected {
+			t.Errorf("NewPrinter(%q).Locale() = %q, expected %q", tt.locale, got, tt.expected)
+		}
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	format := "Processed %d files (%d updated, %d skipped, %d errors, %d warnings)\n"
+	tests := []struct {
+		locale   string
+		format   string
+		args     []interface{}
+		expected string
+	}{
+		{"en", format, []interface{}{1234, 1, 2, 3, 4}, "Processed 1234 files (1 updated, 2 skipped, 3 errors, 4 warnings)\n"},
+		{"de", format, []interface{}{1234, 1, 2, 3, 4}, "1.234 Dateien verarbeitet (1 aktualisiert, 2 übersprungen, 3 Fehler, 4 Warnungen)\n"},
+		{"de", "Header coverage: %.1f%% (%d of %d files)\n", []interface{}{87.5, 7, 8}, "Kopfzeilenabdeckung: 87,5% (7 von 8 Dateien)\n"},
+		{"de", "Untranslated %s\n", []interface{}{"message"}, "Untranslated message\n"},
+	}
+
+	for _, tt := range tests {
+		if got := NewPrinter(tt.locale).Sprintf(tt.format, tt.args...); got != tt.expected {
+			t.Errorf("Sprintf(%q) in %s = %q, expected %q", tt.format, tt.locale, got, tt.expected)
+		}
+	}
+}
+
+// Translations must take the same arguments as the English messages
+func TestTranslationVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0*]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+	for locale, entries := range translations {
+		for key, translation := range entries {
+			expected := verbs.FindAllString(key, -1)
+			got := verbs.FindAllString(translation, -1)
+			if len(got) != len(expected) {
+				t.Errorf("%s translation of %q has verbs %v, expected %v", locale, key, got, expected)
+				continue
+			}
+			for i := range got {
+				if got[i] != expected[i] {
+					t.Errorf("%s translation of %q has verbs %v, expected %v", locale, key, got, expected)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// File: pkg/logfile/logfile.go
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Writer appends to a log file, rotating it once it would grow past a size
+// limit. Rotated files are renamed path.1, path.2 and so on, oldest last.
+// Writes are serialized, so a Writer can be shared by concurrent runs.
+type Writer struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64 // Rotate before exceeding this size; 0 disables rotation
+	backups int   // Rotated files to keep
+	file    *os.File
+	size    int64
+}
+
+// Open opens or creates the log file at path for appending
+func Open(path string, maxSize int64, backups int) (*Writer, error) {
+	w := &Writer{path: path, maxSize: maxSize, backups: backups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open opens the current log file and records its size
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if it would push the file past the limit.
+// A single write larger than the limit still goes to one file.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the rotated files up by one, dropping the oldest, and
+// starts a new log file
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	if w.backups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", w.path, w.backups))
+		for i := w.backups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+		}
+		if err := os.Rename(w.path, w.path+".1"); err != nil {
+			return fmt.Errorf("error rotating log file: %w", err)
+		}
+	} else if err := os.Remove(w.path); err != nil {
+		return fmt.Errorf("error rotating log file: %w", err)
+	}
+	return w.open()
+}
+
+// Close closes the log file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
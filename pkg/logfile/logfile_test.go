@@ -0,0 +1,71 @@
+// File: pkg/logfile/logfile_test.go
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotation(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-logfile-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "pathfix.log")
+	w, err := Open(path, 10, 2)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q) failed: %v", line, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Each line pushes the file past 10 bytes, so every write rotates and
+	// only the two most recent backups are kept
+	expected := map[string]string{
+		"pathfix.log":   "fourth\n",
+		"pathfix.log.1": "third\n",
+		"pathfix.log.2": "second\n",
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil || string(content) != want {
+			t.Errorf("%s = %q, %v, expected %q", name, content, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("pathfix.log.3 exists, expected at most 2 backups")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-logfile-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Without a limit the file is never rotated, and reopening appends
+	path := filepath.Join(tempDir, "pathfix.log")
+	for i := 0; i < 2; i++ {
+		w, err := Open(path, 0, 2)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		w.Write([]byte(strings.Repeat("x", 100) + "\n"))
+		w.Close()
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != 202 {
+		t.Errorf("log file size = %v, %v, expected 202 bytes", info, err)
+	}
+}
@@ -0,0 +1,344 @@
+// File: pkg/models/config.go
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigVersion is the current config file schema version. Files without a
+// Version key are version 0.
+const ConfigVersion = 1
+
+// Comment kinds for CommentStyle.Preferred
+const (
+	PreferLine  = "line"  // Use the line comment marker
+	PreferBlock = "block" // Use the block comment markers
+)
+
+// Header placements for CommentStyle.Placement
+const (
+	PlacementTop            = ""                 // The first line of the file
+	PlacementFrontmatter    = "frontmatter"      // After a leading frontmatter block
+	PlacementAfterFirstLine = "after-first-line" // After the first line (e.g. an XML declaration)
+)
+
+// Dialects lists the values accepted for CommentStyle.Dialect
+var Dialects = []string{"cobol", "rpg", "powershell", "fsharp", "v", "reg", "dockerfile", "matlab", "batch", "vba", "go", "php", "pascal"}
+
+// ErrInvalidConfig is wrapped by the errors of Config.Validate and
+// CommentStyle.Validate
+var ErrInvalidConfig = errors.New("invalid config")
+
+// NewConfig returns a configuration with the defaults of an empty config file
+func NewConfig() *Config {
+	return &Config{
+		Version:           ConfigVersion,
+		CommentPrefix:     "File: ",
+		FileTypes:         make(map[string]CommentStyle),
+		AdditionalIgnores: []string{},
+	}
+}
+
+// WithFileType sets the comment style of an extension, which is lowercased
+// and given a leading dot if it lacks one
+func (c *Config) WithFileType(ext string, style CommentStyle) *Config {
+	if c.FileTypes == nil {
+		c.FileTypes = make(map[string]CommentStyle)
+	}
+	c.FileTypes[NormalizeExtension(ext)] = style
+	return c
+}
+
+// WithFileName sets the comment style of the files whose names match pattern
+func (c *Config) WithFileName(pattern string, style CommentStyle) *Config {
+	if c.FileNames == nil {
+		c.FileNames = make(map[string]CommentStyle)
+	}
+	c.FileNames[pattern] = style
+	return c
+}
+
+// WithIgnores adds patterns to AdditionalIgnores
+func (c *Config) WithIgnores(patterns ...string) *Config {
+	c.AdditionalIgnores = append(c.AdditionalIgnores, patterns...)
+	return c
+}
+
+// WithProtected adds patterns to Protected
+func (c *Config) WithProtected(patterns ...string) *Config {
+	c.Protected = append(c.Protected, patterns...)
+	return c
+}
+
+// WithCommentPrefix sets the text written before the path in each header
+func (c *Config) WithCommentPrefix(prefix string) *Config {
+	c.CommentPrefix = prefix
+	return c
+}
+
+// WithPathPrefix sets the text written before the path in the headers of
+// the files matching pattern
+func (c *Config) WithPathPrefix(pattern, prefix string) *Config {
+	if c.PathPrefixes == nil {
+		c.PathPrefixes = make(map[string]string)
+	}
+	c.PathPrefixes[pattern] = prefix
+	return c
+}
+
+// WithHeaderTemplate sets the template each header's text is written with
+func (c *Config) WithHeaderTemplate(text string) *Config {
+	c.HeaderTemplate = text
+	return c
+}
+
+// WithSPDXLicense sets the SPDX license expression written below each header
+func (c *Config) WithSPDXLicense(id string) *Config {
+	c.SPDXLicense = id
+	return c
+}
+
+// WithPlugin adds a plugin run for the files matching patterns, or for all
+// files when there are none
+func (c *Config) WithPlugin(command []string, patterns ...string) *Config {
+	c.Plugins = append(c.Plugins, Plugin{Command: command, Patterns: patterns})
+	return c
+}
+
+// WithValidator sets the command that checks modified files with an extension
+func (c *Config) WithValidator(ext string, command ...string) *Config {
+	if c.Validators == nil {
+		c.Validators = make(map[string][]string)
+	}
+	c.Validators[NormalizeExtension(ext)] = command
+	return c
+}
+
+// NormalizeExtension returns ext in the form used as a FileTypes key:
+// lowercase with a leading dot
+func NormalizeExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// Validate reports every problem with the configuration, joined into one
+// error, or nil if there are none. It checks a complete configuration:
+// FileTypes entries that only override some fields of a built-in style are
+// valid in a config file, where the processor fills in the rest before it
+// validates.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidConfig}, args...)...))
+	}
+
+	if c.Version < 0 || c.Version > ConfigVersion {
+		invalid("Version %d is not between 0 and %d", c.Version, ConfigVersion)
+	}
+	if c.CommentPrefix == "" {
+		invalid("CommentPrefix is empty")
+	} else if strings.ContainsAny(c.CommentPrefix, "\r\n") {
+		invalid("CommentPrefix %q contains a line break", c.CommentPrefix)
+	}
+	for _, pattern := range sortedKeys(c.PathPrefixes) {
+		prefix := c.PathPrefixes[pattern]
+		if _, err := path.Match(strings.TrimPrefix(pattern, "/"), ""); err != nil {
+			invalid("PathPrefixes pattern %q is malformed", pattern)
+		}
+		if strings.TrimSpace(prefix) == "" {
+			invalid("PathPrefixes[%q] is empty", pattern)
+		} else if strings.ContainsAny(prefix, "\r\n") {
+			invalid("PathPrefixes[%q] %q contains a line break", pattern, prefix)
+		}
+	}
+	if _, err := ParseHeaderTemplate(c.HeaderTemplate); err != nil {
+		invalid("HeaderTemplate %q: %v", c.HeaderTemplate, err)
+	}
+	if c.FrontmatterField != "" && (strings.ContainsAny(c.FrontmatterField, ":#'\" \t\r\n") || strings.HasPrefix(c.FrontmatterField, "-")) {
+		invalid("FrontmatterField %q is not a plain YAML key", c.FrontmatterField)
+	}
+	if strings.ContainsAny(c.SPDXLicense, "\r\n") || strings.TrimSpace(c.SPDXLicense) != c.SPDXLicense {
+		invalid("SPDXLicense %q contains a line break or surrounding spaces", c.SPDXLicense)
+	}
+	if strings.ContainsAny(c.UpdateExistingPrefix, "\r\n") {
+		invalid("UpdateExistingPrefix %q contains a line break", c.UpdateExistingPrefix)
+	}
+	switch c.Style {
+	case "", PreferLine, PreferBlock:
+	default:
+		invalid("Style %q is not \"line\" or \"block\"", c.Style)
+	}
+	switch strings.ToLower(c.PathNormalization) {
+	case "", "nfc", "nfd", "none":
+	default:
+		invalid("PathNormalization %q is not \"nfc\", \"nfd\" or \"none\"", c.PathNormalization)
+	}
+	switch strings.ToLower(c.InvalidPathPolicy) {
+	case "", "skip", "escape", "fail":
+	default:
+		invalid("InvalidPathPolicy %q is not \"skip\", \"escape\" or \"fail\"", c.InvalidPathPolicy)
+	}
+	if c.BinarySampleSize < 0 {
+		invalid("BinarySampleSize %d is negative", c.BinarySampleSize)
+	}
+	if c.Workers < 0 {
+		invalid("Workers %d is negative", c.Workers)
+	}
+	if c.MaxChangedFiles < 0 {
+		invalid("MaxChangedFiles %d is negative", c.MaxChangedFiles)
+	}
+	if c.MaxChangedPercent < 0 || c.MaxChangedPercent > 100 {
+		invalid("MaxChangedPercent %g is not between 0 and 100", c.MaxChangedPercent)
+	}
+
+	for _, ext := range sortedKeys(c.FileTypes) {
+		style := c.FileTypes[ext]
+		if ext != NormalizeExtension(ext) {
+			invalid("FileTypes key %q should be %q", ext, NormalizeExtension(ext))
+		}
+		if err := style.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("FileTypes[%q]: %w", ext, err))
+		}
+	}
+	for _, pattern := range sortedKeys(c.FileNames) {
+		if _, err := path.Match(strings.TrimPrefix(pattern, "/"), ""); err != nil || pattern == "" {
+			invalid("FileNames pattern %q is malformed", pattern)
+		}
+		if err := c.FileNames[pattern].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("FileNames[%q]: %w", pattern, err))
+		}
+	}
+	for _, pattern := range c.Protected {
+		if _, err := path.Match(strings.TrimPrefix(pattern, "/"), ""); err != nil {
+			invalid("Protected pattern %q is malformed", pattern)
+		}
+	}
+	for _, pattern := range c.JSONCommentPaths {
+		if _, err := path.Match(strings.TrimPrefix(pattern, "/"), ""); err != nil {
+			invalid("JSONCommentPaths pattern %q is malformed", pattern)
+		}
+	}
+	for i, plugin := range c.Plugins {
+		if len(plugin.Command) == 0 || plugin.Command[0] == "" {
+			invalid("Plugins[%d] has no command", i)
+		}
+		for _, pattern := range plugin.Patterns {
+			if _, err := path.Match(strings.TrimPrefix(pattern, "/"), ""); err != nil {
+				invalid("Plugins[%d] pattern %q is malformed", i, pattern)
+			}
+		}
+	}
+	for _, ext := range sortedKeys(c.Validators) {
+		if command := c.Validators[ext]; len(command) == 0 || command[0] == "" {
+			invalid("Validators[%q] has no command", ext)
+		}
+	}
+	for _, dir := range c.TrustedConfigs {
+		if !filepath.IsAbs(dir) {
+			invalid("TrustedConfigs entry %q is not an absolute path", dir)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LineStyle returns a style that writes headers as line comments
+func LineStyle(marker string) CommentStyle {
+	return CommentStyle{LineComment: marker, Preferred: PreferLine}
+}
+
+// BlockStyle returns a style that writes headers as block comments
+func BlockStyle(start, end string) CommentStyle {
+	return CommentStyle{BlockCommentStart: start, BlockCommentEnd: end, Preferred: PreferBlock}
+}
+
+// WithBlock adds block comment markers to a style, keeping its preference
+func (s CommentStyle) WithBlock(start, end string) CommentStyle {
+	s.BlockCommentStart = start
+	s.BlockCommentEnd = end
+	if s.Preferred == "" {
+		s.Preferred = PreferBlock
+	}
+	return s
+}
+
+// WithPlacement returns the style with the header placed as given
+func (s CommentStyle) WithPlacement(placement string) CommentStyle {
+	s.Placement = placement
+	return s
+}
+
+// Validate reports every problem with the style, joined into one error, or
+// nil if a header can be written with it
+func (s CommentStyle) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidConfig}, args...)...))
+	}
+
+	hasBlock := s.BlockCommentStart != "" && s.BlockCommentEnd != ""
+	if (s.BlockCommentStart == "") != (s.BlockCommentEnd == "") {
+		invalid("BlockCommentStart and BlockCommentEnd must be set together")
+	}
+	for _, marker := range [][2]string{{"LineComment", s.LineComment}, {"BlockCommentStart", s.BlockCommentStart}, {"BlockCommentEnd", s.BlockCommentEnd}} {
+		if strings.ContainsAny(marker[1], "\r\n") {
+			invalid("%s %q contains a line break", marker[0], marker[1])
+		}
+	}
+	switch s.Preferred {
+	case PreferLine:
+		if s.LineComment == "" {
+			invalid("Preferred is \"line\" but LineComment is empty")
+		}
+	case PreferBlock, "":
+		// Headers are block comments unless line comments are preferred
+		switch {
+		case hasBlock:
+		case s.LineComment == "":
+			invalid("no line or block comment markers")
+		case s.Preferred == PreferBlock:
+			invalid("Preferred is \"block\" but there are no block comment markers")
+		default:
+			invalid("Preferred must be \"line\" for a style with only a line comment")
+		}
+	default:
+		invalid("Preferred %q is not \"line\" or \"block\"", s.Preferred)
+	}
+	switch s.Placement {
+	case PlacementTop, PlacementFrontmatter, PlacementAfterFirstLine:
+	default:
+		invalid("Placement %q is not \"frontmatter\" or \"after-first-line\"", s.Placement)
+	}
+	if s.Dialect != "" && !containsString(Dialects, s.Dialect) {
+		invalid("Dialect %q is not one of %s", s.Dialect, strings.Join(Dialects, ", "))
+	}
+	return errors.Join(errs...)
+}
+
+// sortedKeys returns the keys of a map in order, so errors are reported in
+// the same order each time
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+// File: pkg/models/config_test.go
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCommentStyleValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		style    CommentStyle
+		expected string // Part of the error, or "" for a valid style
+	}{
+		{"line", LineStyle("//"), ""},
+		{"block", BlockStyle("/*", "*/"), ""},
+		{"line and block", LineStyle("//").WithBlock("/*", "*/"), ""},
+		{"frontmatter", BlockStyle("<!--", "-->").WithPlacement(PlacementFrontmatter), ""},
+		{"dialect", CommentStyle{LineComment: "#", Preferred: "line", Dialect: "powershell"}, ""},
+		{"no markers", CommentStyle{Preferred: "line"}, "LineComment is empty"},
+		{"empty", CommentStyle{}, "no line or block comment markers"},
+		{"line without preference", CommentStyle{LineComment: "#"}, "Preferred must be \"line\""},
+		{"block without markers", CommentStyle{LineComment: "#", Preferred: "block"}, "no block comment markers"},
+		{"unpaired block", CommentStyle{LineComment: "#", BlockCommentStart: "/*", Preferred: "line"}, "must be set together"},
+		{"unknown preference", CommentStyle{LineComment: "#", Preferred: "both"}, "Preferred \"both\""},
+		{"unknown placement", LineStyle("#").WithPlacement("bottom"), "Placement \"bottom\""},
+		{"unknown dialect", CommentStyle{LineComment: "#", Preferred: "line", Dialect: "fortran"}, "Dialect \"fortran\""},
+		{"line break", LineStyle("#\n"), "contains a line break"},
+	}
+
+	for _, tc := range testCases {
+		err := tc.style.Validate()
+		if tc.expected == "" {
+			if err != nil {
+				t.Errorf("Validate(%s) = %v, expected nil", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.expected) || !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Validate(%s) = %v, expected an error containing %q", tc.name, err, tc.expected)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := func() *Config {
+		return NewConfig().
+			WithFileType("RS", LineStyle("//").WithBlock("/*", "*/")).
+			WithFileName("Earthfile", LineStyle("#")).
+			WithIgnores("vendor/").
+			WithPlugin([]string{"license-check"}, "*.go").
+			WithValidator(".go", "go", "vet").
+			WithPathPrefix("*_test.go", "Test file: ").
+			WithHeaderTemplate("{{.Prefix}}{{.Path}} ({{.Project}}, {{.Year}})").
+			WithSPDXLicense("MIT OR Apache-2.0")
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate(built config) = %v, expected nil", err)
+	}
+	if _, ok := valid().FileTypes[".rs"]; !ok {
+		t.Errorf("WithFileType(%q) did not add %q", "RS", ".rs")
+	}
+
+	testCases := []struct {
+		name     string
+		modify   func(c *Config)
+		expected []string
+	}{
+		{"empty prefix", func(c *Config) { c.CommentPrefix = "" }, []string{"CommentPrefix is empty"}},
+		{"newer version", func(c *Config) { c.Version = ConfigVersion + 1 }, []string{"Version"}},
+		{"style", func(c *Config) { c.Style = "both" }, []string{"Style \"both\""}},
+		{"normalization", func(c *Config) { c.PathNormalization = "nfkc" }, []string{"PathNormalization \"nfkc\""}},
+		{"policy", func(c *Config) { c.InvalidPathPolicy = "ignore" }, []string{"InvalidPathPolicy \"ignore\""}},
+		{"limits", func(c *Config) { c.MaxChangedFiles = -1; c.MaxChangedPercent = 150 }, []string{"MaxChangedFiles", "MaxChangedPercent"}},
+		{"workers", func(c *Config) { c.Workers = -2 }, []string{"Workers -2 is negative"}},
+		{"file type key", func(c *Config) { c.FileTypes["Go"] = LineStyle("//") }, []string{"FileTypes key \"Go\" should be \".go\""}},
+		{"file type style", func(c *Config) { c.FileTypes[".x"] = CommentStyle{} }, []string{"FileTypes[\".x\"]: invalid config: no line or block"}},
+		{"file name", func(c *Config) { c.FileNames["[Build"] = LineStyle("#"); c.FileNames["Justfile"] = CommentStyle{} }, []string{"FileNames pattern \"[Build\"", "FileNames[\"Justfile\"]: invalid config"}},
+		{"glob", func(c *Config) { c.JSONCommentPaths = []string{"[a.json"} }, []string{"JSONCommentPaths pattern \"[a.json\""}},
+		{"plugin", func(c *Config) { c.Plugins = append(c.Plugins, Plugin{}) }, []string{"Plugins[1] has no command"}},
+		{"validator", func(c *Config) { c.Validators[".py"] = nil }, []string{"Validators[\".py\"] has no command"}},
+		{"protected", func(c *Config) { c.Protected = []string{"third_party/**", "[legal"} }, []string{"Protected pattern \"[legal\" is malformed"}},
+		{"path prefix", func(c *Config) { c.PathPrefixes["[gen/*"] = "Generated: "; c.PathPrefixes["*.pb.go"] = " " }, []string{"PathPrefixes pattern \"[gen/*\"", "PathPrefixes[\"*.pb.go\"] is empty"}},
+		{"template syntax", func(c *Config) { c.HeaderTemplate = "{{.Prefix}}{{.Path" }, []string{"HeaderTemplate \"{{.Prefix}}{{.Path\""}},
+		{"template field", func(c *Config) { c.HeaderTemplate = "{{.Prefix}}{{.Path}} {{.Author}}" }, []string{"can't evaluate field Author"}},
+		{"template prefix", func(c *Config) { c.HeaderTemplate = "{{.Project}}: {{.Prefix}}{{.Path}}" }, []string{"does not start with {{.Prefix}}"}},
+		{"template path", func(c *Config) { c.HeaderTemplate = "{{.Prefix}}{{.Filename}}" }, []string{"does not contain {{.Path}}"}},
+		{"frontmatter", func(c *Config) { c.FrontmatterField = "source path" }, []string{"FrontmatterField \"source path\""}},
+		{"spdx", func(c *Config) { c.SPDXLicense = "MIT\n" }, []string{"SPDXLicense \"MIT\\n\""}},
+		{"template lines", func(c *Config) { c.HeaderTemplate = "{{.Prefix}}{{.Path}}\nCopyright {{.Year}}" }, []string{"line break"}},
+	}
+
+	for _, tc := range testCases {
+		config := valid()
+		tc.modify(config)
+		err := config.Validate()
+		if err == nil || !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Validate(%s) = %v, expected an invalid config error", tc.name, err)
+			continue
+		}
+		for _, expected := range tc.expected {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Validate(%s) = %v, expected it to mention %q", tc.name, err, expected)
+			}
+		}
+	}
+}
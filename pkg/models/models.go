@@ -1,29 +1,235 @@
 // File: pkg/models/models.go
 package models
 
+import "time"
+
 // CommentStyle defines how comments are formatted for a specific file type
 type CommentStyle struct {
 	LineComment       string // For single line comments (e.g. // for C-style, # for Python)
 	BlockCommentStart string // For block comments start (e.g. /* for C-style)
 	BlockCommentEnd   string // For block comments end (e.g. */ for C-style)
 	Preferred         string // Preferred comment style: "line" or "block"
+	Placement         string // Where the header goes: "" for the top of the file, "frontmatter" to skip a leading frontmatter block, "after-first-line"
+	HeaderPrefix      string // Text emitted before the header comment (e.g. indentation or a separator line ending in "\n")
+	HeaderSuffix      string // Text emitted after the header line (e.g. "\n" for a blank line)
+	Dialect           string // Language-specific handling: "cobol" or "rpg" (fixed format with a free-format fallback), "powershell", "fsharp", "v", "reg", "dockerfile", "matlab", "batch", "vba", "go", "php", "pascal"
 }
 
 // Config holds the application configuration
 type Config struct {
+	Version              int                     // Config schema version; older files are upgraded when loaded (see pathfix config migrate)
 	FileTypes            map[string]CommentStyle // Map of file extension to comment style
+	FileNames            map[string]CommentStyle // Map of file name globs (e.g. "Dockerfile", "Dockerfile.*") to comment style, for files known by name; they win over FileTypes
 	AdditionalIgnores    []string                // Additional file/directory patterns to ignore
+	Protected            []string                // Globs of files never to modify, whatever other settings say ("**" matches any number of directories)
 	IncludeGitIgnored    bool                    // Whether to process files ignored by .gitignore
+	GitIgnoreOnly        bool                    // Whether to honor only .gitignore, not .git/info/exclude or git's core.excludesFile
 	IncludeHidden        bool                    // Whether to process hidden files/directories
+	FollowSymlinks       bool                    // Whether to follow symbolic links and junctions (with cycle detection) instead of skipping them
+	IgnoreCase           bool                    // Whether path matching is case-insensitive (enabled automatically by git's core.ignorecase)
+	IncludeDocs          bool                    // Whether to add headers to documentation formats (.md, .markdown, .mdx, .rst, .adoc)
+	IncludeExecutables   bool                    // Whether to process executable files without an extension, styled by their shebang's interpreter
+	AutoDetect           bool                    // Whether to choose the comment style of files of unknown type from their content
+	Languages            []string                // Only process files of these languages (names such as "go" or extensions such as ".vue"); all when empty
+	ExcludeLanguages     []string                // Never process files of these languages
 	DryRun               bool                    // If true, don't modify files
+	Verbose              bool                    // Whether to print what happens to each file, as with --verbose
+	MaxChangedFiles      int                     // Refuse runs that would modify more files than this without confirmation (0 for no limit)
+	MaxChangedPercent    float64                 // Refuse runs that would modify more than this percentage of the files visited without confirmation (0 for no limit)
+	CreatedAfter         string                  // Only add headers to files git did not track yet at this date (YYYY-MM-DD) or revision
+	StateDir             string                  // Directory for the run history and other local state, relative to the root (default: ".pathfix")
+	Backup               bool                    // Whether to copy each file into the state directory before modifying it, so the run can be undone
+	AfterLicense         bool                    // Whether to put the header below a license or copyright comment at the top of the file instead of above it
+	Style                string                  // "line" or "block" to use that kind of header for every type that supports both, overriding Preferred
+	MatchCommentStyle    bool                    // Whether to write line or block headers as the file's existing header or comments do, rather than as Preferred says
 	CommentPrefix        string                  // Text to prepend before the file path (default: "File: ")
+	PathPrefixes         map[string]string       // Text to prepend instead of CommentPrefix for the files matching a glob pattern; the longest matching pattern wins
+	HeaderTemplate       string                  // text/template for the header text, using the fields of HeaderData (default: "{{.Prefix}}{{.Path}}")
+	FrontmatterField     string                  // Key under which to record the path in the YAML frontmatter of Markdown files that have one, instead of a comment (none when empty)
+	LicenseFile          string                  // Template of a license notice written as a comment next to each header, relative to the root directory (none when empty)
+	SPDXLicense          string                  // SPDX license expression, such as "MIT", to write in an SPDX-License-Identifier line below each header (none when empty)
 	UpdateExistingPrefix string                  // If not empty, only update comments starting with this prefix
+	PathNormalization    string                  // Unicode normalization of header paths: "nfc" (default), "nfd" or "none"
+	InvalidPathPolicy    string                  // Handling of file names that are not valid UTF-8: "skip" (default), "escape" (percent-encode) or "fail"
+	BinarySampleSize     int                     // Bytes inspected when detecting binary files (default: 8000)
+	DetectContentType    bool                    // Whether to recognize binary formats (images, archives, executables, PDFs) by content signature
+	TextExtensions       []string                // Extensions always treated as text, skipping binary detection
+	BinaryExtensions     []string                // Extensions always treated as binary, skipping binary detection
+	JSONCommentPaths     []string                // Globs of .json files that accept // comments (e.g. "tsconfig.json", ".vscode/*.json")
+	Plugins              []Plugin                // External commands that can veto or rewrite each file's header
+	Script               string                  // Starlark file defining include(file) and header(file) rules, relative to the root directory
+	Hooks                Hooks                   // Commands run around the walk and after each modified file
+	Validators           map[string][]string     // Commands that check modified files, keyed by extension; the relative path replaces "{file}" or is appended
+	TrustedConfigs       []string                // Absolute directories whose found config files, and those beneath them, may run Hooks, Plugins and Validators; read only from the user config
+	GoFormatCheck        bool                    // Whether .go files must stay gofmt-formatted after the header change (they must always still parse)
+	Workers              int                     // Files processed in parallel when --workers is not given (default: 1)
+}
+
+// Hooks are commands run from the root directory at points of a run. Each
+// is a program followed by its arguments.
+type Hooks struct {
+	PreRun  []string // Run before the walk; a failure aborts the run
+	PostRun []string // Run after the walk with the JSON report on stdin
+	PerFile []string // Run for each modified file with its relative path appended (not in dry runs)
+}
+
+// Plugin is an external command consulted for each candidate file. It
+// receives a JSON description of the file on stdin and may answer on stdout.
+type Plugin struct {
+	Command  []string // Program and arguments, run from the root directory
+	Patterns []string // Path globs the plugin applies to (as in JSONCommentPaths); empty for all files
 }
 
 // Stats tracks processing statistics
 type Stats struct {
-	Processed int // Total number of files processed
-	Updated   int // Number of files updated
-	Skipped   int // Number of files skipped
-	Errors    int // Number of files with errors
-}
\ No newline at end of file
+	Processed int `json:"processed"` // Total number of files processed
+	Updated   int `json:"updated"`   // Number of files updated
+	Skipped   int `json:"skipped"`   // Number of files skipped
+	Errors    int `json:"errors"`    // Number of files with errors
+	Warnings  int `json:"warnings"`  // Number of files with a warning (skipped by policy, or header path escaped or normalized)
+	Reverted  int `json:"reverted"`  // Number of modified files restored because validation failed (also counted as errors)
+	Missing   int `json:"missing"`   // Updated files that had no header
+	Stale     int `json:"stale"`     // Updated files whose header was out of date
+	Removed   int `json:"removed"`   // Updated files whose header was removed, in remove mode
+
+	// The size of the changes to updated files. Changed lines are paired up
+	// in order: a pair is one line rewritten, and the lines left over on
+	// either side are added or removed. Bytes count every changed line.
+	LinesAdded     int `json:"lines_added"`
+	LinesRemoved   int `json:"lines_removed"`
+	LinesRewritten int `json:"lines_rewritten"`
+	BytesAdded     int `json:"bytes_added"`
+	BytesRemoved   int `json:"bytes_removed"`
+}
+
+// Add adds the counts of other to s
+func (s *Stats) Add(other Stats) {
+	s.Processed += other.Processed
+	s.Updated += other.Updated
+	s.Skipped += other.Skipped
+	s.Errors += other.Errors
+	s.Warnings += other.Warnings
+	s.Reverted += other.Reverted
+	s.Missing += other.Missing
+	s.Stale += other.Stale
+	s.Removed += other.Removed
+	s.LinesAdded += other.LinesAdded
+	s.LinesRemoved += other.LinesRemoved
+	s.LinesRewritten += other.LinesRewritten
+	s.BytesAdded += other.BytesAdded
+	s.BytesRemoved += other.BytesRemoved
+}
+
+// DirectoryStats counts the outcomes of the files in a directory
+type DirectoryStats struct {
+	Directory string `json:"directory"` // Relative to the root with forward slashes, "." for the root
+	Stats     Stats  `json:"stats"`
+}
+
+// Actions recorded for each file visited during a run
+const (
+	ActionUpdated   = "updated"   // The header was added or replaced (or would be, in a dry run)
+	ActionUnchanged = "unchanged" // The file already has the correct header
+	ActionSkipped   = "skipped"   // The file was not eligible for a header
+	ActionError     = "error"     // The file could not be processed
+	ActionListed    = "listed"    // The file is eligible for a header; its content was not checked (ListOnly)
+)
+
+// Reasons recorded for updated files
+const (
+	ReasonMissingHeader = "missing header" // The file had no header
+	ReasonStaleHeader   = "stale header"   // The file's header named another path or was formatted differently
+	ReasonRemovedHeader = "removed header" // The file's header was removed, in remove mode
+)
+
+// FileResult records what happened to a single file
+type FileResult struct {
+	Path    string `json:"path"`               // Path relative to the root directory, with forward slashes
+	Action  string `json:"action"`             // One of the Action constants
+	Reason  string `json:"reason,omitempty"`   // Why the file was skipped, or one of the Reason constants for updated files
+	Error   string `json:"error,omitempty"`    // Error message for failed files
+	Warning string `json:"warning,omitempty"`  // What deserves attention about a file that did not fail
+	Diff    string `json:"diff,omitempty"`     // Unified diff of the change to an updated file, when the run records diffs
+	OldPath string `json:"old_path,omitempty"` // The other path a stale header named, as after the file was renamed or moved
+}
+
+// Report summarizes a run for tools that consume it
+type Report struct {
+	Root     string       `json:"root"`
+	DryRun   bool         `json:"dry_run"`
+	FailFast bool         `json:"fail_fast"`         // The run was set to stop at the first failed file
+	Stopped  bool         `json:"stopped,omitempty"` // The run stopped early at a failed file
+	Stats    Stats        `json:"stats"`
+	Results  []FileResult `json:"results"`
+}
+
+// RootReport summarizes the run over one root of a multi-root run
+type RootReport struct {
+	Root       string `json:"root"`
+	ConfigFile string `json:"config_file,omitempty"` // The config file used for the root, if any
+	Stats      Stats  `json:"stats"`
+	Failed     bool   `json:"failed,omitempty"` // The run did not complete, or its config could not be loaded
+	Error      string `json:"error,omitempty"`  // Why the run failed, or the files that failed
+}
+
+// MultiReport combines the runs over several independent roots
+type MultiReport struct {
+	DryRun bool         `json:"dry_run"`
+	Stats  Stats        `json:"stats"` // Totals over all roots
+	Roots  []RootReport `json:"roots"` // In the order the roots were given
+}
+
+// Coverage counts the files eligible for a header and those that already have the correct one
+type Coverage struct {
+	Files   int     `json:"files"`   // Files eligible for a header
+	Covered int     `json:"covered"` // Files whose header is already correct
+	Percent float64 `json:"percent"` // Covered files as a percentage of eligible files (100 when there are none)
+}
+
+// CoverageReport breaks header coverage down by directory and language
+type CoverageReport struct {
+	Root        string              `json:"root"`
+	Total       Coverage            `json:"total"`
+	Directories map[string]Coverage `json:"directories"` // Keyed by directory, "." for the root
+	Languages   map[string]Coverage `json:"languages"`   // Keyed by file extension, or file name for files without one
+
+	// The directories with the most missing or stale headers, most first
+	TopDirectories []DirectoryStats `json:"top_directories,omitempty"`
+}
+
+// Badge is a shields.io endpoint badge
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// LogEntry is a line of the structured run log
+type LogEntry struct {
+	Time   time.Time   `json:"time"`
+	Event  string      `json:"event"` // One of the Log constants
+	Root   string      `json:"root"`
+	DryRun bool        `json:"dry_run"`
+	File   *FileResult `json:"file,omitempty"`  // Outcome of a file
+	Stats  *Stats      `json:"stats,omitempty"` // Final statistics
+	Error  string      `json:"error,omitempty"` // Why the run failed, or the files that failed
+}
+
+// HistoryEntry is a run recorded in the state directory's history
+type HistoryEntry struct {
+	Start    time.Time `json:"start"`
+	Duration float64   `json:"duration_seconds"`
+	Root     string    `json:"root"`
+	DryRun   bool      `json:"dry_run"`
+	Stopped  bool      `json:"stopped,omitempty"` // The run stopped early at a failed file
+	Stats    Stats     `json:"stats"`
+	Error    string    `json:"error,omitempty"` // Why the run failed, or the files that failed
+}
+
+// Events recorded in the structured run log
+const (
+	LogStart  = "start"  // A run started
+	LogFile   = "file"   // A file was visited
+	LogFinish = "finish" // A run ended
+)
@@ -0,0 +1,79 @@
+// File: pkg/models/template.go
+package models
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+)
+
+// DefaultHeaderTemplate is the header text written when HeaderTemplate is
+// empty: the comment prefix followed by the path
+const DefaultHeaderTemplate = "{{.Prefix}}{{.Path}}"
+
+// HeaderData holds the values a HeaderTemplate can use
+type HeaderData struct {
+	Prefix   string // Comment prefix of the file, from CommentPrefix or PathPrefixes
+	Path     string // Path of the file relative to the root directory, with forward slashes
+	Filename string // Last element of Path
+	Dir      string // Path without its last element, or "." for files at the top
+	Project  string // Name of the top of the git work tree, or of the root directory outside one
+	Date     string // Today's date as YYYY-MM-DD
+	Year     string // The current year
+}
+
+// sampleHeaderData is used to try templates out when they are parsed
+var sampleHeaderData = HeaderData{
+	Prefix:   "File: ",
+	Path:     "dir/name.ext",
+	Filename: "name.ext",
+	Dir:      "dir",
+	Project:  "project",
+	Date:     "2006-01-02",
+	Year:     "2006",
+}
+
+// ParseHeaderTemplate parses a HeaderTemplate, or the default if text is
+// empty. The header must be a single line starting with the prefix and
+// naming the path, so that pathfix can find and update it later.
+func ParseHeaderTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultHeaderTemplate
+	}
+	tmpl, err := template.New("header").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := sampleHeaderData
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, sample); err != nil {
+		return nil, err
+	}
+	switch header := sb.String(); {
+	case strings.ContainsAny(header, "\r\n"):
+		return nil, errors.New("the header contains a line break")
+	case !strings.HasPrefix(header, sample.Prefix):
+		return nil, errors.New("the header does not start with {{.Prefix}}")
+	case !strings.Contains(header[len(sample.Prefix):], sample.Path):
+		return nil, errors.New("the header does not contain {{.Path}}")
+	}
+	return tmpl, nil
+}
+
+// ParseNoticeTemplate parses the license notice of a LicenseFile, which can
+// use the same fields as a HeaderTemplate and span several lines
+func ParseNoticeTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("notice").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, sampleHeaderData); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(sb.String()) == "" {
+		return nil, errors.New("the notice is empty")
+	}
+	return tmpl, nil
+}
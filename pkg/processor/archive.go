@@ -0,0 +1,350 @@
+// File: pkg/processor/archive.go
+package processor
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// Archive formats supported by ProcessArchive
+const (
+	archiveTar   = "tar"
+	archiveTarGz = "tar.gz"
+	archiveZip   = "zip"
+)
+
+// archiveFormat detects an archive's format from its file name
+func archiveFormat(name string) (string, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return archiveTarGz, nil
+	case strings.HasSuffix(lower, ".tar"):
+		return archiveTar, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return archiveZip, nil
+	}
+	return "", fmt.Errorf("unsupported archive format: %s (expected .tar, .tar.gz, .tgz or .zip)", name)
+}
+
+// ProcessArchive adds or updates the headers of the files inside a tar,
+// gzip-compressed tar or zip archive and writes a new archive of the same
+// format to outPath, preserving entry metadata. Header paths are relative to
+// the archive's single top-level directory, if it has one. In dry runs
+// nothing is written. Like Process, it returns a FileErrors if some entries
+// could not be processed; the archive is still written.
+func (p *Processor) ProcessArchive(archivePath, outPath string) (models.Stats, error) {
+	return p.ProcessArchiveContext(context.Background(), archivePath, outPath)
+}
+
+// ProcessArchiveContext is ProcessArchive with a context that can cancel the
+// run between entries, in which case no archive is written
+func (p *Processor) ProcessArchiveContext(ctx context.Context, archivePath, outPath string) (models.Stats, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetStats()
+	finish := p.logRun()
+	stats, err := p.processArchive(ctx, archivePath, outPath)
+	finish(err)
+	p.flushOutput()
+	return stats, err
+}
+
+// processArchive implements ProcessArchiveContext
+func (p *Processor) processArchive(ctx context.Context, archivePath, outPath string) (models.Stats, error) {
+	format, err := archiveFormat(archivePath)
+	if err != nil {
+		return p.statistics, err
+	}
+	if _, err := p.loadScript(); err != nil {
+		return p.statistics, err
+	}
+
+	// Write to a temporary file next to the output so a failed run leaves nothing behind
+	var out io.Writer = io.Discard
+	var tmp *os.File
+	if !p.options.DryRun {
+		tmp, err = os.CreateTemp(filepath.Dir(outPath), ".pathfix-archive-*")
+		if err != nil {
+			return p.statistics, fmt.Errorf("error creating output archive: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		out = tmp
+	}
+
+	switch format {
+	case archiveZip:
+		err = p.processZip(ctx, archivePath, out)
+	default:
+		err = p.processTar(ctx, archivePath, format == archiveTarGz, out)
+	}
+	if err != nil {
+		return p.statistics, err
+	}
+
+	if tmp != nil {
+		tmp.Chmod(0644)
+		if err := tmp.Close(); err != nil {
+			return p.statistics, fmt.Errorf("error writing output archive: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), outPath); err != nil {
+			return p.statistics, fmt.Errorf("error writing output archive: %w", err)
+		}
+	}
+	if len(p.failures) > 0 {
+		return p.statistics, p.failures
+	}
+	return p.statistics, nil
+}
+
+// processZip rewrites a zip archive. Unchanged entries are copied without
+// recompression.
+func (p *Processor) processZip(ctx context.Context, archivePath string, out io.Writer) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("error reading archive: %w", err)
+	}
+	defer reader.Close()
+
+	var names []string
+	for _, f := range reader.File {
+		if f.Mode().IsRegular() {
+			names = append(names, f.Name)
+		}
+	}
+	prefix := archiveTopDir(names)
+
+	writer := zip.NewWriter(out)
+	if err := writer.SetComment(reader.Comment); err != nil {
+		return err
+	}
+	for _, f := range reader.File {
+		if !f.Mode().IsRegular() {
+			if err := writer.Copy(f); err != nil {
+				return fmt.Errorf("error writing archive: %w", err)
+			}
+			continue
+		}
+
+		content, err := readZipEntry(f)
+		if err != nil {
+			return fmt.Errorf("error reading %s from archive: %w", f.Name, err)
+		}
+		newContent, err := p.processArchiveEntry(ctx, f.Name, prefix, content)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(newContent, content) {
+			if err := writer.Copy(f); err != nil {
+				return fmt.Errorf("error writing archive: %w", err)
+			}
+			continue
+		}
+
+		header := f.FileHeader
+		w, err := writer.CreateHeader(&header)
+		if err == nil {
+			_, err = w.Write(newContent)
+		}
+		if err != nil {
+			return fmt.Errorf("error writing archive: %w", err)
+		}
+	}
+	return writer.Close()
+}
+
+// readZipEntry reads the uncompressed content of a zip entry
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// processTar rewrites a tar archive, optionally gzip-compressed. The archive
+// is read twice: once to find its top-level directory, then to rewrite it.
+func (p *Processor) processTar(ctx context.Context, archivePath string, compressed bool, out io.Writer) error {
+	var names []string
+	gzipHeader, err := readTar(archivePath, compressed, func(header *tar.Header, _ io.Reader) error {
+		if header.Typeflag == tar.TypeReg {
+			names = append(names, header.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	prefix := archiveTopDir(names)
+
+	var gzipWriter *gzip.Writer
+	tarOut := out
+	if compressed {
+		gzipWriter = gzip.NewWriter(out)
+		gzipWriter.Header = gzipHeader
+		tarOut = gzipWriter
+	}
+	writer := tar.NewWriter(tarOut)
+
+	_, err = readTar(archivePath, compressed, func(header *tar.Header, r io.Reader) error {
+		if header.Typeflag != tar.TypeReg {
+			if err := writer.WriteHeader(header); err != nil {
+				return err
+			}
+			_, err := io.Copy(writer, r)
+			return err
+		}
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("error reading %s from archive: %w", header.Name, err)
+		}
+		newContent, err := p.processArchiveEntry(ctx, header.Name, prefix, content)
+		if err != nil {
+			return err
+		}
+		header.Size = int64(len(newContent))
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = writer.Write(newContent)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if gzipWriter != nil {
+		return gzipWriter.Close()
+	}
+	return nil
+}
+
+// readTar calls fn for each entry of a tar archive and returns the gzip
+// header of compressed archives
+func readTar(archivePath string, compressed bool, fn func(*tar.Header, io.Reader) error) (gzip.Header, error) {
+	var gzipHeader gzip.Header
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return gzipHeader, fmt.Errorf("error reading archive: %w", err)
+	}
+	defer file.Close()
+
+	var in io.Reader = file
+	if compressed {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return gzipHeader, fmt.Errorf("error reading archive: %w", err)
+		}
+		defer gzipReader.Close()
+		gzipHeader = gzipReader.Header
+		in = gzipReader
+	}
+
+	reader := tar.NewReader(in)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return gzipHeader, nil
+		}
+		if err != nil {
+			return gzipHeader, fmt.Errorf("error reading archive: %w", err)
+		}
+		if err := fn(header, reader); err != nil {
+			return gzipHeader, err
+		}
+	}
+}
+
+// archiveTopDir returns the top-level directory shared by all entry names,
+// with a trailing slash, or "" if there is none
+func archiveTopDir(names []string) string {
+	top := ""
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "./")
+		dir, _, ok := strings.Cut(name, "/")
+		if !ok || (top != "" && dir != top) {
+			return ""
+		}
+		top = dir
+	}
+	if top == "" {
+		return ""
+	}
+	return top + "/"
+}
+
+// processArchiveEntry fixes the header of an archive entry and records the
+// outcome. An error is returned only when FailFast stops the run or ctx
+// cancels it.
+func (p *Processor) processArchiveEntry(ctx context.Context, name, prefix string, content []byte) ([]byte, error) {
+	if err := canceled(ctx); err != nil {
+		return nil, err
+	}
+	if p.options.OnFileStart != nil {
+		p.options.OnFileStart(name)
+	}
+	relPath := strings.TrimPrefix(strings.TrimPrefix(name, "./"), prefix)
+	newContent, result := p.fixBuffer(relPath, content, false)
+	result.Path = name
+
+	switch result.Action {
+	case models.ActionUpdated:
+		countDiffSize(&p.statistics, content, newContent)
+		result.Diff = p.changeDiff(name, content, newContent)
+		p.statistics.Processed++
+		p.statistics.Updated++
+		countChange(&p.statistics, result.Reason)
+	case models.ActionUnchanged:
+		p.statistics.Processed++
+		p.statistics.Skipped++
+	case models.ActionError:
+		p.statistics.Processed++
+		p.statistics.Errors++
+	default:
+		p.statistics.Skipped++
+	}
+
+	if p.options.Verbose {
+		switch result.Action {
+		case models.ActionUpdated:
+			if p.options.DryRun {
+				p.printf("Would update: %s\n", name)
+			} else {
+				p.printf("Updated: %s\n", name)
+			}
+		case models.ActionUnchanged:
+			p.printf("No changes needed: %s\n", name)
+		case models.ActionError:
+			p.errorf("Error processing file %s: %s\n", name, result.Error)
+		default:
+			p.printf("Skipping %s (%s)\n", name, result.Reason)
+		}
+	}
+	var err error
+	if result.Action == models.ActionError {
+		err = errors.New(result.Error)
+	}
+	p.addResult(result, err)
+	if err != nil {
+		p.failures = append(p.failures, &FileError{Path: name, Err: err})
+		return newContent, p.stopOnError(name, err)
+	}
+	return newContent, nil
+}
@@ -0,0 +1,157 @@
+// File: pkg/processor/archive_test.go
+package processor
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// archiveTestFiles are the entries of the test archives, under a top-level directory
+var archiveTestFiles = []struct {
+	name     string
+	content  string
+	expected string
+}{
+	{"project-1.0/main.go", "package main\n", "// File: main.go\npackage main\n"},
+	{"project-1.0/lib/util.py", "# File: lib/util.py\nx = 1\n", "# File: lib/util.py\nx = 1\n"},
+	{"project-1.0/data.bin", "\x00\x01\x02", "\x00\x01\x02"},
+}
+
+var archiveModTime = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestProcessTarArchive(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-archive-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Build a gzip-compressed tarball
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	gw.Header.Name = "src.tar"
+	tw := tar.NewWriter(gw)
+	for _, f := range archiveTestFiles {
+		tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0755, Size: int64(len(f.content)), ModTime: archiveModTime, Typeflag: tar.TypeReg})
+		tw.Write([]byte(f.content))
+	}
+	tw.Close()
+	gw.Close()
+	input := filepath.Join(tempDir, "src.tar.gz")
+	output := filepath.Join(tempDir, "fixed.tar.gz")
+	os.WriteFile(input, buf.Bytes(), 0644)
+
+	processor := NewProcessor(tempDir, &Options{})
+	stats, err := processor.ProcessArchive(input, output)
+	if err != nil {
+		t.Fatalf("ProcessArchive failed: %v", err)
+	}
+	if stats.Updated != 1 || stats.Errors != 0 {
+		t.Errorf("ProcessArchive() = %+v, expected 1 update and no errors", stats)
+	}
+
+	file, err := os.Open(output)
+	if err != nil {
+		t.Fatalf("Failed to open output archive: %v", err)
+	}
+	defer file.Close()
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to read output archive: %v", err)
+	}
+	if gr.Header.Name != "src.tar" {
+		t.Errorf("gzip header name = %q, expected %q", gr.Header.Name, "src.tar")
+	}
+	tr := tar.NewReader(gr)
+	for _, f := range archiveTestFiles {
+		header, err := tr.Next()
+		if err != nil {
+			t.Fatalf("Failed to read entry %s: %v", f.name, err)
+		}
+		content, _ := io.ReadAll(tr)
+		if header.Name != f.name || string(content) != f.expected {
+			t.Errorf("entry %s = %s %q, expected %q", f.name, header.Name, content, f.expected)
+		}
+		if header.Mode != 0755 || !header.ModTime.Equal(archiveModTime) {
+			t.Errorf("entry %s metadata = %o %v, expected %o %v", f.name, header.Mode, header.ModTime, 0755, archiveModTime)
+		}
+	}
+}
+
+func TestProcessZipArchive(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-archive-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range archiveTestFiles {
+		w, _ := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: archiveModTime})
+		w.Write([]byte(f.content))
+	}
+	zw.SetComment("release 1.0")
+	zw.Close()
+	input := filepath.Join(tempDir, "src.zip")
+	output := filepath.Join(tempDir, "fixed.zip")
+	os.WriteFile(input, buf.Bytes(), 0644)
+
+	// A dry run writes nothing
+	processor := NewProcessor(tempDir, &Options{DryRun: true})
+	if _, err := processor.ProcessArchive(input, output); err != nil {
+		t.Fatalf("ProcessArchive failed: %v", err)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", output)
+	}
+
+	processor = NewProcessor(tempDir, &Options{})
+	if _, err := processor.ProcessArchive(input, output); err != nil {
+		t.Fatalf("ProcessArchive failed: %v", err)
+	}
+	reader, err := zip.OpenReader(output)
+	if err != nil {
+		t.Fatalf("Failed to read output archive: %v", err)
+	}
+	defer reader.Close()
+	if reader.Comment != "release 1.0" {
+		t.Errorf("archive comment = %q, expected %q", reader.Comment, "release 1.0")
+	}
+	for i, f := range archiveTestFiles {
+		entry := reader.File[i]
+		content, _ := readZipEntry(entry)
+		if entry.Name != f.name || string(content) != f.expected {
+			t.Errorf("entry %s = %s %q, expected %q", f.name, entry.Name, content, f.expected)
+		}
+		if !entry.Modified.Equal(archiveModTime) {
+			t.Errorf("entry %s modified = %v, expected %v", f.name, entry.Modified, archiveModTime)
+		}
+	}
+}
+
+func TestArchiveTopDir(t *testing.T) {
+	tests := []struct {
+		names    []string
+		expected string
+	}{
+		{[]string{"a/x.go", "a/b/y.go"}, "a/"},
+		{[]string{"./a/x.go", "a/y.go"}, "a/"},
+		{[]string{"a/x.go", "b/y.go"}, ""},
+		{[]string{"a/x.go", "y.go"}, ""},
+		{nil, ""},
+	}
+
+	for _, test := range tests {
+		if result := archiveTopDir(test.names); result != test.expected {
+			t.Errorf("archiveTopDir(%v) = %q, expected %q", test.names, result, test.expected)
+		}
+	}
+}
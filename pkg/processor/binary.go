@@ -0,0 +1,295 @@
+// File: pkg/processor/binary.go
+package processor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultBinarySampleSize is how many leading bytes are inspected when
+// deciding whether a file is binary (the same window git uses)
+const DefaultBinarySampleSize = 8000
+
+// controlRatioThreshold is the share of control characters above which a
+// NUL-free sample is considered binary
+const controlRatioThreshold = 0.3
+
+// binaryVerdict describes the outcome of content sniffing
+type binaryVerdict struct {
+	Text     bool   // Whether the file looks like text
+	Encoding string // Wide text encoding, transcoded for processing; empty for byte-oriented text
+	Reason   string // Why the verdict was reached
+}
+
+// Byte order marks of wide text encodings. UTF-32 marks must be checked
+// first because the UTF-32LE mark begins with the UTF-16LE one.
+var wideBOMs = []struct {
+	bom      []byte
+	encoding string
+}{
+	{[]byte{0xFF, 0xFE, 0x00, 0x00}, "UTF-32LE"},
+	{[]byte{0x00, 0x00, 0xFE, 0xFF}, "UTF-32BE"},
+	{[]byte{0xFF, 0xFE}, "UTF-16LE"},
+	{[]byte{0xFE, 0xFF}, "UTF-16BE"},
+}
+
+// isBinaryFile checks if a file is likely to be binary
+func isBinaryFile(path string) bool {
+	return !sniffFile(path, DefaultBinarySampleSize).Text
+}
+
+// knownBinaryExtensions are skipped without reading the file
+var knownBinaryExtensions = []string{
+	// Images and fonts
+	".png", ".jpg", ".jpeg", ".gif", ".bmp", ".ico", ".webp", ".tif", ".tiff", ".psd",
+	".woff", ".woff2", ".ttf", ".otf", ".eot",
+	// Archives and packages
+	".zip", ".tar", ".gz", ".tgz", ".tar.gz", ".bz2", ".xz", ".7z", ".rar", ".jar", ".war", ".whl", ".nupkg",
+	// Documents and media
+	".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".mp3", ".mp4", ".mov", ".avi", ".wav",
+	// Compiled artifacts
+	".exe", ".dll", ".so", ".dylib", ".a", ".lib", ".o", ".obj", ".class", ".pyc", ".wasm", ".pdb",
+}
+
+// hasBinaryExtension reports whether a file name ends in a known binary
+// extension. Extensions configured as text take precedence.
+func (p *Processor) hasBinaryExtension(name string) bool {
+	name = strings.ToLower(name)
+	if containsExtension(p.config.TextExtensions, filepath.Ext(name)) {
+		return false
+	}
+	for _, ext := range knownBinaryExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyFile decides whether a file is text, honoring the configured
+// per-extension overrides before falling back to content sniffing
+func (p *Processor) classifyFile(path string) binaryVerdict {
+	if verdict, ok := p.extensionVerdict(path); ok {
+		return verdict
+	}
+	sample, err := readSample(p.openFile, path, p.config.BinarySampleSize)
+	if err != nil {
+		return binaryVerdict{Text: true, Reason: "unreadable"}
+	}
+	return p.classifyContent(path, sample)
+}
+
+// extensionVerdict applies the configured per-extension overrides
+func (p *Processor) extensionVerdict(name string) (binaryVerdict, bool) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if containsExtension(p.config.BinaryExtensions, ext) {
+		return binaryVerdict{Reason: "configured as binary extension"}, true
+	}
+	if containsExtension(p.config.TextExtensions, ext) {
+		return binaryVerdict{Text: true, Reason: "configured as text extension"}, true
+	}
+	return binaryVerdict{}, false
+}
+
+// classifyContent is classifyFile for the named file's content, of which
+// only the configured sample size is inspected
+func (p *Processor) classifyContent(name string, content []byte) binaryVerdict {
+	if verdict, ok := p.extensionVerdict(name); ok {
+		return verdict
+	}
+
+	sampleSize := p.config.BinarySampleSize
+	if sampleSize <= 0 {
+		sampleSize = DefaultBinarySampleSize
+	}
+	sample := content
+	if len(sample) > sampleSize {
+		sample = sample[:sampleSize]
+	}
+	verdict := sniffContent(sample)
+
+	// Formats such as PDF can look like text; recognize them by signature
+	if verdict.Text && verdict.Encoding == "" && p.config.DetectContentType {
+		if contentType, ok := binaryContentType(sample); ok {
+			return binaryVerdict{Reason: "binary(" + contentType + ")"}
+		}
+	}
+	return verdict
+}
+
+// containsExtension reports whether ext is in list, with or without the leading dot
+func containsExtension(list []string, ext string) bool {
+	if ext == "" {
+		return false
+	}
+	for _, entry := range list {
+		entry = strings.ToLower(entry)
+		if !strings.HasPrefix(entry, ".") {
+			entry = "." + entry
+		}
+		if entry == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffFile inspects the first sampleSize bytes of a file. Unreadable
+// files are reported as text so that the error surfaces when processing.
+func sniffFile(path string, sampleSize int) binaryVerdict {
+	sample, err := readSample(openDisk, path, sampleSize)
+	if err != nil {
+		return binaryVerdict{Text: true, Reason: "unreadable"}
+	}
+	return sniffContent(sample)
+}
+
+// openDisk opens a file on disk for readSample
+func openDisk(path string) (fs.File, error) {
+	return os.Open(path)
+}
+
+// readSample reads up to sampleSize leading bytes of a file opened with open
+func readSample(open func(string) (fs.File, error), path string, sampleSize int) ([]byte, error) {
+	if sampleSize <= 0 {
+		sampleSize = DefaultBinarySampleSize
+	}
+
+	// Open file
+	file, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, sampleSize)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// sniffContent classifies a content sample as text or binary
+func sniffContent(sample []byte) binaryVerdict {
+	// Wide encodings contain NULs but are still text
+	for _, w := range wideBOMs {
+		if bytes.HasPrefix(sample, w.bom) {
+			return binaryVerdict{Text: true, Encoding: w.encoding, Reason: w.encoding + " byte order mark"}
+		}
+	}
+
+	// Check for null bytes which would indicate binary, unless they are the
+	// high bytes of UTF-16 text without a byte order mark
+	if bytes.IndexByte(sample, 0) != -1 {
+		if encoding, ok := detectUTF16(sample); ok {
+			return binaryVerdict{Text: true, Encoding: encoding, Reason: encoding + " text without byte order mark"}
+		}
+		return binaryVerdict{Reason: "contains NUL bytes"}
+	}
+
+	// Many NUL-free formats (some images, fonts) are still mostly control bytes
+	if len(sample) > 0 {
+		control := 0
+		for _, b := range sample {
+			if isControlByte(b) {
+				control++
+			}
+		}
+		if float64(control)/float64(len(sample)) > controlRatioThreshold {
+			return binaryVerdict{Reason: "high ratio of control characters"}
+		}
+	}
+
+	return binaryVerdict{Text: true, Reason: "text"}
+}
+
+// isControlByte reports whether b is a control character not normally found in text
+func isControlByte(b byte) bool {
+	switch b {
+	case '\t', '\n', '\r', '\f', '\v', 0x1B: // 0x1B (ESC) appears in ANSI-colored text
+		return false
+	}
+	return b < 0x20 || b == 0x7F
+}
+
+// Signatures of executables and archives that http.DetectContentType does not
+// know. Those short enough to start a text file, such as "MZ", are checked
+// further by valid.
+var magicNumbers = []struct {
+	magic       []byte
+	contentType string
+	valid       func(sample []byte) bool
+}{
+	{[]byte("\x7fELF"), "application/x-elf", nil},
+	{[]byte("MZ"), "application/x-msdownload", isPortableExecutable},
+	{[]byte{0xFE, 0xED, 0xFA, 0xCE}, "application/x-mach-binary", nil},
+	{[]byte{0xFE, 0xED, 0xFA, 0xCF}, "application/x-mach-binary", nil},
+	{[]byte{0xCE, 0xFA, 0xED, 0xFE}, "application/x-mach-binary", nil},
+	{[]byte{0xCF, 0xFA, 0xED, 0xFE}, "application/x-mach-binary", nil},
+	{[]byte{0xCA, 0xFE, 0xBA, 0xBE}, "application/java-vm", nil},
+	{[]byte("7z\xBC\xAF\x27\x1C"), "application/x-7z-compressed", nil},
+	{[]byte{0xFD, '7', 'z', 'X', 'Z', 0x00}, "application/x-xz", nil},
+	{[]byte("BZh"), "application/x-bzip2", isBzip2},
+	{[]byte{0x28, 0xB5, 0x2F, 0xFD}, "application/zstd", nil},
+	{[]byte("SQLite format 3\x00"), "application/vnd.sqlite3", nil},
+}
+
+// isPortableExecutable reports whether a sample starting with "MZ" has the
+// DOS header of a Windows executable, whose offset at 0x3C points to the
+// "PE" signature within the sample
+func isPortableExecutable(sample []byte) bool {
+	if len(sample) < 0x40 {
+		return false
+	}
+	offset := int(binary.LittleEndian.Uint32(sample[0x3C:]))
+	return offset >= 0x40 && offset <= len(sample)-4 && bytes.Equal(sample[offset:offset+4], []byte("PE\x00\x00"))
+}
+
+// isBzip2 reports whether a sample starting with "BZh" goes on with the
+// block size, a digit from 1 to 9
+func isBzip2(sample []byte) bool {
+	return len(sample) > 3 && sample[3] >= '1' && sample[3] <= '9'
+}
+
+// binaryApplicationTypes are non-media types from http.DetectContentType that denote binary content
+var binaryApplicationTypes = map[string]bool{
+	"application/pdf":               true,
+	"application/zip":               true,
+	"application/x-gzip":            true,
+	"application/x-rar-compressed":  true,
+	"application/wasm":              true,
+	"application/ogg":               true,
+	"application/vnd.ms-fontobject": true,
+}
+
+// binaryContentType identifies images, archives, executables and documents
+// by their content signature, regardless of file extension
+func binaryContentType(sample []byte) (string, bool) {
+	for _, m := range magicNumbers {
+		if bytes.HasPrefix(sample, m.magic) && (m.valid == nil || m.valid(sample)) {
+			return m.contentType, true
+		}
+	}
+
+	contentType := http.DetectContentType(sample)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	switch {
+	case contentType == "image/svg+xml":
+		return "", false
+	case strings.HasPrefix(contentType, "image/"), strings.HasPrefix(contentType, "audio/"),
+		strings.HasPrefix(contentType, "video/"), strings.HasPrefix(contentType, "font/"):
+		return contentType, true
+	case binaryApplicationTypes[contentType]:
+		return contentType, true
+	}
+	return "", false
+}
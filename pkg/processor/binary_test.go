@@ -4,6 +4,7 @@ package processor
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -60,4 +61,121 @@ func TestNonExistentFile(t *testing.T) {
 	if result {
 		t.Errorf("isBinaryFile should return false for non-existent files")
 	}
-}
\ No newline at end of file
+}
+
+func TestSniffContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		sample   []byte
+		text     bool
+		encoding string
+	}{
+		{"plain text", []byte("package main\n\nfunc main() {}\n"), true, ""},
+		{"ansi colors", []byte("\x1b[31mred\x1b[0m\n"), true, ""},
+		{"utf-16le", []byte{0xFF, 0xFE, 'h', 0x00, 'i', 0x00}, true, "UTF-16LE"},
+		{"utf-16be", []byte{0xFE, 0xFF, 0x00, 'h', 0x00, 'i'}, true, "UTF-16BE"},
+		{"utf-32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00}, true, "UTF-32LE"},
+		{"utf-16le without bom", []byte{'/', 0x00, '/', 0x00, ' ', 0x00, 'h', 0x00, 'i', 0x00, '\n', 0x00}, true, "UTF-16LE"},
+		{"utf-16be without bom", []byte{0x00, 'h', 0x00, 'i', 0x00, '\r', 0x00, '\n'}, true, "UTF-16BE"},
+		{"nul-padded records", []byte{'a', 0x00, 0x00, 0x00, 'b', 0x00, 0x00, 0x00}, false, ""},
+		{"nul bytes", []byte("Hello\x00World"), false, ""},
+		{"control bytes", []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A, 0x01, 0x02, 0x03, 0x04, 0x05}, false, ""},
+	}
+
+	for _, test := range tests {
+		verdict := sniffContent(test.sample)
+		if verdict.Text != test.text || verdict.Encoding != test.encoding {
+			t.Errorf("sniffContent(%s) = %+v, expected text=%v encoding=%q",
+				test.name, verdict, test.text, test.encoding)
+		}
+		if verdict.Reason == "" {
+			t.Errorf("sniffContent(%s) returned no reason", test.name)
+		}
+	}
+}
+
+func TestExtensionOverrides(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "override-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A proprietary text format with embedded NULs
+	recordFile := filepath.Join(tempDir, "data.rec")
+	if err := os.WriteFile(recordFile, []byte("# records\nA\x00B\n"), 0644); err != nil {
+		t.Fatalf("Failed to write record file: %v", err)
+	}
+
+	// A plain text file that should never be touched
+	lockFile := filepath.Join(tempDir, "deps.lock")
+	if err := os.WriteFile(lockFile, []byte("version = 1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+
+	p := NewProcessor(tempDir, &Options{})
+	p.config.TextExtensions = []string{".REC"}
+	p.config.BinaryExtensions = []string{"lock"}
+
+	if verdict := p.classifyFile(recordFile); !verdict.Text {
+		t.Errorf("Expected %s to be forced to text, got: %+v", recordFile, verdict)
+	}
+	if verdict := p.classifyFile(lockFile); verdict.Text {
+		t.Errorf("Expected %s to be forced to binary, got: %+v", lockFile, verdict)
+	}
+}
+
+func TestBinaryContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		sample      []byte
+		contentType string
+		binary      bool
+	}{
+		{"pdf", []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\n"), "application/pdf", true},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png", true},
+		{"elf", []byte("\x7fELF\x02\x01\x01"), "application/x-elf", true},
+		{"zip", []byte("PK\x03\x04\x14\x00"), "application/zip", true},
+		{"exe", []byte("MZ\x90\x00" + strings.Repeat("\x00", 0x38) + "\x40\x00\x00\x00PE\x00\x00\x4c\x01"), "application/x-msdownload", true},
+		{"mz text", []byte("MZ files are listed below\n"), "", false},
+		{"mz bad offset", []byte("MZ" + strings.Repeat(" ", 0x3A) + "\xff\xff\x00\x00"), "", false},
+		{"bzip2", []byte("BZh91AY&SY"), "application/x-bzip2", true},
+		{"bzh text", []byte("BZh is not a block size\n"), "", false},
+		{"go source", []byte("package main\n"), "", false},
+		{"xml", []byte("<?xml version=\"1.0\"?>\n<root/>\n"), "", false},
+	}
+
+	for _, test := range tests {
+		contentType, binary := binaryContentType(test.sample)
+		if binary != test.binary || contentType != test.contentType {
+			t.Errorf("binaryContentType(%s) = %q, %v, expected %q, %v",
+				test.name, contentType, binary, test.contentType, test.binary)
+		}
+	}
+}
+
+func TestKnownBinaryExtensions(t *testing.T) {
+	p := NewProcessor(".", &Options{})
+	p.config.TextExtensions = []string{".obj"} // Wavefront OBJ models are text
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"logo.PNG", true},
+		{"release.tar.gz", true},
+		{"libfoo.so", true},
+		{"Main.class", true},
+		{"main.go", false},
+		{"archive.tar.gz.go", false},
+		{"model.obj", false},
+	}
+
+	for _, test := range tests {
+		result := p.hasBinaryExtension(test.name)
+		if result != test.expected {
+			t.Errorf("hasBinaryExtension(%s) = %v, expected %v", test.name, result, test.expected)
+		}
+	}
+}
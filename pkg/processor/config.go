@@ -2,41 +2,194 @@
 package processor
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/yourusername/pathfix/pkg/models"
 )
 
 // LoadConfig loads configuration from the specified file
 func LoadConfig(configPath string) (*models.Config, error) {
+	return LoadUserConfig("", configPath)
+}
+
+// UserConfigFiles are the names the per-user config can have, in order of
+// precedence
+var UserConfigFiles = []string{"config.json", "config.yaml", "config.yml"}
+
+// UserConfigPath returns where personal defaults are read from: the first
+// of UserConfigFiles in the pathfix directory under $XDG_CONFIG_HOME
+// (~/.config when unset) on Unix, the Application Support folder on macOS
+// and %AppData% on Windows, or config.json when there is none. It returns
+// "" when there is no home directory.
+func UserConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	dir = filepath.Join(dir, "pathfix")
+	for _, name := range UserConfigFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return filepath.Join(dir, name)
+		}
+	}
+	return filepath.Join(dir, UserConfigFiles[0])
+}
+
+// LoadUserConfig loads the config file at configPath on top of the per-user
+// config at userPath. Settings in the project's file win, except that lists
+// of patterns and extensions are combined and FileTypes and Validators are
+// merged by key. Either path may be empty, and a missing user config is not
+// an error.
+func LoadUserConfig(userPath, configPath string) (*models.Config, error) {
+	config, _, _, err := loadConfigFiles(userPath, configPath, true)
+	return config, err
+}
+
+// commandSettings are the settings that run commands
+var commandSettings = []string{"Hooks", "Plugins", "Validators"}
+
+// loadConfigFiles is LoadUserConfig for a config file that may not be
+// trusted. Unless trusted is set or the user config's TrustedConfigs holds
+// the directory of configPath or one above it, the settings of configPath
+// that run commands are left out, and their names are returned. confined
+// reports that the LicenseFile comes from such a file, so it must be inside
+// the root directory.
+func loadConfigFiles(userPath, configPath string, trusted bool) (config *models.Config, ignored []string, confined bool, err error) {
 	// Default configuration
-	config := &models.Config{
-		CommentPrefix:     "File: ",
-		FileTypes:         make(map[string]models.CommentStyle),
-		AdditionalIgnores: []string{},
+	config = models.NewConfig()
+
+	merged := map[string]interface{}{}
+	if userPath != "" {
+		user, err := readConfigObject(userPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, false, fmt.Errorf("user config %s: %w", userPath, err)
+		}
+		if user != nil {
+			merged = user
+		}
+	}
+	if configPath != "" {
+		project, err := readConfigObject(configPath)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !trusted && !trustedConfig(merged["TrustedConfigs"], configPath) {
+			for _, key := range commandSettings {
+				if _, ok := project[key]; ok {
+					delete(project, key)
+					ignored = append(ignored, key)
+				}
+			}
+			_, confined = project["LicenseFile"]
+		}
+		delete(project, "TrustedConfigs")
+		mergeConfigObjects(merged, project)
+	}
+
+	// Both layers are in the current schema, so the result decodes directly
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("error parsing config file: %w", err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, nil, false, fmt.Errorf("error parsing config file: %w", err)
 	}
+	return config, ignored, confined, nil
+}
 
-	// If no config file specified, return defaults
-	if configPath == "" {
-		return config, nil
+// trustedConfig reports whether the TrustedConfigs setting of the user
+// config lists the directory of configPath or one above it
+func trustedConfig(setting interface{}, configPath string) bool {
+	dirs, _ := setting.([]interface{})
+	configDir, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		return false
+	}
+	for _, entry := range dirs {
+		dir, ok := entry.(string)
+		if !ok || !filepath.IsAbs(dir) {
+			continue
+		}
+		if rel, err := filepath.Rel(filepath.Clean(dir), configDir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
 	}
+	return false
+}
 
-	// Read config file
+// readConfigObject reads a config file, upgrades it to the current schema
+// and decodes it as a JSON object. Files ending in .yaml or .yml hold the
+// same settings in YAML.
+func readConfigObject(configPath string) (map[string]interface{}, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
+	if ext := strings.ToLower(filepath.Ext(configPath)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("error parsing config file: %w", err)
+		}
+	}
 
-	// Parse JSON
-	err = json.Unmarshal(data, config)
+	// Upgrade files written for an older schema
+	data, _, err = MigrateConfig(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing config file: %w", err)
 	}
 
-	return config, nil
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+	return object, nil
+}
+
+// yamlToJSON converts a YAML document to JSON, so that it goes through the
+// same schema upgrades and decoding as a JSON config
+func yamlToJSON(data []byte) ([]byte, error) {
+	var object map[string]interface{}
+	if err := yaml.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	if object == nil {
+		object = map[string]interface{}{}
+	}
+	return json.Marshal(object)
+}
+
+// mergeConfigObjects applies the settings of override on top of base
+func mergeConfigObjects(base, override map[string]interface{}) {
+	for key, value := range override {
+		switch key {
+		case "AdditionalIgnores", "Protected", "TextExtensions", "BinaryExtensions", "JSONCommentPaths":
+			if list, ok := base[key].([]interface{}); ok {
+				if extra, ok := value.([]interface{}); ok {
+					value = append(list, extra...)
+				}
+			}
+		case "FileTypes", "FileNames", "Validators", "PathPrefixes":
+			if entries, ok := base[key].(map[string]interface{}); ok {
+				if extra, ok := value.(map[string]interface{}); ok {
+					for name, entry := range extra {
+						entries[name] = entry
+					}
+					value = entries
+				}
+			}
+		}
+		base[key] = value
+	}
 }
 
 // SaveConfig saves configuration to the specified file
@@ -69,11 +222,56 @@ func MergeConfig(config *models.Config, defaultFileTypes map[string]models.Comme
 	} else {
 		// Otherwise, merge with defaults
 		for ext, style := range defaultFileTypes {
-			if _, ok := config.FileTypes[ext]; !ok {
+			override, ok := config.FileTypes[ext]
+			if !ok {
 				config.FileTypes[ext] = style
+				continue
 			}
+			// Partial overrides (e.g. only "Preferred") inherit the remaining fields
+			config.FileTypes[ext] = mergeCommentStyle(override, style)
 		}
 	}
 
 	return config
-}
\ No newline at end of file
+}
+
+// mergeFileNames returns the FileNames of a config combined with the
+// defaults; entries for a default pattern may be partial, as in FileTypes
+func mergeFileNames(fileNames, defaults map[string]models.CommentStyle) map[string]models.CommentStyle {
+	merged := make(map[string]models.CommentStyle, len(defaults)+len(fileNames))
+	for pattern, style := range defaults {
+		merged[pattern] = style
+	}
+	for pattern, style := range fileNames {
+		if base, ok := defaults[pattern]; ok {
+			style = mergeCommentStyle(style, base)
+		}
+		merged[pattern] = style
+	}
+	return merged
+}
+
+// mergeCommentStyle fills the empty fields of override from base
+func mergeCommentStyle(override, base models.CommentStyle) models.CommentStyle {
+	if override.LineComment == "" && override.BlockCommentStart == "" && override.BlockCommentEnd == "" {
+		override.LineComment = base.LineComment
+		override.BlockCommentStart = base.BlockCommentStart
+		override.BlockCommentEnd = base.BlockCommentEnd
+	}
+	if override.Preferred == "" {
+		override.Preferred = base.Preferred
+	}
+	if override.Placement == "" {
+		override.Placement = base.Placement
+	}
+	if override.HeaderPrefix == "" {
+		override.HeaderPrefix = base.HeaderPrefix
+	}
+	if override.HeaderSuffix == "" {
+		override.HeaderSuffix = base.HeaderSuffix
+	}
+	if override.Dialect == "" {
+		override.Dialect = base.Dialect
+	}
+	return override
+}
@@ -0,0 +1,277 @@
+// File: pkg/processor/config_test.go
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestLoadUserConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-userconfig-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	userPath := filepath.Join(tempDir, "user.json")
+	projectPath := filepath.Join(tempDir, "project.json")
+	files := map[string]string{
+		userPath:    `{"Version": 1, "Verbose": true, "CommentPrefix": "Path: ", "AdditionalIgnores": ["vendor/"], "FileTypes": {".rs": {"LineComment": "//"}, ".py": {"LineComment": "#"}}}`,
+		projectPath: `{"CommentPrefix": "File: ", "AdditionalIgnores": ["dist/"], "FileTypes": {".rs": {"LineComment": "///"}}}`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	// The project's settings win, lists are combined and file types merged
+	config, err := LoadUserConfig(userPath, projectPath)
+	if err != nil {
+		t.Fatalf("LoadUserConfig failed: %v", err)
+	}
+	if config.CommentPrefix != "File: " || !config.Verbose {
+		t.Errorf("LoadUserConfig() prefix = %q, verbose = %v, expected %q, true", config.CommentPrefix, config.Verbose, "File: ")
+	}
+	if expected := []string{"vendor/", "dist/"}; !reflect.DeepEqual(config.AdditionalIgnores, expected) {
+		t.Errorf("LoadUserConfig() ignores = %v, expected %v", config.AdditionalIgnores, expected)
+	}
+	if config.FileTypes[".rs"].LineComment != "///" || config.FileTypes[".py"].LineComment != "#" {
+		t.Errorf("LoadUserConfig() file types = %v, expected .rs from the project and .py from the user", config.FileTypes)
+	}
+
+	// A missing user config is not an error, a missing project config is
+	if config, err := LoadUserConfig(filepath.Join(tempDir, "missing.json"), projectPath); err != nil || config.CommentPrefix != "File: " {
+		t.Errorf("LoadUserConfig(missing user config) = %v, %v, expected the project config", config, err)
+	}
+	if _, err := LoadUserConfig(userPath, filepath.Join(tempDir, "missing.json")); err == nil {
+		t.Errorf("LoadUserConfig(missing project config) succeeded, expected an error")
+	}
+}
+
+func TestUserConfigPath(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the user config directory does not follow XDG_CONFIG_HOME")
+	}
+	tempDir, err := os.MkdirTemp("", "pathfix-userpath-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	t.Setenv("XDG_CONFIG_HOME", tempDir)
+	dir := filepath.Join(tempDir, "pathfix")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", dir, err)
+	}
+
+	// config.json is expected when there is none, and wins over YAML
+	tests := []struct {
+		create   string
+		expected string
+	}{
+		{"", "config.json"},
+		{"config.yml", "config.yml"},
+		{"config.yaml", "config.yaml"},
+		{"config.json", "config.json"},
+	}
+	for _, test := range tests {
+		if test.create != "" {
+			if err := os.WriteFile(filepath.Join(dir, test.create), []byte("Workers: 3\n"), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", test.create, err)
+			}
+		}
+		if result := UserConfigPath(); result != filepath.Join(dir, test.expected) {
+			t.Errorf("UserConfigPath() with %s = %q, expected %s", test.create, result, test.expected)
+		}
+	}
+
+	// A YAML user config sets personal defaults such as Workers
+	os.Remove(filepath.Join(dir, "config.json"))
+	p := NewProcessor(tempDir, &Options{UserConfig: true})
+	if p.ConfigError() != nil || p.config.Workers != 3 {
+		t.Errorf("NewProcessor() with config.yaml = %v, Workers %d, expected 3", p.ConfigError(), p.config.Workers)
+	}
+}
+
+func TestFoundConfigCommands(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-trust-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	repo := filepath.Join(tempDir, "repo")
+	if err := os.Mkdir(repo, 0755); err != nil {
+		t.Fatalf("Failed to create repo: %v", err)
+	}
+	configPath := filepath.Join(repo, ".pathfix.json")
+	config := `{"CommentPrefix": "Path: ", "Hooks": {"PreRun": ["touch", "pwned"]}, "Plugins": [{"Command": ["veto"]}], "Validators": {".go": ["vet"]}}`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	hasCommands := func(config *models.Config) bool {
+		return len(config.Hooks.PreRun) > 0 && len(config.Plugins) > 0 && len(config.Validators) > 0
+	}
+
+	// A found config keeps its other settings but runs no commands
+	var stderr strings.Builder
+	p := NewProcessor(repo, &Options{Stderr: &stderr})
+	if p.ConfigError() != nil || p.config.CommentPrefix != "Path: " || len(p.config.Hooks.PreRun) > 0 || len(p.config.Plugins) > 0 || len(p.config.Validators) > 0 {
+		t.Errorf("found config: error %v, config %+v, expected the prefix without commands", p.ConfigError(), p.config)
+	}
+	if !strings.Contains(stderr.String(), "Hooks, Plugins, Validators") {
+		t.Errorf("found config warning = %q, expected the ignored settings", stderr.String())
+	}
+
+	// Unless the user allows them or names the file
+	if p := NewProcessor(repo, &Options{AllowCommands: true}); !hasCommands(p.config) {
+		t.Errorf("found config with AllowCommands has no commands: %+v", p.config)
+	}
+	if p := NewProcessor(repo, &Options{ConfigFile: configPath}); !hasCommands(p.config) {
+		t.Errorf("named config has no commands: %+v", p.config)
+	}
+
+	// Or the user config trusts the directory or one above it
+	tests := []struct {
+		trusted  string
+		expected bool
+	}{
+		{repo, true},
+		{tempDir, true},
+		{filepath.Join(repo, "sub"), false},
+		{repo + "2", false},
+		{"repo", false},
+	}
+	for _, test := range tests {
+		userPath := filepath.Join(tempDir, "user.json")
+		data, _ := json.Marshal(map[string]interface{}{"TrustedConfigs": []string{test.trusted}})
+		if err := os.WriteFile(userPath, data, 0644); err != nil {
+			t.Fatalf("Failed to write user config: %v", err)
+		}
+		config, _, _, err := loadConfigFiles(userPath, configPath, false)
+		if err != nil {
+			t.Fatalf("loadConfigFiles failed: %v", err)
+		}
+		if hasCommands(config) != test.expected {
+			t.Errorf("loadConfigFiles() with TrustedConfigs %q has commands = %v, expected %v", test.trusted, hasCommands(config), test.expected)
+		}
+	}
+
+	// A project cannot trust itself
+	if err := os.WriteFile(configPath, []byte(`{"TrustedConfigs": ["/"], "Hooks": {"PreRun": ["touch", "pwned"]}}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if config, ignored, _, err := loadConfigFiles("", configPath, false); err != nil || len(config.Hooks.PreRun) > 0 || len(ignored) != 1 {
+		t.Errorf("loadConfigFiles(self-trusting config) = %+v, %v, %v, expected the hooks ignored", config.Hooks, ignored, err)
+	}
+}
+
+func TestAdditionalIgnores(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-ignores-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, name := range []string{"main.go", "vendor/lib.go", "gen/out.pb.go"} {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	configPath := filepath.Join(tempDir, "pathfix.json")
+	if err := os.WriteFile(configPath, []byte(`{"AdditionalIgnores": ["vendor/", "*.pb.go", "pathfix.json"]}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	processor := NewProcessor(tempDir, &Options{DryRun: true, ConfigFile: configPath})
+	if _, err := processor.Process(); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"main.go":       models.ReasonMissingHeader,
+		"vendor/lib.go": "ignored by config",
+		"gen/out.pb.go": "ignored by config",
+	}
+	found := 0
+	for _, result := range processor.Report().Results {
+		reason, ok := expected[result.Path]
+		if !ok {
+			continue
+		}
+		found++
+		if result.Reason != reason {
+			t.Errorf("Process(%s) reason = %q, expected %q", result.Path, result.Reason, reason)
+		}
+	}
+	if found != len(expected) {
+		t.Errorf("Process() reported %d of the files, expected %d", found, len(expected))
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-validate-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Every built-in style is complete
+	code, docs := DefaultFileTypes()
+	for _, fileTypes := range []map[string]models.CommentStyle{code, docs} {
+		for ext, style := range fileTypes {
+			if err := style.Validate(); err != nil {
+				t.Errorf("Validate(%s) = %v, expected nil", ext, err)
+			}
+		}
+	}
+
+	// Partial overrides of built-in types are completed before validation,
+	// but a new type must be complete
+	configPath := filepath.Join(tempDir, "config.json")
+	for content, valid := range map[string]bool{
+		`{"FileTypes": {".pas": {"Preferred": "block"}}}`:      true,
+		`{"FileTypes": {".foo": {"LineComment": "#"}}}`:        false,
+		`{"PathNormalization": "nfkc", "MaxChangedFiles": -1}`: false,
+	} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		p := NewProcessor(tempDir, &Options{ConfigFile: configPath})
+		if (p.ConfigError() == nil) != valid {
+			t.Errorf("NewProcessor(%s) config error = %v, expected valid %v", content, p.ConfigError(), valid)
+		}
+	}
+
+	// A programmatic config is used as given, without changing it
+	config := models.NewConfig().WithCommentPrefix("Path: ").WithFileType("foo", models.LineStyle("#"))
+	p := NewProcessor(tempDir, &Options{Config: config})
+	if p.ConfigError() != nil || p.config.CommentPrefix != "Path: " || p.fileTypes[".foo"].LineComment != "#" {
+		t.Errorf("NewProcessor(Config) = %v, %v, expected the built config", p.config, p.ConfigError())
+	}
+	if len(config.FileTypes) != 1 {
+		t.Errorf("NewProcessor(Config) changed the caller's file types to %d entries, expected 1", len(config.FileTypes))
+	}
+
+	// Reloading an invalid config keeps the current one
+	config.CommentPrefix = ""
+	if err := p.ReloadConfig(); err == nil || !errors.Is(err, models.ErrInvalidConfig) {
+		t.Errorf("ReloadConfig(invalid) = %v, expected an invalid config error", err)
+	}
+	if p.config.CommentPrefix != "Path: " {
+		t.Errorf("ReloadConfig(invalid) prefix = %q, expected %q", p.config.CommentPrefix, "Path: ")
+	}
+}
@@ -0,0 +1,47 @@
+// File: pkg/processor/count.go
+package processor
+
+import (
+	"context"
+	"errors"
+)
+
+// CountFiles returns the number of files a run would check: those that pass
+// the walk's filters, for which OnFileStart is called. None of them is read,
+// so counting costs a walk of the tree, and programs can show the progress
+// of the run that follows.
+func (p *Processor) CountFiles(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	count := 0
+	options := p.quietOptions()
+	options.ListOnly = true
+	options.Workers = 1
+	options.OnFileStart = func(string) { count++ }
+	counter := p.fork(&options)
+	counter.counting = true
+	_, err := counter.process(ctx)
+	var fileErrors FileErrors
+	if err != nil && !errors.As(err, &fileErrors) {
+		return count, err
+	}
+	return count, nil
+}
+
+// quietOptions returns a copy of the options for a run the processor makes
+// for itself, such as the change limits' plan, without the callbacks, log,
+// history or verbose output of the caller's run
+func (p *Processor) quietOptions() Options {
+	options := *p.options
+	options.Verbose = false
+	options.FailFast = false
+	options.History = false
+	options.OnResult = nil
+	options.OnFileStart = nil
+	options.OnFileUpdated = nil
+	options.OnFileSkipped = nil
+	options.OnError = nil
+	options.Log = nil
+	return options
+}
@@ -0,0 +1,50 @@
+// File: pkg/processor/count_test.go
+package processor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCountFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":   {Data: []byte("build/\n")},
+		"main.go":      {Data: []byte("package main\n")},
+		"lib/a.py":     {Data: []byte("# File: lib/a.py\nx = 1\n")},
+		"data.go":      {Data: []byte("\x00\x01\x02")},
+		"logo.png":     {Data: []byte("\x89PNG\r\n")},
+		"build/gen.go": {Data: []byte("package gen\n")},
+	}
+	root := filepath.FromSlash("/virtual")
+
+	// Files the walk filters out are not counted; those checked later are
+	started := 0
+	options := &Options{
+		DryRun:      true,
+		FS:          fsys,
+		OnFileStart: func(string) { started++ },
+	}
+	p := NewProcessor(root, options)
+	count, err := p.CountFiles(context.Background())
+	if err != nil || count != 3 {
+		t.Errorf("CountFiles() = %d, %v, expected 3", count, err)
+	}
+	if started != 0 || len(p.Results()) != 0 {
+		t.Errorf("CountFiles() called OnFileStart %d times and recorded %d results, expected none", started, len(p.Results()))
+	}
+
+	if _, err := p.Process(); err != nil {
+		t.Fatalf("Process() failed: %v", err)
+	}
+	if started != count {
+		t.Errorf("Process() called OnFileStart %d times, expected the %d files counted", started, count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.CountFiles(ctx); err == nil {
+		t.Errorf("CountFiles(canceled) succeeded, expected an error")
+	}
+}
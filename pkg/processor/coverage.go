@@ -0,0 +1,99 @@
+// File: pkg/processor/coverage.go
+package processor
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// Coverage summarizes the results of a dry run as header coverage. Files are
+// grouped by their directory, truncated to depth levels below the root (0
+// counts everything under "."), and by language. Skipped files are not
+// eligible; files that failed are eligible but not covered.
+func (p *Processor) Coverage(depth int) models.CoverageReport {
+	report := models.CoverageReport{
+		Root:        p.rootDir,
+		Directories: make(map[string]models.Coverage),
+		Languages:   make(map[string]models.Coverage),
+	}
+	for _, result := range p.results {
+		if result.Action == models.ActionSkipped {
+			continue
+		}
+		covered := result.Action == models.ActionUnchanged
+		report.Total = addCoverage(report.Total, covered)
+		dir := coverageDir(result.Path, depth)
+		report.Directories[dir] = addCoverage(report.Directories[dir], covered)
+		lang := Language(result.Path)
+		report.Languages[lang] = addCoverage(report.Languages[lang], covered)
+	}
+	report.Total.Percent = coveragePercent(report.Total)
+	return report
+}
+
+// addCoverage counts a file and updates the percentage
+func addCoverage(c models.Coverage, covered bool) models.Coverage {
+	c.Files++
+	if covered {
+		c.Covered++
+	}
+	c.Percent = coveragePercent(c)
+	return c
+}
+
+// coveragePercent returns the share of covered files, or 100 if there are none
+func coveragePercent(c models.Coverage) float64 {
+	if c.Files == 0 {
+		return 100
+	}
+	return float64(c.Covered) * 100 / float64(c.Files)
+}
+
+// coverageDir returns the directory of relPath truncated to depth levels
+func coverageDir(relPath string, depth int) string {
+	dir := path.Dir(relPath)
+	if dir == "." || depth <= 0 {
+		return "."
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
+
+// Language returns the language a file is grouped under in reports: the
+// lowercase extension of relPath, or its name if it has none
+func Language(relPath string) string {
+	if ext := path.Ext(relPath); ext != "" {
+		return strings.ToLower(ext)
+	}
+	return path.Base(relPath)
+}
+
+// CoverageBadge returns a shields.io endpoint badge showing the coverage
+// percentage. The percentage is rounded down, so 100% means every file.
+func CoverageBadge(label string, c models.Coverage) models.Badge {
+	percent := math.Floor(c.Percent)
+	color := "red"
+	switch {
+	case percent >= 95:
+		color = "brightgreen"
+	case percent >= 80:
+		color = "green"
+	case percent >= 60:
+		color = "yellow"
+	case percent >= 40:
+		color = "orange"
+	}
+	return models.Badge{
+		SchemaVersion: 1,
+		Label:         label,
+		Message:       fmt.Sprintf("%.0f%%", percent),
+		Color:         color,
+	}
+}
@@ -0,0 +1,103 @@
+// File: pkg/processor/coverage_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestCoverage(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-coverage-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"main.go":           "// File: main.go\npackage main\n",
+		"pkg/a/a.go":        "// File: pkg/a/a.go\npackage a\n",
+		"pkg/b/b.go":        "package b\n",
+		"scripts/build.py":  "print('hi')\n",
+		"scripts/image.png": "\x89PNG\r\n",
+		"scripts/notes.txt": "all:\n",
+		"scripts/deploy.sh": "# File: scripts/deploy.sh\necho hi\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	processor := NewProcessor(tempDir, &Options{DryRun: true})
+	if _, err := processor.Process(); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	report := processor.Coverage(1)
+
+	if report.Total.Files != 5 || report.Total.Covered != 3 || report.Total.Percent != 60 {
+		t.Errorf("Coverage(1).Total = %+v, expected 3 of 5 files (60%%)", report.Total)
+	}
+	expectedDirs := map[string]models.Coverage{
+		".":       {Files: 1, Covered: 1, Percent: 100},
+		"pkg":     {Files: 2, Covered: 1, Percent: 50},
+		"scripts": {Files: 2, Covered: 1, Percent: 50},
+	}
+	if len(report.Directories) != len(expectedDirs) {
+		t.Errorf("Coverage(1).Directories = %v, expected %v", report.Directories, expectedDirs)
+	}
+	for dir, want := range expectedDirs {
+		if got := report.Directories[dir]; got != want {
+			t.Errorf("Coverage(1).Directories[%s] = %+v, expected %+v", dir, got, want)
+		}
+	}
+	if got := report.Languages[".go"]; got.Files != 3 || got.Covered != 2 {
+		t.Errorf("Coverage(1).Languages[.go] = %+v, expected 2 of 3 files", got)
+	}
+}
+
+func TestCoverageDir(t *testing.T) {
+	tests := []struct {
+		path     string
+		depth    int
+		expected string
+	}{
+		{"main.go", 1, "."},
+		{"pkg/a/a.go", 0, "."},
+		{"pkg/a/a.go", 1, "pkg"},
+		{"pkg/a/a.go", 2, "pkg/a"},
+		{"pkg/a/a.go", 5, "pkg/a"},
+	}
+	for _, test := range tests {
+		if result := coverageDir(test.path, test.depth); result != test.expected {
+			t.Errorf("coverageDir(%s, %d) = %s, expected %s", test.path, test.depth, result, test.expected)
+		}
+	}
+}
+
+func TestCoverageBadge(t *testing.T) {
+	tests := []struct {
+		percent float64
+		message string
+		color   string
+	}{
+		{100, "100%", "brightgreen"},
+		{99.9, "99%", "brightgreen"},
+		{85, "85%", "green"},
+		{60, "60%", "yellow"},
+		{45.5, "45%", "orange"},
+		{0, "0%", "red"},
+	}
+	for _, test := range tests {
+		badge := CoverageBadge("path headers", models.Coverage{Percent: test.percent})
+		if badge.Message != test.message || badge.Color != test.color || badge.SchemaVersion != 1 {
+			t.Errorf("CoverageBadge(%v) = %+v, expected %s in %s", test.percent, badge, test.message, test.color)
+		}
+	}
+}
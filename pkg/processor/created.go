@@ -0,0 +1,119 @@
+// File: pkg/processor/created.go
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reasonPredates is the skip reason for files that existed before the
+// CreatedAfter cutoff
+const reasonPredates = "created before the cutoff"
+
+// createdAfter returns the cutoff before which files keep their headers as
+// they are, from the options or the config
+func (p *Processor) createdAfter() string {
+	if p.options.CreatedAfter != "" {
+		return p.options.CreatedAfter
+	}
+	return p.config.CreatedAfter
+}
+
+// filesBefore returns the files below the root that git already tracked at
+// the cutoff, which is a date or a revision. Paths are relative to the root
+// with forward slashes. It returns nil when there is no cutoff.
+func (p *Processor) filesBefore() (map[string]bool, error) {
+	cutoff := p.createdAfter()
+	if cutoff == "" {
+		return nil, nil
+	}
+
+	// A date selects the last commit before it; a revision is used as is
+	rev := cutoff + "^{commit}"
+	if date, ok := parseCutoffDate(cutoff); ok {
+		commit, err := p.commitBefore(date)
+		if err != nil {
+			return nil, fmt.Errorf("created-after %s: %w", cutoff, err)
+		}
+		if commit == "" {
+			// Nothing was committed yet, so every file is new
+			return map[string]bool{}, nil
+		}
+		rev = commit
+	}
+	out, err := runGit(p.rootDir, "rev-parse", "--verify", "--quiet", "--end-of-options", rev)
+	if err != nil {
+		return nil, fmt.Errorf("created-after %s is not a date (YYYY-MM-DD) or a revision of the git repository: %w", cutoff, err)
+	}
+
+	// Without --full-name, ls-tree lists the paths below the working
+	// directory relative to it
+	out, err = runGit(p.rootDir, "ls-tree", "-r", "-z", "--name-only", strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("created-after %s: %w", cutoff, err)
+	}
+	files := make(map[string]bool)
+	for _, name := range bytes.Split(out, []byte{0}) {
+		if len(name) > 0 {
+			files[p.pathKey(string(name))] = true
+		}
+	}
+	return files, nil
+}
+
+// commitBefore returns the newest commit of HEAD committed before date, or
+// "" if there is none. Dates are compared here rather than by git, whose
+// date parsing guesses at ambiguous input.
+func (p *Processor) commitBefore(date time.Time) (string, error) {
+	out, err := runGit(p.rootDir, "rev-list", "--timestamp", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		timestamp, commit, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		if seconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil && seconds < date.Unix() {
+			return commit, nil
+		}
+	}
+	return "", nil
+}
+
+// pathKey returns relPath in the form used to look it up in path sets,
+// folding case when matching is case-insensitive
+func (p *Processor) pathKey(relPath string) string {
+	if p.config.IgnoreCase {
+		return strings.ToLower(relPath)
+	}
+	return relPath
+}
+
+// parseCutoffDate parses a date given as YYYY-MM-DD (midnight UTC) or in
+// RFC 3339 format
+func parseCutoffDate(s string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if date, err := time.Parse(layout, s); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// runGit runs a git command in dir and returns its output
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,82 @@
+// File: pkg/processor/created_test.go
+package processor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreatedAfter(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tempDir, err := os.MkdirTemp("", "pathfix-created-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	t.Setenv("GIT_AUTHOR_NAME", "pathfix")
+	t.Setenv("GIT_AUTHOR_EMAIL", "pathfix@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "pathfix")
+	t.Setenv("GIT_COMMITTER_EMAIL", "pathfix@example.com")
+
+	// old.go is committed in 2020 and tagged; new.go comes later and
+	// untracked.go is not committed at all
+	write := func(name string) {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte("package src\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	commit := func(date string) {
+		for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", "add", "--date", date}} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = tempDir
+			cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v: %s", args, err, out)
+			}
+		}
+	}
+	if _, err := runGit(tempDir, "init", "-q"); err != nil {
+		t.Fatalf("%v", err)
+	}
+	write("src/old.go")
+	commit("2020-01-01T12:00:00Z")
+	if _, err := runGit(tempDir, "tag", "policy"); err != nil {
+		t.Fatalf("%v", err)
+	}
+	write("src/new.go")
+	commit("2022-01-01T12:00:00Z")
+	write("src/untracked.go")
+
+	testCases := []struct {
+		root, cutoff string
+		updated      int
+		failed       bool
+	}{
+		{tempDir, "", 3, false},
+		{tempDir, "policy", 2, false},
+		{tempDir, "2021-06-01", 2, false},
+		{tempDir, "2019-01-01", 3, false},
+		{tempDir, "2023-01-01", 1, false},
+		{filepath.Join(tempDir, "src"), "policy", 2, false},
+		{tempDir, "no-such-ref", 0, true},
+	}
+	for _, tc := range testCases {
+		processor := NewProcessor(tc.root, &Options{DryRun: true, CreatedAfter: tc.cutoff})
+		stats, err := processor.Process()
+		if (err != nil) != tc.failed {
+			t.Errorf("Process(created after %q) = %v, expected failure %v", tc.cutoff, err, tc.failed)
+			continue
+		}
+		if !tc.failed && stats.Updated != tc.updated {
+			t.Errorf("Process(created after %q) updated %d files, expected %d", tc.cutoff, stats.Updated, tc.updated)
+		}
+	}
+}
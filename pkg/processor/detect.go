@@ -0,0 +1,148 @@
+// File: pkg/processor/detect.go
+package processor
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// detectSampleSize is how much of a file AutoDetect reads during the walk
+const detectSampleSize = 1024
+
+// modeLinePatterns find the language an editor mode line names, as in
+// "-*- mode: python -*-" for Emacs and "vim: set ft=sh:" for Vim
+var modeLinePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-\*-\s*(?:.*;\s*)?mode:\s*([\w+-]+)`),
+	regexp.MustCompile(`-\*-\s*([\w+-]+)\s*-\*-`),
+	regexp.MustCompile(`\b(?:vim?|ex):.*\b(?:ft|filetype|syntax)=([\w+-]+)`),
+}
+
+// modeNames maps editor mode names that are neither a language name nor an
+// extension to the extension of their file type
+var modeNames = map[string]string{
+	"c++":          ".cpp",
+	"dosbatch":     ".bat",
+	"emacs-lisp":   ".lisp",
+	"js":           ".js",
+	"nxml":         ".xml",
+	"python3":      ".py",
+	"shell":        ".sh",
+	"shell-script": ".sh",
+	"zsh":          ".zsh",
+}
+
+// detectedFileType resolves the comment style of a file whose name matches
+// no file type from its content, for the AutoDetect option: a shebang, an
+// editor mode line, an XML, HTML or PHP prolog, or the first statement of a
+// few languages. The file's own extensions can still exclude it.
+func (p *Processor) detectedFileType(relPath string, content []byte) (models.CommentStyle, bool) {
+	if !p.config.AutoDetect || isDocFile(relPath) {
+		return models.CommentStyle{}, false
+	}
+	ext := detectExtension(content, p.modeType)
+	if ext == "" || !p.languageSelected(append(fileExtensions(relPath), ext)...) {
+		return models.CommentStyle{}, false
+	}
+	style, ok := p.fileTypes[ext]
+	return style, ok
+}
+
+// isDetectedEntry applies detectedFileType to the start of the file at
+// filePath, found by the walk
+func (p *Processor) isDetectedEntry(filePath, relPath string) bool {
+	if !p.config.AutoDetect {
+		return false
+	}
+	file, err := p.openFile(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	head := make([]byte, detectSampleSize)
+	n, _ := io.ReadFull(file, head)
+	_, ok := p.detectedFileType(relPath, head[:n])
+	return ok
+}
+
+// modeType returns the extension of the file type an editor mode name
+// stands for, or "" if it names none
+func (p *Processor) modeType(name string) string {
+	name = strings.ToLower(name)
+	if ext, ok := modeNames[name]; ok {
+		return ext
+	}
+	for _, ext := range languages[name] {
+		if _, ok := p.fileTypes[ext]; ok {
+			return ext
+		}
+	}
+	if _, ok := p.fileTypes["."+name]; ok {
+		return "." + name
+	}
+	return ""
+}
+
+// detectExtension guesses the extension of a text file from its content, or
+// returns "" for binary content or content it does not recognize. modeType
+// resolves the names of editor mode lines.
+func detectExtension(content []byte, modeType func(string) string) string {
+	content = bytes.TrimPrefix(content, utf8BOM)
+	if bytes.IndexByte(content, 0) >= 0 {
+		return ""
+	}
+
+	// An interpreter or editor mode named in the first lines
+	first, n := firstLine(content)
+	second, _ := firstLine(content[n:])
+	if isShebang(first) {
+		if ext := shebangTypes[shebangInterpreter(first)]; ext != "" {
+			return ext
+		}
+	}
+	for _, line := range []string{first, second} {
+		for _, pattern := range modeLinePatterns {
+			if match := pattern.FindStringSubmatch(line); match != nil {
+				if ext := modeType(match[1]); ext != "" {
+					return ext
+				}
+			}
+		}
+	}
+
+	// The first line that is neither blank nor a // comment, such as the
+	// "Code generated" notice of generated Go files
+	var line string
+	for rest := content; len(rest) > 0 && line == ""; {
+		next, n := firstLine(rest)
+		rest = rest[n:]
+		if line = strings.TrimSpace(next); strings.HasPrefix(line, "//") {
+			line = ""
+		}
+	}
+	lower := strings.ToLower(line)
+	switch {
+	case strings.HasPrefix(line, "<?xml"):
+		return ".xml"
+	case strings.HasPrefix(lower, "<!doctype html") || strings.HasPrefix(lower, "<html"):
+		return ".html"
+	case strings.HasPrefix(line, "<?php"):
+		return ".php"
+	case strings.HasPrefix(line, "%YAML"):
+		return ".yaml"
+	case strings.EqualFold(line, "@echo off"):
+		return ".bat"
+	case strings.HasPrefix(line, "#include <") || strings.HasPrefix(line, "#include \""):
+		return ".c"
+	case strings.HasPrefix(line, "package "):
+		// Java ends the statement with a semicolon, Go does not
+		if strings.HasSuffix(line, ";") {
+			return ".java"
+		}
+		return ".go"
+	}
+	return ""
+}
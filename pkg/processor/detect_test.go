@@ -0,0 +1,100 @@
+// File: pkg/processor/detect_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestDetectExtension(t *testing.T) {
+	p := NewProcessor("", &Options{Config: models.NewConfig()})
+
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"#!/usr/bin/env python3\nprint()\n", ".py"},
+		{"#!/bin/sh\n", ".sh"},
+		{"#!/usr/bin/unknown\n", ""},
+		{"# -*- mode: ruby -*-\nputs 1\n", ".rb"},
+		{"#!/usr/bin/env foo\n# -*- coding: utf-8; mode: python -*-\n", ".py"},
+		{"# vim: set ft=sh:\necho\n", ".sh"},
+		{"; -*- emacs-lisp -*-\n", ".lisp"},
+		{"\xEF\xBB\xBF<?xml version=\"1.0\"?>\n<a/>\n", ".xml"},
+		{"\n<!DOCTYPE html>\n<html></html>\n", ".html"},
+		{"<?php\necho 1;\n", ".php"},
+		{"%YAML 1.2\n---\na: 1\n", ".yaml"},
+		{"@ECHO OFF\r\necho hi\r\n", ".bat"},
+		{"#include <stdio.h>\n", ".c"},
+		{"// Code generated by stringer. DO NOT EDIT.\n\npackage main\n", ".go"},
+		{"package com.example;\n", ".java"},
+		{"Some plain text\n", ""},
+		{"package main\x00\x01", ""},
+	}
+
+	for _, test := range tests {
+		result := detectExtension([]byte(test.content), p.modeType)
+		if result != test.expected {
+			t.Errorf("detectExtension(%q) = %s, expected %s", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestAutoDetect(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "detect-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"config.in":      "#!/bin/sh\necho configure\n",
+		"page.htm":       "<!DOCTYPE html>\n<p>Hi</p>\n",
+		"zz_gen.go.orig": "// Code generated by gen. DO NOT EDIT.\n\npackage gen\n",
+		"notes.txt":      "Some notes\n",
+		"README.md":      "<?xml version=\"1.0\"?>\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Without the option, nothing is detected
+	stats, err := NewProcessor(tempDir, &Options{Config: models.NewConfig()}).Process()
+	if err != nil || stats.Updated != 0 {
+		t.Errorf("Process() without AutoDetect = %+v, %v, expected no updates", stats, err)
+	}
+
+	// Excluding a file's own extension still applies
+	options := &Options{Config: models.NewConfig(), AutoDetect: true, ExcludeLanguages: []string{".orig"}}
+	if _, err := NewProcessor(tempDir, options).Process(); err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	expected := map[string]string{
+		"config.in":      "#!/bin/sh\n# File: config.in\necho configure\n",
+		"page.htm":       "<!-- File: page.htm -->\n<!DOCTYPE html>\n<p>Hi</p>\n",
+		"zz_gen.go.orig": files["zz_gen.go.orig"],
+		"notes.txt":      files["notes.txt"],
+		"README.md":      files["README.md"],
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+
+	// Languages select detected files by the detected language
+	p := NewProcessor(tempDir, &Options{Config: models.NewConfig(), AutoDetect: true, Languages: []string{"go"}, DryRun: true})
+	stats, err = p.Process()
+	if err != nil || stats.Updated != 1 {
+		t.Errorf("Process() with --lang go = %+v, %v, expected only zz_gen.go.orig updated", stats, err)
+	}
+}
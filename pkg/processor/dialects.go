@@ -0,0 +1,313 @@
+// File: pkg/processor/dialects.go
+package processor
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// fixedFormatComment puts the comment indicator in column 7, the position
+// reserved for it by fixed-format COBOL and RPG
+const fixedFormatComment = "      *"
+
+// applyDialect adjusts a column-sensitive comment style to the source format
+// actually used by content. Fixed format is the default; files that declare
+// (or clearly use) free format fall back to the free-format comment syntax.
+func applyDialect(content []byte, style models.CommentStyle) models.CommentStyle {
+	switch style.Dialect {
+	case "cobol":
+		line, _ := firstLine(content)
+		if isCobolFreeDirective(line) {
+			// The directive switches format, so the header goes after it
+			style.LineComment = "*>"
+			style.Placement = "after-first-line"
+		} else if looksFreeFormCobol(content) {
+			style.LineComment = "*>"
+		}
+	case "fsharp":
+		// Keep a leading #light directive (after any shebang) in front
+		content = content[findInsertionPoint(content, models.CommentStyle{}):]
+		line, _ := firstLine(content)
+		if strings.HasPrefix(strings.TrimSpace(line), "#light") {
+			style.Placement = "after-first-line"
+		}
+	case "v":
+		// Verilog and V both use //, but Coq sources share the extension
+		if classifyVFile(content) == "coq" {
+			style.LineComment = ""
+			style.BlockCommentStart = "(*"
+			style.BlockCommentEnd = "*)"
+			style.Preferred = "block"
+		}
+	case "matlab":
+		// MATLAB and Objective-C share the .m extension
+		if classifyMFile(content) == "objc" {
+			style.LineComment = "//"
+			style.BlockCommentStart = "/*"
+			style.BlockCommentEnd = "*/"
+		}
+	case "batch":
+		// The header goes after @echo off, which keeps it from being echoed
+		line, _ := firstLine(bytes.TrimPrefix(content, utf8BOM))
+		if strings.EqualFold(strings.TrimSpace(line), "@echo off") {
+			style.Placement = "after-first-line"
+		}
+		style.LineComment = batchLineComment(content[findInsertionPoint(content, style):])
+	case "php":
+		// A template that does not start in PHP gets the header in a PHP block
+		// of its own; PHP drops the newline right after ?>
+		if phpOpenTagLen(content[findInsertionPoint(content, models.CommentStyle{}):]) == 0 {
+			style.LineComment = ""
+			style.BlockCommentStart = "<?php /*"
+			style.BlockCommentEnd = "*/ ?>"
+			style.Preferred = "block"
+		}
+	case "reg":
+		// regedit requires its signature line to come first
+		line, _ := firstLine(bytes.TrimPrefix(content, utf8BOM))
+		if strings.HasPrefix(line, "Windows Registry Editor") || strings.HasPrefix(line, "REGEDIT4") {
+			style.Placement = "after-first-line"
+		}
+	case "dockerfile":
+		// Parser directives are only read before the first comment
+		line, _ := firstLine(bytes.TrimPrefix(content, utf8BOM))
+		if isDockerfileDirective(line) {
+			style.Placement = "after-first-line"
+		}
+	case "pascal":
+		// Pascal has two kinds of block comment; a header in the other kind
+		// is recognized, and kept in it
+		line, _ := firstLine(content[findInsertionPoint(content, models.CommentStyle{}):])
+		line = strings.TrimSpace(line)
+		for _, markers := range pascalBlockComments {
+			if strings.HasPrefix(line, markers[0]) && !strings.HasPrefix(line, markers[0]+"$") && strings.HasSuffix(line, markers[1]) {
+				style.BlockCommentStart, style.BlockCommentEnd = markers[0], markers[1]
+				break
+			}
+		}
+	case "rpg":
+		// **FREE must stay on line 1, column 1 of fully free-form RPG
+		line, _ := firstLine(content)
+		if strings.EqualFold(strings.TrimRight(line, " \r"), "**FREE") {
+			style.LineComment = "//"
+			style.Placement = "after-first-line"
+		}
+	}
+	return style
+}
+
+// pascalBlockComments are the start and end markers of Pascal's block
+// comments; those starting with $ are compiler directives
+var pascalBlockComments = [][2]string{{"{", "}"}, {"(*", "*)"}}
+
+// dialectSeparator returns extra text to put between the header and the rest
+// of the file when the two would otherwise be read as a single construct
+func dialectSeparator(rest []byte, style models.CommentStyle) string {
+	switch style.Dialect {
+	case "powershell":
+		// A line header directly above "# .SYNOPSIS"-style help would become part
+		// of the help block and break Get-Help, so keep them apart
+		line, _ := firstLine(rest)
+		if style.Preferred == "line" && isPowerShellLineHelp(line) {
+			return "\n"
+		}
+	}
+	return ""
+}
+
+// isPowerShellLineHelp checks for a line comment opening comment-based help
+func isPowerShellLineHelp(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") || strings.HasPrefix(line, "#Requires") {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(line[1:]), ".")
+}
+
+// isDockerfileDirective checks for a "# syntax=", "# escape=" or "# check="
+// parser directive
+func isDockerfileDirective(line string) bool {
+	text, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+	if !ok {
+		return false
+	}
+	name, _, ok := strings.Cut(text, "=")
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "syntax", "escape", "check":
+		return true
+	}
+	return false
+}
+
+// classifyVFile guesses which language a .v file is written in:
+// "verilog", "vlang" or "coq"
+func classifyVFile(content []byte) string {
+	text := string(content)
+	switch {
+	case strings.Contains(text, "endmodule") || strings.Contains(text, "`timescale"):
+		return "verilog"
+	case strings.Contains(text, "Qed.") || strings.Contains(text, "Require Import") ||
+		strings.Contains(text, "Theorem ") || strings.Contains(text, "Lemma "):
+		return "coq"
+	case strings.Contains(text, "fn ") || strings.Contains(text, "module main"):
+		return "vlang"
+	default:
+		return "verilog"
+	}
+}
+
+// classifyMFile guesses which language a .m file is written in: "objc" if
+// a line starts with a preprocessor include or an Objective-C declaration,
+// and "matlab" otherwise
+func classifyMFile(content []byte) string {
+	for len(content) > 0 {
+		line, n := firstLine(content)
+		content = content[n:]
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"#import", "#include", "@interface", "@implementation", "@protocol"} {
+			if strings.HasPrefix(line, marker) {
+				return "objc"
+			}
+		}
+	}
+	return "matlab"
+}
+
+// batchLineComment returns the comment marker for a header at the start of
+// content in a batch file: that of the comment there, or else :: if more
+// lines use it than REM
+func batchLineComment(content []byte) string {
+	var labels, rems int
+	for first := true; len(content) > 0; first = false {
+		line, n := firstLine(content)
+		content = content[n:]
+		switch kind := batchCommentKind(line); {
+		case first && kind != "":
+			return kind
+		case kind == "::":
+			labels++
+		case kind == "REM":
+			rems++
+		}
+	}
+	if labels > rems {
+		return "::"
+	}
+	return "REM"
+}
+
+// batchCommentKind returns "REM" or "::" for a batch file comment line, or
+// "" for other lines
+func batchCommentKind(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "::") {
+		return "::"
+	}
+	word, _, _ := strings.Cut(strings.TrimPrefix(line, "@"), " ")
+	if strings.EqualFold(word, "REM") {
+		return "REM"
+	}
+	return ""
+}
+
+// goConstraintsLen returns the length of the //go:build and // +build lines
+// a Go file starts with, through the blank line that must follow them, or 0
+// if it starts with none
+func goConstraintsLen(content []byte) int {
+	offset, end := 0, 0
+	for offset < len(content) {
+		line, n := firstLine(content[offset:])
+		text := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(text, "//go:build") || strings.HasPrefix(text, "// +build"):
+			end = offset + n
+		case text == "" && end > 0:
+			return offset + n
+		case text != "":
+			return end
+		}
+		offset += n
+	}
+	return end
+}
+
+// phpOpenTagLen returns the length of the line of the <?php tag a PHP file
+// starts with, and of a line comment above it, or 0 if the file does not
+// start in PHP. A tag closed on the same line, as in a one-line block, opens
+// no PHP code for the header to go in.
+func phpOpenTagLen(content []byte) int {
+	isOpenTag := func(line string) bool {
+		line = strings.ToLower(strings.TrimSpace(line))
+		return strings.HasPrefix(line, "<?php") && !strings.Contains(line, "?>")
+	}
+	first, n := firstLine(content)
+	if isOpenTag(first) {
+		return n
+	}
+	// A header above the tag, from before it was placed below it
+	if text := strings.TrimSpace(first); strings.HasPrefix(text, "//") || strings.HasPrefix(text, "#") {
+		if second, m := firstLine(content[n:]); isOpenTag(second) {
+			return n + m
+		}
+	}
+	return 0
+}
+
+// vbaAttributesLen returns the length of the lines an exported VBA file
+// starts with: the VERSION line and Begin/End block of classes and forms,
+// Object references and the Attribute lines
+func vbaAttributesLen(content []byte) int {
+	offset, depth := 0, 0
+	for offset < len(content) {
+		line, n := firstLine(content[offset:])
+		word, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch {
+		case strings.EqualFold(word, "Begin"):
+			depth++
+		case strings.EqualFold(word, "End") && depth > 0:
+			depth--
+		case depth == 0 && word != "VERSION" && word != "Object" && word != "Attribute":
+			return offset
+		}
+		offset += n
+	}
+	return offset
+}
+
+// isCobolFreeDirective checks for a >>SOURCE FORMAT FREE compiler directive
+func isCobolFreeDirective(line string) bool {
+	fields := strings.Fields(strings.ToUpper(line))
+	if len(fields) == 0 || fields[0] != ">>SOURCE" {
+		return false
+	}
+	return fields[len(fields)-1] == "FREE"
+}
+
+// looksFreeFormCobol reports whether the first significant line starts in
+// the sequence area (columns 1-6) with something other than a sequence number
+func looksFreeFormCobol(content []byte) bool {
+	for len(content) > 0 {
+		line, n := firstLine(content)
+		content = content[n:]
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		area := line
+		if len(area) > 6 {
+			area = area[:6]
+		}
+		area = strings.TrimSpace(area)
+		if area == "" {
+			return false
+		}
+		return strings.IndexFunc(area, func(r rune) bool { return r < '0' || r > '9' }) >= 0
+	}
+	return false
+}
@@ -0,0 +1,432 @@
+// File: pkg/processor/dialects_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestFixedFormatHeaders(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "dialects-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"fixed.cbl",
+			"000100 IDENTIFICATION DIVISION.\n",
+			"      * File: fixed.cbl\n000100 IDENTIFICATION DIVISION.\n",
+		},
+		{
+			"free.cob",
+			"IDENTIFICATION DIVISION.\n",
+			"*> File: free.cob\nIDENTIFICATION DIVISION.\n",
+		},
+		{
+			"directive.cbl",
+			"       >>SOURCE FORMAT IS FREE\nIDENTIFICATION DIVISION.\n",
+			"       >>SOURCE FORMAT IS FREE\n*> File: directive.cbl\nIDENTIFICATION DIVISION.\n",
+		},
+		{
+			"fixed.rpgle",
+			"     H DFTACTGRP(*NO)\n",
+			"      * File: fixed.rpgle\n     H DFTACTGRP(*NO)\n",
+		},
+		{
+			"free.rpgle",
+			"**FREE\nctl-opt dftactgrp(*no);\n",
+			"**FREE\n// File: free.rpgle\nctl-opt dftactgrp(*no);\n",
+		},
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	for _, test := range tests {
+		path := filepath.Join(tempDir, test.name)
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+
+		// Run twice to make sure the header is recognized on the second pass
+		for i := 0; i < 2; i++ {
+			if _, err := processor.processFile(path, test.name); err != nil {
+				t.Fatalf("processFile(%s) failed: %v", test.name, err)
+			}
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", test.name, string(content), test.expected)
+		}
+	}
+}
+
+func TestPowerShellHeaders(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "powershell-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"requires.ps1",
+			"#Requires -Version 7.0\nparam()\n",
+			"# File: requires.ps1\n#Requires -Version 7.0\nparam()\n",
+		},
+		{
+			"linehelp.ps1",
+			"# .SYNOPSIS\n# Deploys the app\nparam()\n",
+			"# File: linehelp.ps1\n\n# .SYNOPSIS\n# Deploys the app\nparam()\n",
+		},
+		{
+			"blockhelp.psm1",
+			"<#\n.SYNOPSIS\nTools\n#>\n",
+			"# File: blockhelp.psm1\n<#\n.SYNOPSIS\nTools\n#>\n",
+		},
+		{
+			"manifest.psd1",
+			"@{\n    ModuleVersion = '1.0'\n}\n",
+			"# File: manifest.psd1\n@{\n    ModuleVersion = '1.0'\n}\n",
+		},
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	for _, test := range tests {
+		path := filepath.Join(tempDir, test.name)
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+
+		// Run twice to make sure the separator is not added again
+		for i := 0; i < 2; i++ {
+			if _, err := processor.processFile(path, test.name); err != nil {
+				t.Fatalf("processFile(%s) failed: %v", test.name, err)
+			}
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", test.name, string(content), test.expected)
+		}
+	}
+}
+
+func TestFSharpDirectives(t *testing.T) {
+	style := models.CommentStyle{LineComment: "//", Preferred: "line", Dialect: "fsharp"}
+
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"module App\n", 0},
+		{"#light\nmodule App\n", 7},
+		{"#!/usr/bin/env -S dotnet fsi\nprintfn \"hi\"\n", 29},
+		{"#!/usr/bin/env -S dotnet fsi\n#light\nprintfn \"hi\"\n", 36},
+	}
+
+	for _, test := range tests {
+		content := []byte(test.content)
+		result := findInsertionPoint(content, applyDialect(content, style))
+		if result != test.expected {
+			t.Errorf("insertion point for %q = %d, expected %d", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestVFileClassification(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"`timescale 1ns / 1ps\nmodule counter(input clk);\nendmodule\n", "verilog"},
+		{"module main\n\nfn main() {\n\tprintln('hi')\n}\n", "vlang"},
+		{"Require Import Arith.\nTheorem t : 1 = 1.\nProof. reflexivity. Qed.\n", "coq"},
+	}
+
+	for _, test := range tests {
+		result := classifyVFile([]byte(test.content))
+		if result != test.expected {
+			t.Errorf("classifyVFile(%q) = %s, expected %s", test.content, result, test.expected)
+		}
+	}
+
+	style := applyDialect([]byte(tests[2].content), models.CommentStyle{LineComment: "//", Preferred: "line", Dialect: "v"})
+	if style.Preferred != "block" || style.BlockCommentStart != "(*" {
+		t.Errorf("Expected Coq block comments for .v proof file, got: %+v", style)
+	}
+}
+
+func TestMFileClassification(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"function y = square(x)\n  y = x.^2;\nend\n", "matlab"},
+		{"#import <Foundation/Foundation.h>\n\n@implementation View\n@end\n", "objc"},
+		{"% script\ndisp('#include')\n", "matlab"},
+	}
+
+	for _, test := range tests {
+		result := classifyMFile([]byte(test.content))
+		if result != test.expected {
+			t.Errorf("classifyMFile(%q) = %s, expected %s", test.content, result, test.expected)
+		}
+	}
+
+	style := applyDialect([]byte(tests[1].content), models.CommentStyle{LineComment: "%", Preferred: "line", Dialect: "matlab"})
+	if style.LineComment != "//" || style.BlockCommentStart != "/*" {
+		t.Errorf("Expected C comments for Objective-C .m file, got: %+v", style)
+	}
+}
+
+func TestRegistryFileHeaders(t *testing.T) {
+	style := models.CommentStyle{LineComment: ";", Preferred: "line", Dialect: "reg"}
+
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"Windows Registry Editor Version 5.00\r\n\r\n[HKEY_CURRENT_USER\\Software]\r\n", 38},
+		{"\xEF\xBB\xBFWindows Registry Editor Version 5.00\n", 40},
+		{"REGEDIT4\n", 9},
+		{"[HKEY_CURRENT_USER\\Software]\n", 0},
+	}
+
+	for _, test := range tests {
+		content := []byte(test.content)
+		result := findInsertionPoint(content, applyDialect(content, style))
+		if result != test.expected {
+			t.Errorf("insertion point for %q = %d, expected %d", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestBatchFileHeaders(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dialects-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"plain.bat",
+			"echo hi\r\n",
+			"REM File: plain.bat\necho hi\r\n",
+		},
+		{
+			"quiet.cmd",
+			"@ECHO OFF\r\nREM Build\r\nmsbuild\r\n",
+			"@ECHO OFF\r\nREM File: quiet.cmd\nREM Build\r\nmsbuild\r\n",
+		},
+		{
+			"labels.bat",
+			"@echo off\n:: Build\n:: Then test\nmsbuild\n",
+			"@echo off\n:: File: labels.bat\n:: Build\n:: Then test\nmsbuild\n",
+		},
+		{
+			"moved.bat",
+			"@echo off\n:: File: old.bat\nREM Build\nREM Then test\nmsbuild\n",
+			"@echo off\n:: File: moved.bat\nREM Build\nREM Then test\nmsbuild\n",
+		},
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	for _, test := range tests {
+		path := filepath.Join(tempDir, test.name)
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+
+		// Run twice to make sure the header is recognized on the second pass
+		for i := 0; i < 2; i++ {
+			if _, err := processor.Process(); err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", test.name, string(content), test.expected)
+		}
+	}
+}
+
+func TestVBAAttributes(t *testing.T) {
+	style := models.CommentStyle{LineComment: "'", Preferred: "line", Dialect: "vba"}
+
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"Attribute VB_Name = \"Module1\"\r\nOption Explicit\r\n", 31},
+		{"VERSION 1.0 CLASS\nBEGIN\n  MultiUse = -1  'True\nEND\nAttribute VB_Name = \"Shape\"\nOption Explicit\n", 79},
+		{"VERSION 5.00\nBegin VB.Form Main\n   Begin VB.CommandButton OK\n   End\nEnd\nAttribute VB_Name = \"Main\"\nPrivate Sub OK_Click()\nEnd Sub\n", 99},
+		{"Option Explicit\n", 0},
+	}
+
+	for _, test := range tests {
+		content := []byte(test.content)
+		result := findInsertionPoint(content, applyDialect(content, style))
+		if result != test.expected {
+			t.Errorf("insertion point for %q = %d, expected %d", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestPHPOpenTag(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dialects-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"plain.php",
+			"<?php\necho 1;\n",
+			"<?php\n// File: plain.php\necho 1;\n",
+		},
+		{
+			"strict.php",
+			"<?PHP declare(strict_types=1);\n\nnamespace App;\n",
+			"<?PHP declare(strict_types=1);\n// File: strict.php\n\nnamespace App;\n",
+		},
+		{
+			"cli.php",
+			"#!/usr/bin/env php\n<?php\necho 1;\n",
+			"#!/usr/bin/env php\n<?php\n// File: cli.php\necho 1;\n",
+		},
+		{
+			"page.php",
+			"<html>\n<?= $title ?>\n</html>\n",
+			"<?php /* File: page.php */ ?>\n<html>\n<?= $title ?>\n</html>\n",
+		},
+		{
+			"old.php",
+			"// File: old.php\n<?php\necho 1;\n",
+			"<?php\n// File: old.php\necho 1;\n",
+		},
+		{
+			"renamed.php",
+			"// File: legacy.php\n<?php\necho 1;\n",
+			"<?php\n// File: renamed.php\necho 1;\n",
+		},
+	}
+
+	for _, test := range tests {
+		if err := os.WriteFile(filepath.Join(tempDir, test.name), []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+	}
+
+	// The headers above <?php are moved, and the one naming another path
+	// is reported as a rename
+	p := NewProcessor(tempDir, &Options{})
+	stats, err := p.Process()
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.Missing != 4 || stats.Stale != 2 {
+		t.Errorf("Expected 4 missing and 2 stale headers, got: %d missing, %d stale", stats.Missing, stats.Stale)
+	}
+	for _, result := range p.Results() {
+		if result.Path == "renamed.php" && result.OldPath != "legacy.php" {
+			t.Errorf("renamed.php was moved from %q, expected legacy.php", result.OldPath)
+		}
+	}
+
+	// The headers are recognized on the second pass
+	if stats, err := NewProcessor(tempDir, &Options{}).Process(); err != nil || stats.Updated != 0 {
+		t.Errorf("second run = %+v, %v, expected no updates", stats, err)
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(filepath.Join(tempDir, test.name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", test.name, string(content), test.expected)
+		}
+	}
+}
+
+func TestPascalBlockComments(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dialects-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	braces := models.CommentStyle{BlockCommentStart: "{", BlockCommentEnd: "}", Preferred: "block"}
+	parens := models.CommentStyle{BlockCommentStart: "(*", BlockCommentEnd: "*)", Preferred: "block"}
+	tests := []struct {
+		name     string
+		style    models.CommentStyle
+		content  string
+		expected string
+	}{
+		{"new.pas", braces, "program New;\n", "{ File: new.pas }\nprogram New;\n"},
+		{"parens.pas", braces, "(* File: parens.pas *)\nprogram Parens;\n", "(* File: parens.pas *)\nprogram Parens;\n"},
+		{"moved.pp", braces, "(* File: old.pp *)\nunit Moved;\n", "(* File: moved.pp *)\nunit Moved;\n"},
+		{"directive.pas", braces, "{$mode objfpc}\nunit Directive;\n", "{ File: directive.pas }\n{$mode objfpc}\nunit Directive;\n"},
+		{"written.dpr", parens, "program Written;\n", "(* File: written.dpr *)\nprogram Written;\n"},
+		{"braces.dpr", parens, "{ File: braces.dpr }\nprogram Braces;\n", "{ File: braces.dpr }\nprogram Braces;\n"},
+		{"skip.pas", parens, "(*$R *.res*)\nprogram Skip;\n", "(* File: skip.pas *)\n(*$R *.res*)\nprogram Skip;\n"},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(tempDir, test.name)
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+
+		// Run twice to make sure the header is recognized on the second pass
+		config := models.NewConfig().WithFileType(filepath.Ext(test.name), test.style)
+		for i := 0; i < 2; i++ {
+			if _, err := NewProcessor(tempDir, &Options{Config: config, Files: []string{path}}).Process(); err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", test.name, string(content), test.expected)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// File: pkg/processor/diff.go
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// diffContext is the number of unchanged lines shown around a change
+const diffContext = 3
+
+// UnifiedDiff returns a unified diff between the old and new content of a
+// file, or "" if they are equal. Header edits are localized, so the diff is
+// a single hunk spanning everything between the common leading and trailing
+// lines.
+func UnifiedDiff(name string, oldContent, newContent []byte) string {
+	if bytes.Equal(oldContent, newContent) {
+		return ""
+	}
+	a := splitLines(oldContent)
+	b := splitLines(newContent)
+	prefix, suffix := commonLines(a, b)
+
+	start := prefix - diffContext
+	if start < 0 {
+		start = 0
+	}
+	trailing := suffix
+	if trailing > diffContext {
+		trailing = diffContext
+	}
+	oldEnd := len(a) - suffix + trailing
+	newEnd := len(b) - suffix + trailing
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", name, name)
+	fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(start, oldEnd-start), hunkRange(start, newEnd-start))
+	for _, line := range a[start:prefix] {
+		writeDiffLine(&sb, ' ', line)
+	}
+	for _, line := range a[prefix : len(a)-suffix] {
+		writeDiffLine(&sb, '-', line)
+	}
+	for _, line := range b[prefix : len(b)-suffix] {
+		writeDiffLine(&sb, '+', line)
+	}
+	for _, line := range a[len(a)-suffix : oldEnd] {
+		writeDiffLine(&sb, ' ', line)
+	}
+	return sb.String()
+}
+
+// changeDiff prints the diff of a change when PrintDiffs is set, and
+// returns it for the file's result when Diffs is set, or else ""
+func (p *Processor) changeDiff(name string, oldContent, newContent []byte) string {
+	if !p.options.Diffs && !p.options.PrintDiffs {
+		return ""
+	}
+	diff := UnifiedDiff(name, oldContent, newContent)
+	if p.options.PrintDiffs {
+		p.printf("%s", diff)
+	}
+	if !p.options.Diffs {
+		return ""
+	}
+	return diff
+}
+
+// countDiffSize adds the size of the change from oldContent to newContent
+// to the line and byte counts of stats
+func countDiffSize(stats *models.Stats, oldContent, newContent []byte) {
+	a := splitLines(oldContent)
+	b := splitLines(newContent)
+	prefix, suffix := commonLines(a, b)
+	removed := a[prefix : len(a)-suffix]
+	added := b[prefix : len(b)-suffix]
+
+	rewritten := len(removed)
+	if len(added) < rewritten {
+		rewritten = len(added)
+	}
+	stats.LinesRewritten += rewritten
+	stats.LinesAdded += len(added) - rewritten
+	stats.LinesRemoved += len(removed) - rewritten
+	for _, line := range added {
+		stats.BytesAdded += len(line)
+	}
+	for _, line := range removed {
+		stats.BytesRemoved += len(line)
+	}
+}
+
+// commonLines returns the number of leading and trailing lines a and b have
+// in common, without overlap
+func commonLines(a, b []string) (int, int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+// splitLines splits content after each newline, keeping the newlines
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// hunkRange formats the start and length of a hunk side
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// writeDiffLine writes a diff line, marking a missing final newline
+func writeDiffLine(sb *strings.Builder, marker byte, line string) {
+	sb.WriteByte(marker)
+	sb.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteString("\n\\ No newline at end of file\n")
+	}
+}
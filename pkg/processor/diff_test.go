@@ -0,0 +1,110 @@
+// File: pkg/processor/diff_test.go
+package processor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{"equal", "a\n", "a\n", ""},
+		{"header added", "package main\n\nfunc main() {}\n", "// File: main.go\npackage main\n\nfunc main() {}\n",
+			"--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,4 @@\n+// File: main.go\n package main\n \n func main() {}\n"},
+		{"header replaced", "// File: old.go\n1\n2\n3\n4\n5\n", "// File: main.go\n1\n2\n3\n4\n5\n",
+			"--- a/main.go\n+++ b/main.go\n@@ -1,4 +1,4 @@\n-// File: old.go\n+// File: main.go\n 1\n 2\n 3\n"},
+		{"after shebang", "#!/bin/sh\necho\n", "#!/bin/sh\n# File: main.go\necho\n",
+			"--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,3 @@\n #!/bin/sh\n+# File: main.go\n echo\n"},
+		{"empty file", "", "// File: main.go\n",
+			"--- a/main.go\n+++ b/main.go\n@@ -0,0 +1 @@\n+// File: main.go\n"},
+		{"no final newline", "x", "// File: main.go\nx",
+			"--- a/main.go\n+++ b/main.go\n@@ -1 +1,2 @@\n+// File: main.go\n x\n\\ No newline at end of file\n"},
+	}
+
+	for _, test := range tests {
+		if result := UnifiedDiff("main.go", []byte(test.old), []byte(test.new)); result != test.expected {
+			t.Errorf("UnifiedDiff(%s) = %q, expected %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestRecordDiffs(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "diffs-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"new.go": "package a\n",
+		"ok.go":  "// File: ok.go\npackage a\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	for _, diffs := range []bool{false, true} {
+		p := NewProcessor(tempDir, &Options{DryRun: true, Diffs: diffs})
+		if _, err := p.Process(); err != nil {
+			t.Fatalf("Processor.Process failed: %v", err)
+		}
+		for _, result := range p.Results() {
+			expected := ""
+			if diffs && result.Path == "new.go" {
+				expected = "--- a/new.go\n+++ b/new.go\n@@ -1 +1,2 @@\n+// File: new.go\n package a\n"
+			}
+			if result.Diff != expected {
+				t.Errorf("Diff of %s with Diffs=%v = %q, expected %q", result.Path, diffs, result.Diff, expected)
+			}
+		}
+	}
+
+	// Printed diffs preview the changes without being recorded
+	var stdout bytes.Buffer
+	p := NewProcessor(tempDir, &Options{DryRun: true, PrintDiffs: true, Stdout: &stdout})
+	if _, err := p.Process(); err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	expected := "--- a/new.go\n+++ b/new.go\n@@ -1 +1,2 @@\n+// File: new.go\n package a\n"
+	if stdout.String() != expected {
+		t.Errorf("Output with PrintDiffs = %q, expected %q", stdout.String(), expected)
+	}
+	for _, result := range p.Results() {
+		if result.Diff != "" {
+			t.Errorf("Diff of %s with PrintDiffs = %q, expected none", result.Path, result.Diff)
+		}
+	}
+}
+
+func TestCountDiffSize(t *testing.T) {
+	tests := []struct {
+		old      string
+		new      string
+		expected models.Stats
+	}{
+		{"package a\n", "// File: a.go\npackage a\n", models.Stats{LinesAdded: 1, BytesAdded: 14}},
+		{"// File: b.go\npackage a\n", "// File: a.go\npackage a\n", models.Stats{LinesRewritten: 1, BytesAdded: 14, BytesRemoved: 14}},
+		{"/* File: b.c\n */\nint x;\n", "// File: a.c\nint x;\n", models.Stats{LinesRewritten: 1, LinesRemoved: 1, BytesAdded: 13, BytesRemoved: 17}},
+		{"x\n", "x\n", models.Stats{}},
+	}
+
+	for _, test := range tests {
+		var stats models.Stats
+		countDiffSize(&stats, []byte(test.old), []byte(test.new))
+		if stats != test.expected {
+			t.Errorf("countDiffSize(%q, %q) = %+v, expected %+v", test.old, test.new, stats, test.expected)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// File: pkg/processor/dirstats.go
+package processor
+
+import (
+	"path"
+	"sort"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// DirectoryStats breaks the statistics of the latest run down by directory,
+// truncated to depth levels below the root as in Coverage, or the full
+// directory of each file when depth is negative. Directories are ordered by
+// the files that need a header (missing or stale) and then by errors, most
+// first, so the start of the list shows where cleanup matters most. Only the
+// first top are returned when top is positive.
+func (p *Processor) DirectoryStats(depth, top int) []models.DirectoryStats {
+	byDir := make(map[string]*models.Stats)
+	for _, result := range p.results {
+		dir := coverageDir(result.Path, depth)
+		if depth < 0 {
+			dir = path.Dir(result.Path)
+		}
+		stats, ok := byDir[dir]
+		if !ok {
+			stats = &models.Stats{}
+			byDir[dir] = stats
+		}
+		countResult(stats, result)
+	}
+
+	dirs := make([]models.DirectoryStats, 0, len(byDir))
+	for dir, stats := range byDir {
+		dirs = append(dirs, models.DirectoryStats{Directory: dir, Stats: *stats})
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		a, b := dirs[i].Stats, dirs[j].Stats
+		if a.Missing+a.Stale != b.Missing+b.Stale {
+			return a.Missing+a.Stale > b.Missing+b.Stale
+		}
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		return dirs[i].Directory < dirs[j].Directory
+	})
+	if top > 0 && len(dirs) > top {
+		dirs = dirs[:top]
+	}
+	return dirs
+}
+
+// countResult adds a file's outcome to stats as the walk counts it
+func countResult(stats *models.Stats, result models.FileResult) {
+	if result.Warning != "" {
+		stats.Warnings++
+	}
+	switch result.Action {
+	case models.ActionUpdated:
+		stats.Processed++
+		stats.Updated++
+		countChange(stats, result.Reason)
+	case models.ActionUnchanged:
+		stats.Processed++
+		stats.Skipped++
+	case models.ActionError:
+		stats.Processed++
+		stats.Errors++
+	case models.ActionListed:
+		stats.Processed++
+	default:
+		stats.Skipped++
+	}
+}
@@ -0,0 +1,71 @@
+// File: pkg/processor/dirstats_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestDirectoryStats(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-dirstats-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"main.go":           "// File: main.go\npackage main\n",
+		"pkg/a/a.go":        "package a\n",
+		"pkg/a/b.go":        "// File: pkg/a/old.go\npackage a\n",
+		"pkg/c/c.go":        "// File: c.go\npackage c\n",
+		"scripts/build.py":  "print('hi')\n",
+		"scripts/notes.txt": "all:\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	processor := NewProcessor(tempDir, &Options{DryRun: true})
+	stats, err := processor.Process()
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.Updated != 4 || stats.Missing != 2 || stats.Stale != 2 {
+		t.Errorf("Process() = %+v, expected 4 updated files, 2 missing and 2 stale", stats)
+	}
+
+	testCases := []struct {
+		depth, top int
+		expected   []string
+	}{
+		{-1, 0, []string{"pkg/a", "pkg/c", "scripts", "."}},
+		{1, 0, []string{"pkg", "scripts", "."}},
+		{1, 1, []string{"pkg"}},
+		{0, 0, []string{"."}},
+	}
+	for _, tc := range testCases {
+		dirs := processor.DirectoryStats(tc.depth, tc.top)
+		var names []string
+		for _, dir := range dirs {
+			names = append(names, dir.Directory)
+		}
+		if !reflect.DeepEqual(names, tc.expected) {
+			t.Errorf("DirectoryStats(%d, %d) = %v, expected %v", tc.depth, tc.top, names, tc.expected)
+		}
+	}
+
+	expected := models.Stats{Processed: 3, Updated: 3, Missing: 1, Stale: 2}
+	if got := processor.DirectoryStats(1, 1)[0].Stats; got != expected {
+		t.Errorf("DirectoryStats(1, 1)[pkg] = %+v, expected %+v", got, expected)
+	}
+}
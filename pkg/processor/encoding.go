@@ -0,0 +1,118 @@
+// File: pkg/processor/encoding.go
+package processor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// byteOrder returns the byte order of a wide encoding named in wideBOMs,
+// and the size of its code units
+func byteOrder(encoding string) (binary.ByteOrder, int) {
+	switch encoding {
+	case "UTF-16LE":
+		return binary.LittleEndian, 2
+	case "UTF-16BE":
+		return binary.BigEndian, 2
+	case "UTF-32LE":
+		return binary.LittleEndian, 4
+	case "UTF-32BE":
+		return binary.BigEndian, 4
+	}
+	return nil, 0
+}
+
+// decodeText converts text in a wide encoding to UTF-8 for processing. A
+// byte order mark becomes a UTF-8 one, so that the header goes after it and
+// encodeText restores it. Content that would not survive the round trip,
+// such as UTF-16 with an unpaired surrogate or a truncated last character,
+// is rejected.
+func decodeText(content []byte, encoding string) ([]byte, error) {
+	order, size := byteOrder(encoding)
+	if order == nil {
+		return nil, fmt.Errorf("unknown encoding %s", encoding)
+	}
+	if len(content)%size != 0 {
+		return nil, fmt.Errorf("invalid %s: truncated character", encoding)
+	}
+
+	var runes []rune
+	if size == 2 {
+		units := make([]uint16, len(content)/2)
+		for i := range units {
+			units[i] = order.Uint16(content[2*i:])
+		}
+		runes = utf16.Decode(units)
+	} else {
+		runes = make([]rune, len(content)/4)
+		for i := range runes {
+			runes[i] = rune(order.Uint32(content[4*i:]))
+		}
+	}
+
+	decoded := []byte(string(runes))
+	if !bytes.Equal(encodeText(decoded, encoding), content) {
+		return nil, fmt.Errorf("invalid %s", encoding)
+	}
+	return decoded, nil
+}
+
+// encodeText converts UTF-8 text back to the wide encoding it was decoded
+// from
+func encodeText(content []byte, encoding string) []byte {
+	order, size := byteOrder(encoding)
+	runes := bytes.Runes(content)
+	if size == 2 {
+		units := utf16.Encode(runes)
+		encoded := make([]byte, 2*len(units))
+		for i, unit := range units {
+			order.PutUint16(encoded[2*i:], unit)
+		}
+		return encoded
+	}
+	encoded := make([]byte, 4*len(runes))
+	for i, r := range runes {
+		order.PutUint32(encoded[4*i:], uint32(r))
+	}
+	return encoded
+}
+
+// detectUTF16 recognizes UTF-16 text without a byte order mark, as some
+// Windows tools write it, returning its encoding. The sample must decode
+// without NULs or control characters other than whitespace, and most of it
+// must be ASCII, whose high bytes are the NULs that set UTF-16 apart.
+func detectUTF16(sample []byte) (string, bool) {
+	sample = sample[:len(sample)&^1]
+	if len(sample) < 4 {
+		return "", false
+	}
+	for _, encoding := range []string{"UTF-16LE", "UTF-16BE"} {
+		order, _ := byteOrder(encoding)
+		units := make([]uint16, len(sample)/2)
+		for i := range units {
+			units[i] = order.Uint16(sample[2*i:])
+		}
+		// The sample may end inside a surrogate pair
+		if last := units[len(units)-1]; utf16.IsSurrogate(rune(last)) && last < 0xDC00 {
+			units = units[:len(units)-1]
+		}
+
+		ascii, valid := 0, true
+		for _, r := range utf16.Decode(units) {
+			if r == utf8.RuneError || r < 0x80 && isControlByte(byte(r)) {
+				valid = false
+				break
+			}
+			if r < 0x80 {
+				ascii++
+			}
+		}
+		if valid && 2*ascii > len(units) {
+			return encoding, true
+		}
+	}
+	return "", false
+}
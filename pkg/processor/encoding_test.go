@@ -0,0 +1,101 @@
+// File: pkg/processor/encoding_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+// utf16LE encodes s as UTF-16LE
+func utf16LE(s string) []byte {
+	var encoded []byte
+	for _, unit := range utf16.Encode([]rune(s)) {
+		encoded = append(encoded, byte(unit), byte(unit>>8))
+	}
+	return encoded
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		encoding string
+		expected string // "" if decoding must fail
+	}{
+		{"utf-16le", utf16LE("\uFEFFnamespace App;\n"), "UTF-16LE", "\uFEFFnamespace App;\n"},
+		{"utf-16le without bom", utf16LE("x = 1\r\n"), "UTF-16LE", "x = 1\r\n"},
+		{"utf-16be", []byte{0xFE, 0xFF, 0x00, 'h', 0x00, 'i'}, "UTF-16BE", "\uFEFFhi"},
+		{"surrogate pair", utf16LE("// 😀\n"), "UTF-16LE", "// 😀\n"},
+		{"utf-32le", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0x00, 0x00, 0x00}, "UTF-32LE", "\uFEFFh"},
+		{"utf-32be", []byte{0x00, 0x00, 0xFE, 0xFF, 0x00, 0x00, 0x00, 'h'}, "UTF-32BE", "\uFEFFh"},
+		{"unpaired surrogate", []byte{'a', 0x00, 0x00, 0xD8, 'b', 0x00}, "UTF-16LE", ""},
+		{"truncated", []byte{0xFF, 0xFE, 'a', 0x00, 'b'}, "UTF-16LE", ""},
+		{"out of range", []byte{0x00, 0x00, 0x11, 0x00}, "UTF-32LE", ""},
+	}
+
+	for _, test := range tests {
+		decoded, err := decodeText(test.content, test.encoding)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("decodeText(%s) = %q, expected an error", test.name, decoded)
+			}
+			continue
+		}
+		if err != nil || string(decoded) != test.expected {
+			t.Errorf("decodeText(%s) = %q, %v, expected %q", test.name, decoded, err, test.expected)
+			continue
+		}
+		if encoded := encodeText(decoded, test.encoding); string(encoded) != string(test.content) {
+			t.Errorf("encodeText(%s) = %v, expected %v", test.name, encoded, test.content)
+		}
+	}
+}
+
+func TestWideTextFiles(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-encoding-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Visual Studio writes UTF-16LE with a byte order mark
+	tests := []struct {
+		name     string
+		content  []byte
+		expected []byte
+	}{
+		{"Program.cs", utf16LE("\uFEFFclass Program {}\r\n"), utf16LE("\uFEFF// File: Program.cs\nclass Program {}\r\n")},
+		{"setup.py", utf16LE("import os\n"), utf16LE("# File: setup.py\nimport os\n")},
+		{"big.js", []byte{0xFE, 0xFF, 0x00, 'x', 0x00, '\n'}, encodeText([]byte("\uFEFF// File: big.js\nx\n"), "UTF-16BE")},
+	}
+	for _, test := range tests {
+		if err := os.WriteFile(filepath.Join(tempDir, test.name), test.content, 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", test.name, err)
+		}
+	}
+
+	stats, err := NewProcessor(tempDir, &Options{}).Process()
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.Updated != len(tests) || stats.Warnings != 0 {
+		t.Errorf("Process() = %+v, expected %d updates without warnings", stats, len(tests))
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(filepath.Join(tempDir, test.name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != string(test.expected) {
+			t.Errorf("%s = %q, expected %q", test.name, content, test.expected)
+		}
+	}
+
+	// Headers already in place are recognized
+	stats, err = NewProcessor(tempDir, &Options{}).Process()
+	if err != nil || stats.Updated != 0 {
+		t.Errorf("second Process() = %+v, %v, expected no updates", stats, err)
+	}
+}
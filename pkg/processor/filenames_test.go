@@ -0,0 +1,110 @@
+// File: pkg/processor/filenames_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestFileNameType(t *testing.T) {
+	p := NewProcessor("", &Options{Config: models.NewConfig()})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"Dockerfile", "Dockerfile"},
+		{"build/Dockerfile.dev", "Dockerfile.*"},
+		{"api.Dockerfile", "*.Dockerfile"},
+		{"src/CMakeLists.txt", "CMakeLists.txt"},
+		{"GNUmakefile", "GNUmakefile"},
+		{"ci/Jenkinsfile", "Jenkinsfile"},
+		{"main.go", ""},
+		{"notes.txt", ""},
+	}
+
+	for _, test := range tests {
+		result, _, _ := p.fileNameType(test.path)
+		if result != test.expected {
+			t.Errorf("fileNameType(%s) = %s, expected %s", test.path, result, test.expected)
+		}
+	}
+
+	// A name wins over the extension it happens to have
+	if style, ok := p.lookupFileType("CMakeLists.txt"); !ok || style.LineComment != "#" {
+		t.Errorf("lookupFileType(CMakeLists.txt) = %+v, %v, expected a # comment", style, ok)
+	}
+}
+
+func TestFileNames(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "filenames-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"Dockerfile":            "FROM alpine\n",
+		"docker/Dockerfile.dev": "# syntax=docker/dockerfile:1\nFROM alpine\n",
+		"Makefile":              "all:\n\tgo build\n",
+		"Jenkinsfile":           "pipeline {}\n",
+		"CMakeLists.txt":        "project(a)\n",
+		"Earthfile":             "VERSION 0.8\n",
+		"notes.txt":             "notes\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := models.NewConfig().WithFileName("Earthfile", models.LineStyle("//"))
+	stats, err := NewProcessor(tempDir, &Options{Config: config}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Updated != 6 {
+		t.Errorf("Expected 6 files updated, got: %d", stats.Updated)
+	}
+
+	// The parser directive stays on the first line, and the config overrides
+	// the built-in style of Earthfile
+	expected := map[string]string{
+		"Dockerfile":            "# File: Dockerfile\nFROM alpine\n",
+		"docker/Dockerfile.dev": "# syntax=docker/dockerfile:1\n# File: docker/Dockerfile.dev\nFROM alpine\n",
+		"Makefile":              "# File: Makefile\nall:\n\tgo build\n",
+		"Jenkinsfile":           "// File: Jenkinsfile\npipeline {}\n",
+		"CMakeLists.txt":        "# File: CMakeLists.txt\nproject(a)\n",
+		"Earthfile":             "// File: Earthfile\nVERSION 0.8\n",
+		"notes.txt":             files["notes.txt"],
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+
+	// Name-matched files belong to languages
+	p := NewProcessor(tempDir, &Options{Config: models.NewConfig(), Languages: []string{"docker"}, Remove: true})
+	if _, err := p.Process(); err != nil {
+		t.Fatalf("Processor.Process with Languages failed: %v", err)
+	}
+	for _, result := range p.Results() {
+		updated := result.Action == models.ActionUpdated
+		docker := filepath.Base(result.Path) == "Dockerfile" || filepath.Base(result.Path) == "Dockerfile.dev"
+		if updated != docker {
+			t.Errorf("%s was %s (%s) with --lang docker", result.Path, result.Action, result.Reason)
+		}
+	}
+}
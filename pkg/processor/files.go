@@ -0,0 +1,135 @@
+// File: pkg/processor/files.go
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// walkFiles visits the listed files instead of walking the root directory.
+// Listed directories are walked; each path is visited once.
+func (p *Processor) walkFiles(files []string, walkFn fs.WalkDirFunc) error {
+	seen := make(map[string]bool)
+	for _, name := range files {
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(p.rootDir, path)
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		relPath, err := filepath.Rel(p.rootDir, path)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			err = fmt.Errorf("%s is outside %s", name, p.rootDir)
+			if p.options.Verbose {
+				p.errorf("Error: %v\n", err)
+			}
+			p.statistics.Errors++
+			p.record(path, models.ActionError, "", err)
+			if err := p.stopOnError(path, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if reason := p.excludedDir(relPath); reason != "" {
+			if p.options.Verbose {
+				p.printf("Skipping %s: %s\n", reason, path)
+			}
+			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, reason, nil)
+			continue
+		}
+
+		info, err := p.lstat(path)
+		if err != nil {
+			if p.options.Verbose {
+				p.errorf("Error accessing file %s: %v\n", path, err)
+			}
+			p.statistics.Errors++
+			p.record(path, models.ActionError, "", err)
+			if err := p.stopOnError(relPath, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if info.IsDir() {
+			err = p.walkDir(path, walkFn)
+		} else {
+			err = walkFn(path, fs.FileInfoToDirEntry(info), nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excludedDir returns why a walk would not reach relPath because of a
+// directory above it, or "" if it would
+func (p *Processor) excludedDir(relPath string) string {
+	dir := p.rootDir
+	for _, name := range strings.Split(filepath.Dir(relPath), string(filepath.Separator)) {
+		if name == "." {
+			continue
+		}
+		dir = filepath.Join(dir, name)
+		if dir == p.State().Path() {
+			return "pathfix state"
+		}
+		if !p.options.IncludeHidden && isHiddenPath(dir, name) {
+			return "hidden directory"
+		}
+	}
+	return ""
+}
+
+// filesToProcess returns the files a run visits instead of walking the root
+// directory: Options.Files, or those Staged or Tracked selects from git. It
+// returns nil to walk the root.
+func (p *Processor) filesToProcess() ([]string, error) {
+	if !p.options.Staged && !p.options.Tracked {
+		return p.options.Files, nil
+	}
+	switch {
+	case p.options.Staged && p.options.Tracked:
+		return nil, errors.New("Staged and Tracked cannot be combined")
+	case p.options.Files != nil:
+		return nil, errors.New("Files cannot be combined with Staged or Tracked")
+	case p.options.FS != nil:
+		return nil, errors.New("Staged and Tracked need the git work tree on disk, not Options.FS")
+	}
+
+	// Both list the paths beneath the root relative to it
+	args := []string{"ls-files", "-z"}
+	if p.options.Staged {
+		args = []string{"diff", "--cached", "--name-only", "-z", "--diff-filter=ACMR", "--relative"}
+	}
+	out, err := runGit(p.rootDir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tracked files may be deleted from the work tree, and submodules are
+	// directories of their own repositories
+	files := []string{}
+	for _, name := range bytes.Split(out, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		path := filepath.Join(p.rootDir, filepath.FromSlash(string(name)))
+		if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,146 @@
+// File: pkg/processor/files_test.go
+package processor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestProcessFiles(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-files-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := []string{"a.go", "b.go", "sub/c.go", "sub/d.go", ".hidden/e.go", "vendor/f.go"}
+	for _, name := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte("package a\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("vendor/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+
+	options := &Options{
+		DryRun: true,
+		Files: []string{
+			"a.go",
+			filepath.Join(tempDir, "a.go"), // The same file again
+			"sub",
+			".hidden/e.go",
+			"vendor/f.go",
+			"missing.go",
+			"../outside.go",
+		},
+	}
+	p := NewProcessor(tempDir, options)
+	stats, _ := p.Process()
+	if stats.Processed != 3 || stats.Updated != 3 || stats.Errors != 2 || stats.Skipped != 2 {
+		t.Errorf("Process() = %+v, expected 3 files updated, 2 skipped and 2 errors", stats)
+	}
+
+	expected := map[string]string{
+		"a.go":         models.ActionUpdated,
+		"sub/c.go":     models.ActionUpdated,
+		"sub/d.go":     models.ActionUpdated,
+		".hidden/e.go": models.ActionSkipped,
+		"vendor/f.go":  models.ActionSkipped,
+		"missing.go":   models.ActionError,
+	}
+	for _, result := range p.Results() {
+		if action, ok := expected[result.Path]; ok && result.Action != action {
+			t.Errorf("Process(%s) = %s, expected %s", result.Path, result.Action, action)
+		}
+		if result.Path == "b.go" {
+			t.Errorf("Process() visited b.go, which is not listed")
+		}
+	}
+
+	// An empty list processes nothing rather than the whole directory
+	p = NewProcessor(tempDir, &Options{DryRun: true, Files: []string{}})
+	if stats, _ := p.Process(); stats.Processed != 0 {
+		t.Errorf("Process() with no files processed %d files, expected 0", stats.Processed)
+	}
+}
+
+func TestGitFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tempDir, err := os.MkdirTemp("", "pathfix-gitfiles-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	t.Setenv("GIT_AUTHOR_NAME", "pathfix")
+	t.Setenv("GIT_AUTHOR_EMAIL", "pathfix@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "pathfix")
+	t.Setenv("GIT_COMMITTER_EMAIL", "pathfix@example.com")
+
+	// committed.go and deleted.go are committed, then deleted.go is removed
+	// from the work tree; staged.go is staged, and untracked.go is neither
+	git := func(args ...string) {
+		if _, err := runGit(tempDir, args...); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	git("init", "-q")
+	for _, name := range []string{"src/committed.go", "src/deleted.go", "src/staged.go", "src/untracked.go", "top.go"} {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte("package src\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	git("add", "src/committed.go", "src/deleted.go", "top.go")
+	git("commit", "-q", "-m", "add")
+	git("add", "src/staged.go")
+	if err := os.Remove(filepath.Join(tempDir, "src", "deleted.go")); err != nil {
+		t.Fatalf("Failed to remove deleted.go: %v", err)
+	}
+
+	testCases := []struct {
+		root     string
+		options  Options
+		expected string
+	}{
+		{".", Options{Staged: true}, "src/staged.go"},
+		{".", Options{Tracked: true}, "src/committed.go src/staged.go top.go"},
+		{"src", Options{Tracked: true}, "committed.go staged.go"},
+	}
+	for _, tc := range testCases {
+		options := tc.options
+		options.DryRun = true
+		p := NewProcessor(filepath.Join(tempDir, tc.root), &options)
+		if _, err := p.Process(); err != nil {
+			t.Errorf("Process(%s, %+v) failed: %v", tc.root, tc.options, err)
+			continue
+		}
+		var paths []string
+		for _, result := range p.Results() {
+			if result.Action == models.ActionUpdated {
+				paths = append(paths, result.Path)
+			}
+		}
+		if got := strings.Join(paths, " "); got != tc.expected {
+			t.Errorf("Process(%s, %+v) updated %q, expected %q", tc.root, tc.options, got, tc.expected)
+		}
+	}
+
+	if _, err := NewProcessor(tempDir, &Options{Staged: true, Tracked: true}).Process(); err == nil {
+		t.Errorf("Process() with Staged and Tracked succeeded, expected an error")
+	}
+}
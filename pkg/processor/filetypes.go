@@ -0,0 +1,233 @@
+// File: pkg/processor/filetypes.go
+package processor
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// jsonCommentStyle is the style used for .json files that opt in to comments
+var jsonCommentStyle = models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"}
+
+// WellKnownJSONCommentPaths lists JSON files whose consumers tolerate comments.
+// They are not enabled by default; copy them into JSONCommentPaths to opt in.
+var WellKnownJSONCommentPaths = []string{
+	"tsconfig.json",
+	"tsconfig.*.json",
+	"jsconfig.json",
+	".vscode/*.json",
+	"devcontainer.json",
+	".devcontainer.json",
+}
+
+// lookupFileType resolves the comment style for a file from its relative path
+func (p *Processor) lookupFileType(relPath string) (models.CommentStyle, bool) {
+	relPath = filepath.ToSlash(relPath)
+	ext := strings.ToLower(path.Ext(relPath))
+
+	// Plain JSON cannot hold comments, so only opted-in paths are processed
+	if ext == ".json" {
+		if _, ok := p.fileTypes[ext]; !ok && p.matchesAny(relPath, p.config.JSONCommentPaths) {
+			return jsonCommentStyle, true
+		}
+	}
+
+	// Files known by name, such as Dockerfile, win over their extension
+	if _, style, ok := p.fileNameType(relPath); ok {
+		return style, true
+	}
+
+	// Compound extensions, such as .d.ts, win over the last one
+	for _, ext := range fileExtensions(relPath) {
+		if style, ok := p.fileTypes[ext]; ok {
+			return style, true
+		}
+	}
+	return models.CommentStyle{}, false
+}
+
+// fileExtensions returns the extensions the name of relPath ends with,
+// lowercased and longest first: ".d.ts" and ".ts" for "types.d.ts". The
+// leading dot of a hidden file only counts when there is no other, as in
+// ".htaccess".
+func fileExtensions(relPath string) []string {
+	name := strings.ToLower(path.Base(filepath.ToSlash(relPath)))
+	last := strings.LastIndex(name, ".")
+	var exts []string
+	for i := 0; i <= last; i++ {
+		if name[i] == '.' && (i > 0 || i == last) {
+			exts = append(exts, name[i:])
+		}
+	}
+	return exts
+}
+
+// sortedPatterns returns the patterns of fileNames in sorted order, the
+// order in which fileNameType tries them
+func sortedPatterns(fileNames map[string]models.CommentStyle) []string {
+	patterns := make([]string, 0, len(fileNames))
+	for pattern := range fileNames {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// fileNameType returns the FileNames pattern relPath matches and its style.
+// The longest matching pattern wins, and patterns of equal length are tried
+// in sorted order.
+func (p *Processor) fileNameType(relPath string) (string, models.CommentStyle, bool) {
+	longest := ""
+	for _, pattern := range p.nameGlobs {
+		if len(pattern) > len(longest) && p.matchesAny(relPath, []string{pattern}) {
+			longest = pattern
+		}
+	}
+	if longest == "" {
+		return "", models.CommentStyle{}, false
+	}
+	return longest, p.fileNames[longest], true
+}
+
+// matchesAny reports whether relPath matches any of the glob patterns
+func (p *Processor) matchesAny(relPath string, patterns []string) bool {
+	if p.config.IgnoreCase {
+		relPath = strings.ToLower(relPath)
+	}
+	for _, pattern := range patterns {
+		if p.config.IgnoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		if matchPathGlob(relPath, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches a slash-separated relative path against a glob.
+// Patterns containing a slash match the whole path, others match the base name.
+func matchPathGlob(relPath, pattern string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, relPath)
+		return matched
+	}
+	matched, _ := path.Match(pattern, path.Base(relPath))
+	return matched
+}
+
+// shebangTypes maps script interpreters, without version suffixes, to the
+// extension whose comment style their extension-less scripts use
+var shebangTypes = map[string]string{
+	"sh":         ".sh",
+	"ash":        ".sh",
+	"dash":       ".sh",
+	"bash":       ".bash",
+	"zsh":        ".zsh",
+	"ksh":        ".ksh",
+	"mksh":       ".ksh",
+	"csh":        ".csh",
+	"tcsh":       ".csh",
+	"fish":       ".fish",
+	"python":     ".py",
+	"pypy":       ".py",
+	"ruby":       ".rb",
+	"perl":       ".pl",
+	"node":       ".js",
+	"nodejs":     ".js",
+	"lua":        ".lua",
+	"luajit":     ".lua",
+	"pwsh":       ".ps1",
+	"powershell": ".ps1",
+	"groovy":     ".groovy",
+	"kscript":    ".kts",
+	"scala":      ".scala",
+	"swift":      ".swift",
+	"dart":       ".dart",
+	"Rscript":    ".r",
+	"tclsh":      ".tcl",
+	"julia":      ".jl",
+	"elixir":     ".exs",
+	"runhaskell": ".hs",
+	"runghc":     ".hs",
+}
+
+// isExecutableEntry reports whether a file without an extension found by
+// the walk is an executable script whose interpreter has a known comment
+// style, for the IncludeExecutables option
+func (p *Processor) isExecutableEntry(filePath string, d fs.DirEntry) bool {
+	if !p.config.IncludeExecutables || path.Ext(d.Name()) != "" || !p.isExecutable(filePath) {
+		return false
+	}
+	file, err := p.openFile(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	head := make([]byte, 256)
+	n, _ := io.ReadFull(file, head)
+	_, ok := p.shebangFileType(d.Name(), head[:n])
+	return ok
+}
+
+// isExecutableBuffer applies isExecutableEntry to the unsaved content of the
+// file at relPath, whose mode is read from the root directory
+func (p *Processor) isExecutableBuffer(relPath string, content []byte) bool {
+	if !p.isExecutable(filepath.Join(p.rootDir, filepath.FromSlash(relPath))) {
+		return false
+	}
+	_, ok := p.shebangFileType(relPath, content)
+	return ok
+}
+
+// isExecutable reports whether filePath is a regular file with an execute
+// permission bit set, which Windows never reports
+func (p *Processor) isExecutable(filePath string) bool {
+	info, err := p.stat(filePath)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// shebangFileType resolves the comment style of a file without an extension
+// from the interpreter named on its shebang line
+func (p *Processor) shebangFileType(relPath string, content []byte) (models.CommentStyle, bool) {
+	if !p.config.IncludeExecutables || path.Ext(filepath.ToSlash(relPath)) != "" {
+		return models.CommentStyle{}, false
+	}
+	content = bytes.TrimPrefix(content, utf8BOM)
+	line, _ := firstLine(content)
+	if !isShebang(line) {
+		return models.CommentStyle{}, false
+	}
+	ext := shebangTypes[shebangInterpreter(line)]
+	if !p.languageSelected(ext) {
+		return models.CommentStyle{}, false
+	}
+	style, ok := p.fileTypes[ext]
+	return style, ok
+}
+
+// shebangInterpreter returns the name of the program a shebang line runs,
+// looking through env and dropping version suffixes such as the "3.11" of
+// python3.11
+func shebangInterpreter(line string) string {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimRight(line, "\r"), "#!"))
+	if len(fields) > 0 && path.Base(fields[0]) == "env" {
+		// Skip env's options and variable assignments
+		fields = fields[1:]
+		for len(fields) > 0 && (strings.HasPrefix(fields[0], "-") || strings.Contains(fields[0], "=")) {
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimRight(path.Base(fields[0]), "0123456789.")
+}
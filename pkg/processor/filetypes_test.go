@@ -0,0 +1,225 @@
+// File: pkg/processor/filetypes_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestShebangInterpreter(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected string
+	}{
+		{"#!/bin/sh", "sh"},
+		{"#!/usr/bin/env python3", "python"},
+		{"#!/usr/bin/python3.11 -u", "python"},
+		{"#! /usr/bin/env -S node --no-warnings", "node"},
+		{"#!/usr/bin/env LANG=C perl -w\r", "perl"},
+		{"#!/usr/bin/env", ""},
+		{"#!", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := shebangInterpreter(tc.line); got != tc.expected {
+			t.Errorf("shebangInterpreter(%q) = %q, expected %q", tc.line, got, tc.expected)
+		}
+	}
+}
+
+func TestIncludeExecutables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows files have no execute permission bits")
+	}
+	tempDir, err := os.MkdirTemp("", "pathfix-executables-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := []struct {
+		name     string
+		content  string
+		mode     os.FileMode
+		expected string // Content after the run with IncludeExecutables
+	}{
+		{"bin/deploy", "#!/bin/bash\necho deploy\n", 0755, "#!/bin/bash\n# File: bin/deploy\necho deploy\n"},
+		{"bin/migrate", "#!/usr/bin/env python3\nprint()\n", 0755, "#!/usr/bin/env python3\n# File: bin/migrate\nprint()\n"},
+		{"bin/notes", "#!/bin/bash\necho notes\n", 0644, "#!/bin/bash\necho notes\n"},
+		{"bin/tool", "\x7fELF\x02\x01\x01", 0755, "\x7fELF\x02\x01\x01"},
+		{"bin/custom", "#!/usr/bin/env unknown-interpreter\n", 0755, "#!/usr/bin/env unknown-interpreter\n"},
+	}
+	for _, file := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(file.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", file.name, err)
+		}
+		if err := os.WriteFile(path, []byte(file.content), file.mode); err != nil {
+			t.Fatalf("Failed to create %s: %v", file.name, err)
+		}
+	}
+
+	// Without the option, extension-less files are not candidates
+	stats, err := NewProcessor(tempDir, &Options{DryRun: true}).Process()
+	if err != nil || stats.Processed != 0 {
+		t.Errorf("Process() = %+v, %v, expected no files processed", stats, err)
+	}
+
+	if _, err := NewProcessor(tempDir, &Options{IncludeExecutables: true}).Process(); err != nil {
+		t.Fatalf("Process(IncludeExecutables) failed: %v", err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(file.name)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file.name, err)
+		}
+		if string(content) != file.expected {
+			t.Errorf("Process(IncludeExecutables) wrote %q to %s, expected %q", content, file.name, file.expected)
+		}
+	}
+}
+
+func TestAdditionalLanguageHeaders(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-languages-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"Main.hs", "module Main where\n", "-- File: Main.hs\nmodule Main where\n"},
+		{"lib.ml", "let x = 1\n", "(* File: lib.ml *)\nlet x = 1\n"},
+		{"lib.mli", "val x : int\n", "(* File: lib.mli *)\nval x : int\n"},
+		{"app.ex", "defmodule App do\nend\n", "# File: app.ex\ndefmodule App do\nend\n"},
+		{"run.exs", "#!/usr/bin/env elixir\nIO.puts(1)\n", "#!/usr/bin/env elixir\n# File: run.exs\nIO.puts(1)\n"},
+		{"app.erl", "-module(app).\n", "% File: app.erl\n-module(app).\n"},
+		{"core.clj", "(ns core)\n", ";; File: core.clj\n(ns core)\n"},
+		{"util.lisp", "(defun f () 1)\n", ";; File: util.lisp\n(defun f () 1)\n"},
+		{"stats.R", "x <- 1\n", "# File: stats.R\nx <- 1\n"},
+		{"fit.jl", "using Stats\n", "# File: fit.jl\nusing Stats\n"},
+		{"solve.m", "function y = solve(x)\n", "% File: solve.m\nfunction y = solve(x)\n"},
+		{"View.m", "#import \"View.h\"\n", "// File: View.m\n#import \"View.h\"\n"},
+		{"paper.tex", "\\documentclass{article}\n", "% File: paper.tex\n\\documentclass{article}\n"},
+		{"calc.f90", "program calc\nend program\n", "! File: calc.f90\nprogram calc\nend program\n"},
+		{"legacy.F", "      PROGRAM LEGACY\n", "! File: legacy.F\n      PROGRAM LEGACY\n"},
+		{"boot.asm", "section .text\n", "; File: boot.asm\nsection .text\n"},
+		{"start.s", ".globl _start\n", "/* File: start.s */\n.globl _start\n"},
+		{"main.tf", "resource \"null_resource\" \"a\" {}\n", "# File: main.tf\nresource \"null_resource\" \"a\" {}\n"},
+		{"prod.tfvars", "region = \"eu\"\n", "# File: prod.tfvars\nregion = \"eu\"\n"},
+		{"user.proto", "syntax = \"proto3\";\n", "// File: user.proto\nsyntax = \"proto3\";\n"},
+		{"schema.graphql", "type Query {}\n", "# File: schema.graphql\ntype Query {}\n"},
+		{"nginx.conf", "events {}\n", "# File: nginx.conf\nevents {}\n"},
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, file.name), []byte(file.content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file.name, err)
+		}
+	}
+
+	// Run twice to make sure the headers are recognized on the second pass
+	for i := 0; i < 2; i++ {
+		if _, err := NewProcessor(tempDir, &Options{}).Process(); err != nil {
+			t.Fatalf("Processor.Process failed: %v", err)
+		}
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(tempDir, file.name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file.name, err)
+		}
+		if string(content) != file.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", file.name, string(content), file.expected)
+		}
+	}
+}
+
+func TestFileExtensions(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected []string
+	}{
+		{"src/types.d.ts", []string{".d.ts", ".ts"}},
+		{"views/Home.Blade.PHP", []string{".blade.php", ".php"}},
+		{"main.go", []string{".go"}},
+		{".htaccess", []string{".htaccess"}},
+		{".eslintrc.js", []string{".js"}},
+		{"Makefile", nil},
+	}
+
+	for _, tc := range testCases {
+		got := fileExtensions(tc.path)
+		if strings.Join(got, " ") != strings.Join(tc.expected, " ") {
+			t.Errorf("fileExtensions(%s) = %v, expected %v", tc.path, got, tc.expected)
+		}
+	}
+}
+
+func TestCompoundExtensions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-compound-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"home.blade.php": "<h1>{{ $title }}</h1>\n",
+		"types.d.ts":     "declare const x: number;\n",
+		"app.ts":         "const x = 1;\n",
+		"app.test.js":    "test('x', () => {});\n",
+		"app.js":         "run();\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	// A compound extension configured by the user wins over the last one, and
+	// excluding one leaves the others
+	config := models.NewConfig().WithFileType(".test.js", models.BlockStyle("/*", "*/"))
+	options := &Options{Config: config, ExcludeLanguages: []string{".d.ts"}}
+	if _, err := NewProcessor(tempDir, options).Process(); err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"home.blade.php": "{{-- File: home.blade.php --}}\n<h1>{{ $title }}</h1>\n",
+		"types.d.ts":     files["types.d.ts"],
+		"app.ts":         "// File: app.ts\nconst x = 1;\n",
+		"app.test.js":    "/* File: app.test.js */\ntest('x', () => {});\n",
+		"app.js":         "// File: app.js\nrun();\n",
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+
+	// Languages select files by any of their extensions
+	p := NewProcessor(tempDir, &Options{Config: config, Languages: []string{".ts"}, ExcludeLanguages: []string{}})
+	for _, test := range []struct {
+		path     string
+		expected bool
+	}{
+		{"types.d.ts", true},
+		{"app.ts", true},
+		{"app.js", false},
+	} {
+		if got := p.languageSelected(p.languageKeys(test.path)...); got != test.expected {
+			t.Errorf("languageSelected(%s) with --lang .ts = %v, expected %v", test.path, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// File: pkg/processor/frontmatter.go
+package processor
+
+import (
+	"bytes"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// reasonDocs is the skip reason of documentation files without IncludeDocs
+const reasonDocs = "documentation file without --include-docs"
+
+// isDocFile reports whether relPath is in one of the documentation formats
+// that IncludeDocs adds
+func isDocFile(relPath string) bool {
+	_, ok := docFileTypes()[strings.ToLower(path.Ext(relPath))]
+	return ok
+}
+
+// usesFrontmatter reports whether the path of a file goes in its YAML
+// frontmatter, under FrontmatterField, rather than in a comment
+func (p *Processor) usesFrontmatter(content []byte, style models.CommentStyle) bool {
+	if p.config.FrontmatterField == "" || style.Placement != "frontmatter" {
+		return false
+	}
+	_, _, ok := yamlFrontmatter(content)
+	return ok
+}
+
+// yamlFrontmatter returns the offsets of the first line inside the YAML
+// frontmatter at the start of content and of its closing --- line, or false
+// if content does not start with a complete one
+func yamlFrontmatter(content []byte) (int, int, bool) {
+	offset := 0
+	if bytes.HasPrefix(content, utf8BOM) {
+		offset = len(utf8BOM)
+	}
+	line, n := firstLine(content[offset:])
+	if strings.TrimRight(line, "\r") != "---" {
+		return 0, 0, false
+	}
+	start := offset + n
+	for offset = start; offset < len(content); offset += n {
+		line, n = firstLine(content[offset:])
+		if strings.TrimRight(line, "\r") == "---" {
+			return start, offset, true
+		}
+	}
+	return 0, 0, false
+}
+
+// frontmatterHeader returns content with its path recorded in the YAML
+// frontmatter as FrontmatterField, or removed from it with Remove, and the
+// header it had already. A comment header below the frontmatter, written
+// before the field was configured, is removed as well.
+func (p *Processor) frontmatterHeader(relPath string, content []byte, style models.CommentStyle) ([]byte, priorHeader, error) {
+	start, end, _ := yamlFrontmatter(content)
+	field := p.config.FrontmatterField
+
+	// Find the field among the top-level keys
+	var prior priorHeader
+	lineStart, lineEnd := -1, -1
+	for offset := start; offset < end; {
+		line, n := firstLine(content[offset:])
+		if value, ok := strings.CutPrefix(line, field+":"); ok {
+			lineStart, lineEnd = offset, offset+n
+			prior.found = true
+			if old := yamlValue(value); !p.sameHeader(old, relPath) {
+				prior.movedFrom = old
+			}
+			break
+		}
+		offset += n
+	}
+
+	// Find a comment header below the frontmatter
+	_, closing := firstLine(content[end:])
+	after := end + closing
+	prefixes := p.headerPrefixes(p.commentPrefix(relPath))
+	comment := existingHeaderLen(content[after:], style, prefixes...)
+	if comment > 0 && !prior.found {
+		prior.found = true
+		if old := headerField(string(content[after:after+comment]), style, prefixes...); old != "" && !p.sameHeader(old, relPath) {
+			prior.movedFrom = old
+		}
+	}
+
+	if p.options.MovedOnly && !prior.found {
+		return nil, priorHeader{}, skipReason(reasonNoHeader)
+	}
+	if p.options.MovedOnly && prior.movedFrom == "" {
+		return content, prior, nil
+	}
+
+	// The new field line, unless the one there names the path already
+	var fieldLine string
+	if !p.options.Remove {
+		eol := "\n"
+		if line, _ := firstLine(content[end:]); strings.HasSuffix(line, "\r") {
+			eol = "\r\n"
+		}
+		fieldLine = field + ": " + yamlScalar(relPath) + eol
+		if lineStart >= 0 && prior.movedFrom == "" {
+			fieldLine = string(content[lineStart:lineEnd])
+		}
+	}
+	if lineStart < 0 {
+		// A new field goes at the end of the frontmatter
+		lineStart, lineEnd = end, end
+	}
+
+	newContent := make([]byte, 0, len(content)+len(fieldLine))
+	newContent = append(newContent, content[:lineStart]...)
+	newContent = append(newContent, fieldLine...)
+	newContent = append(newContent, content[lineEnd:after]...)
+	newContent = append(newContent, content[after+comment:]...)
+	return newContent, prior, nil
+}
+
+// yamlScalar returns s as a YAML value: plain if it is made of letters,
+// digits and the punctuation of ordinary paths, and double-quoted otherwise
+func yamlScalar(s string) string {
+	plain := s != "" && !strings.HasPrefix(s, "-")
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_./-+", r) {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// yamlValue returns the string a YAML scalar on a single line stands for
+func yamlValue(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, `"`) {
+		if value, err := strconv.Unquote(s); err == nil {
+			return value
+		}
+	}
+	if len(s) >= 2 && strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	// A comment ends a plain value
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+// File: pkg/processor/frontmatter_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestYAMLScalar(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"docs/guide.md", "docs/guide.md"},
+		{"docs/Über-uns_2.md", "docs/Über-uns_2.md"},
+		{"my notes.md", `"my notes.md"`},
+		{"-draft.md", `"-draft.md"`},
+		{"a:b#c.md", `"a:b#c.md"`},
+	}
+
+	for _, test := range tests {
+		result := yamlScalar(test.path)
+		if result != test.expected {
+			t.Errorf("yamlScalar(%s) = %s, expected %s", test.path, result, test.expected)
+		}
+		if value := yamlValue(" " + result + " "); value != test.path {
+			t.Errorf("yamlValue(%s) = %s, expected %s", result, value, test.path)
+		}
+	}
+	if value := yamlValue("'it''s.md'"); value != "it's.md" {
+		t.Errorf("yamlValue('it''s.md') = %s, expected it's.md", value)
+	}
+	if value := yamlValue("a.md # set by pathfix"); value != "a.md" {
+		t.Errorf("yamlValue(a.md # set by pathfix) = %s, expected a.md", value)
+	}
+}
+
+func TestFrontmatterField(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "frontmatter-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"docs/guide.md":  "---\ntitle: Guide\n---\n# Guide\n",
+		"docs/moved.md":  "---\npath: old/moved.md\ntitle: Moved\n---\nText\n",
+		"current.md":     "---\npath: \"current.md\"\n---\nText\n",
+		"old.md":         "---\ntitle: Old\n---\n<!-- File: old.md -->\nText\n",
+		"plain.md":       "# Plain\n",
+		"toml.md":        "+++\ntitle = \"TOML\"\n+++\nText\n",
+		"notes.markdown": "---\r\ntitle: Notes\r\n---\r\nText\r\n",
+		"my notes.md":    "---\n---\nText\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := models.NewConfig()
+	config.FrontmatterField = "path"
+	stats, err := NewProcessor(tempDir, &Options{Config: config, IncludeDocs: true}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Missing != 5 || stats.Stale != 2 {
+		t.Errorf("Expected 5 missing and 2 stale headers, got: %d missing, %d stale", stats.Missing, stats.Stale)
+	}
+
+	// Files without YAML frontmatter get a comment
+	expected := map[string]string{
+		"docs/guide.md":  "---\ntitle: Guide\npath: docs/guide.md\n---\n# Guide\n",
+		"docs/moved.md":  "---\npath: docs/moved.md\ntitle: Moved\n---\nText\n",
+		"current.md":     files["current.md"],
+		"old.md":         "---\ntitle: Old\npath: old.md\n---\nText\n",
+		"plain.md":       "<!-- File: plain.md -->\n# Plain\n",
+		"toml.md":        "+++\ntitle = \"TOML\"\n+++\n<!-- File: toml.md -->\nText\n",
+		"notes.markdown": "---\r\ntitle: Notes\r\npath: notes.markdown\r\n---\r\nText\r\n",
+		"my notes.md":    "---\npath: \"my notes.md\"\n---\nText\n",
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+
+	if stats, err := NewProcessor(tempDir, &Options{Config: config, IncludeDocs: true}).Process(); err != nil || stats.Updated != 0 {
+		t.Errorf("second run = %+v, %v, expected no updates", stats, err)
+	}
+
+	// Remove takes the field out again
+	if _, err := NewProcessor(tempDir, &Options{Config: config, IncludeDocs: true, Remove: true}).Process(); err != nil {
+		t.Fatalf("Processor.Process with Remove failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "docs", "guide.md"))
+	if err != nil || string(content) != files["docs/guide.md"] {
+		t.Errorf("docs/guide.md after Remove = %q, %v, expected %q", content, err, files["docs/guide.md"])
+	}
+
+	// Without IncludeDocs, Markdown files are skipped with a reason
+	p := NewProcessor(tempDir, &Options{Config: config, DryRun: true})
+	if _, err := p.Process(); err != nil {
+		t.Fatalf("Processor.Process without IncludeDocs failed: %v", err)
+	}
+	for _, result := range p.Results() {
+		if result.Reason != reasonDocs {
+			t.Errorf("%s was %s (%s), expected it skipped as %q", result.Path, result.Action, result.Reason, reasonDocs)
+		}
+	}
+}
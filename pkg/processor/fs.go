@@ -0,0 +1,133 @@
+// File: pkg/processor/fs.go
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteFileFS is a file system that files can be written to, for runs over
+// Options.FS that are not dry runs
+type WriteFileFS interface {
+	fs.FS
+
+	// WriteFile replaces the content of the named file, which exists. The
+	// permissions are those the processor would give a new file.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// errReadOnlyFS is returned for writes to an Options.FS without WriteFile
+var errReadOnlyFS = errors.New("file system is read-only")
+
+// The file operations of a run go through these methods, which use
+// Options.FS when it is set and the disk otherwise. Paths are the walk's,
+// beneath the root directory; on Options.FS, the root directory is ".".
+
+// fsName returns the name in Options.FS of a path beneath the root directory
+func (p *Processor) fsName(op, path string) (string, error) {
+	relPath, err := filepath.Rel(p.rootDir, path)
+	name := filepath.ToSlash(relPath)
+	if err != nil || !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: path, Err: fs.ErrInvalid}
+	}
+	return name, nil
+}
+
+// readFile returns the content of a file
+func (p *Processor) readFile(path string) ([]byte, error) {
+	if p.options.FS == nil {
+		return os.ReadFile(path)
+	}
+	name, err := p.fsName("read", path)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(p.options.FS, name)
+}
+
+// writeFile replaces the content of a file
+func (p *Processor) writeFile(path string, data []byte) error {
+	if p.options.FS == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+	name, err := p.fsName("write", path)
+	if err != nil {
+		return err
+	}
+	fsys, ok := p.options.FS.(WriteFileFS)
+	if !ok {
+		return &fs.PathError{Op: "write", Path: name, Err: errReadOnlyFS}
+	}
+	return fsys.WriteFile(name, data, 0644)
+}
+
+// openFile opens a file for reading
+func (p *Processor) openFile(path string) (fs.File, error) {
+	if p.options.FS == nil {
+		return os.Open(path)
+	}
+	name, err := p.fsName("open", path)
+	if err != nil {
+		return nil, err
+	}
+	return p.options.FS.Open(name)
+}
+
+// stat describes a file, following symbolic links
+func (p *Processor) stat(path string) (fs.FileInfo, error) {
+	if p.options.FS == nil {
+		return os.Stat(path)
+	}
+	name, err := p.fsName("stat", path)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(p.options.FS, name)
+}
+
+// lstat describes a file without following a symbolic link. io/fs has no
+// links of its own, so on Options.FS it is stat.
+func (p *Processor) lstat(path string) (fs.FileInfo, error) {
+	if p.options.FS == nil {
+		return os.Lstat(path)
+	}
+	return p.stat(path)
+}
+
+// walkDir walks the tree at root, as filepath.WalkDir does, calling walkFn
+// with paths beneath the root directory
+func (p *Processor) walkDir(root string, walkFn fs.WalkDirFunc) error {
+	if p.options.FS == nil {
+		return filepath.WalkDir(root, walkFn)
+	}
+	start, err := p.fsName("walk", root)
+	if err != nil {
+		return walkFn(root, nil, err)
+	}
+	return fs.WalkDir(p.options.FS, start, func(name string, d fs.DirEntry, err error) error {
+		path := root
+		if name != start {
+			path = filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(name, start+"/")))
+			if start == "." {
+				path = filepath.Join(root, filepath.FromSlash(name))
+			}
+		}
+		return walkFn(path, d, err)
+	})
+}
+
+// readGitIgnoreFS returns the patterns of the .gitignore at the top of
+// Options.FS, or none if there is none
+func readGitIgnoreFS(fsys fs.FS) ([]gitIgnorePattern, error) {
+	data, err := fs.ReadFile(fsys, ".gitignore")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error loading .gitignore: %w", err)
+	}
+	return parseGitIgnore(strings.Split(string(data), "\n")), nil
+}
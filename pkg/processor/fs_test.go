@@ -0,0 +1,89 @@
+// File: pkg/processor/fs_test.go
+package processor
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// memFS is a writable in-memory file system
+type memFS struct {
+	fstest.MapFS
+}
+
+func (m memFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	file, ok := m.MapFS[name]
+	if !ok {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+	}
+	file.Data = data
+	return nil
+}
+
+func TestProcessFS(t *testing.T) {
+	newFS := func() fstest.MapFS {
+		return fstest.MapFS{
+			".gitignore":      {Data: []byte("build/\n")},
+			"main.go":         {Data: []byte("package main\n")},
+			"lib/util.py":     {Data: []byte("# File: lib/util.py\nx = 1\n")},
+			"lib/old.js":      {Data: []byte("// File: old.js\nlet x;\n")},
+			"build/gen.go":    {Data: []byte("package gen\n")},
+			"assets/logo.png": {Data: []byte("\x89PNG\r\n")},
+		}
+	}
+	root := filepath.FromSlash("/virtual")
+
+	// Any fs.FS, such as an embedded tree, can be checked
+	p := NewProcessor(root, &Options{DryRun: true, FS: newFS()})
+	stats, err := p.Process()
+	if err != nil {
+		t.Fatalf("Process() on a read-only FS failed: %v", err)
+	}
+	if stats.Processed != 3 || stats.Updated != 2 {
+		t.Errorf("dry run over FS = %+v, expected 3 files processed and 2 to update", stats)
+	}
+	for _, result := range p.Results() {
+		if result.Path == "build/gen.go" && result.Reason != "gitignored file" {
+			t.Errorf("build/gen.go was %s (%s), expected it gitignored", result.Action, result.Reason)
+		}
+	}
+
+	// Writing needs WriteFile
+	stats, err = NewProcessor(root, &Options{FS: newFS()}).Process()
+	var fileErrors FileErrors
+	if !errors.As(err, &fileErrors) || stats.Errors != 2 || !errors.Is(fileErrors[0].Err, errReadOnlyFS) {
+		t.Errorf("Process() writing to a read-only FS = %+v, %v, expected 2 read-only errors", stats, err)
+	}
+
+	fsys := memFS{newFS()}
+	if _, err := NewProcessor(root, &Options{FS: fsys}).Process(); err != nil {
+		t.Fatalf("Process() on a writable FS failed: %v", err)
+	}
+	expected := map[string]string{
+		"main.go":      "// File: main.go\npackage main\n",
+		"lib/util.py":  "# File: lib/util.py\nx = 1\n",
+		"lib/old.js":   "// File: lib/old.js\nlet x;\n",
+		"build/gen.go": "package gen\n",
+	}
+	for name, content := range expected {
+		if got := string(fsys.MapFS[name].Data); got != content {
+			t.Errorf("%s = %q, expected %q", name, got, content)
+		}
+	}
+
+	// Listed files are found in the FS too
+	p = NewProcessor(root, &Options{DryRun: true, FS: fsys, Files: []string{"lib"}})
+	if stats, err := p.Process(); err != nil || stats.Processed != 2 || stats.Updated != 0 {
+		t.Errorf("Process() of the listed lib = %+v, %v, expected 2 files up to date", stats, err)
+	}
+	for _, result := range p.Results() {
+		if result.Action != models.ActionUnchanged {
+			t.Errorf("%s was %s, expected unchanged", result.Path, result.Action)
+		}
+	}
+}
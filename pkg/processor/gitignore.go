@@ -4,48 +4,114 @@ package processor
 import (
 	"bufio"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
 
 // GitIgnore holds patterns from a .gitignore file
 type GitIgnore struct {
-	patterns []string
-	rootDir  string
+	patterns   []gitIgnorePattern
+	rootDir    string
+	prefix     string // The root directory relative to the top of its git work tree, ending in a slash, or ""
+	ignoreCase bool
+}
+
+// gitIgnorePattern is a line of a .gitignore file
+type gitIgnorePattern struct {
+	glob     string // The pattern without its "!", leading slash or trailing slash
+	negate   bool   // The line starts with "!", re-including what it matches
+	dirOnly  bool   // The line ends with a slash, so it matches only directories
+	anchored bool   // The line has another slash, so it matches from the root rather than any name
+	workTree bool   // The line is from one of git's exclude files, relative to the top of the work tree
 }
 
 // NewGitIgnore creates a new GitIgnore processor
 func NewGitIgnore(rootDir string) (*GitIgnore, error) {
-	gitignorePath := filepath.Join(rootDir, ".gitignore")
-	gi := &GitIgnore{
-		rootDir: rootDir,
+	// A missing .gitignore ignores nothing
+	patterns, err := readGitIgnoreFile(filepath.Join(rootDir, ".gitignore"))
+	if err != nil {
+		return nil, err
 	}
+	return &GitIgnore{
+		patterns: patterns,
+		rootDir:  rootDir,
+	}, nil
+}
 
-	file, err := os.Open(gitignorePath)
-	if err != nil {
-		// Return an empty gitignore if file doesn't exist
-		if os.IsNotExist(err) {
-			return gi, nil
+// parseGitIgnore parses the lines of a .gitignore file, skipping blank
+// lines and comments
+func parseGitIgnore(lines []string) []gitIgnorePattern {
+	var patterns []gitIgnorePattern
+	for _, line := range lines {
+		if pattern, ok := parseGitIgnorePattern(line); ok {
+			patterns = append(patterns, pattern)
 		}
-		return nil, err
 	}
-	defer file.Close()
+	return patterns
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+// parseGitIgnorePattern parses a line of a .gitignore file, reporting false
+// for blank lines and comments. Backslashes escape a leading "#" or "!"
+// and trailing spaces, which are otherwise dropped.
+func parseGitIgnorePattern(line string) (gitIgnorePattern, bool) {
+	line = trimTrailingSpaces(strings.TrimSuffix(line, "\r"))
+	if line == "" || line[0] == '#' {
+		return gitIgnorePattern{}, false
+	}
+
+	var pattern gitIgnorePattern
+	if line[0] == '!' {
+		pattern.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = line[:len(line)-1]
+	}
+	pattern.anchored = strings.Contains(line, "/")
+	pattern.glob = strings.TrimPrefix(line, "/")
+	return pattern, pattern.glob != ""
+}
+
+// trimTrailingSpaces removes the spaces at the end of line that are not
+// escaped with a backslash
+func trimTrailingSpaces(line string) string {
+	end := -1 // Where the trailing spaces start
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			if end < 0 {
+				end = i
+			}
+		case '\\':
+			i++
+			end = -1
+		default:
+			end = -1
 		}
-		gi.patterns = append(gi.patterns, line)
 	}
+	if end >= 0 {
+		return line[:end]
+	}
+	return line
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+// match reports whether the pattern matches relPath, a file or directory
+// path relative to the root with forward slashes
+func (pattern gitIgnorePattern) match(relPath string, isDir, ignoreCase bool) bool {
+	if pattern.dirOnly && !isDir {
+		return false
+	}
+	if !pattern.anchored {
+		relPath = path.Base(relPath)
 	}
+	return wildmatch(pattern.glob, relPath, ignoreCase)
+}
 
-	return gi, nil
+// SetIgnoreCase makes pattern matching case-insensitive, like git's core.ignorecase
+func (gi *GitIgnore) SetIgnoreCase(ignoreCase bool) {
+	gi.ignoreCase = ignoreCase
 }
 
 // ShouldIgnore checks if a file should be ignored based on .gitignore patterns
@@ -59,117 +125,239 @@ func (gi *GitIgnore) ShouldIgnore(path string) bool {
 	// Normalize path separators to forward slashes
 	relPath = filepath.ToSlash(relPath)
 
-	// First pass: find if the file is ignored by any pattern
-	isIgnored := false
-	for _, pattern := range gi.patterns {
-		// Skip negated patterns in the first pass
-		if strings.HasPrefix(pattern, "!") {
-			continue
+	// Git does not look inside an excluded directory, so a file beneath one
+	// cannot be re-included
+	for i := 0; i < len(relPath); i++ {
+		if relPath[i] == '/' && gi.excluded(relPath[:i], true) {
+			return true
 		}
+	}
+	return gi.excluded(relPath, false)
+}
 
-		// Check if file matches any non-negated pattern
-		if matchGitIgnorePattern(relPath, pattern) {
-			isIgnored = true
-			break
+// ignoresDir reports whether the directory at path is excluded, so that
+// nothing beneath it can be re-included
+func (gi *GitIgnore) ignoresDir(path string) bool {
+	relPath, err := filepath.Rel(gi.rootDir, path)
+	if err != nil {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	for i := 0; i < len(relPath); i++ {
+		if relPath[i] == '/' && gi.excluded(relPath[:i], true) {
+			return true
 		}
 	}
+	return gi.excluded(relPath, true)
+}
 
-	// If the file is not ignored by any pattern, no need to check negations
-	if !isIgnored {
+// excluded reports whether the last pattern matching relPath, if any,
+// excludes it rather than re-including it
+func (gi *GitIgnore) excluded(relPath string, isDir bool) bool {
+	for i := len(gi.patterns) - 1; i >= 0; i-- {
+		pattern := gi.patterns[i]
+		target := relPath
+		if pattern.workTree {
+			target = gi.prefix + relPath
+		}
+		if pattern.match(target, isDir, gi.ignoreCase) {
+			return !pattern.negate
+		}
+	}
+	return false
+}
+
+// matchGitIgnorePattern checks if a file path, or a directory above it,
+// matches a gitignore pattern, disregarding any negation
+func matchGitIgnorePattern(path, line string) bool {
+	pattern, ok := parseGitIgnorePattern(line)
+	if !ok {
 		return false
 	}
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && pattern.match(path[:i], true, false) {
+			return true
+		}
+	}
+	return pattern.match(path, false, false)
+}
+
+// LoadGitExcludes adds the patterns git reads besides .gitignore when the
+// root directory is in a git work tree: those of $GIT_DIR/info/exclude and
+// of the file named by core.excludesFile (by default git/ignore in the
+// user config directory). They are relative to the top of the work tree,
+// and the patterns of .gitignore take precedence over them.
+func (gi *GitIgnore) LoadGitExcludes() error {
+	workTree, gitDir := findGitDir(gi.rootDir)
+	if gitDir == "" {
+		return nil
+	}
+	prefix, err := filepath.Rel(workTree, gi.rootDir)
+	if err != nil {
+		return err
+	}
 
-	// Second pass: check if the file is explicitly included by a negated pattern
-	for _, pattern := range gi.patterns {
-		// Only process negated patterns
-		if !strings.HasPrefix(pattern, "!") {
+	var excludes []gitIgnorePattern
+	for _, name := range []string{gitExcludesFile(gitDir), filepath.Join(gitCommonDir(gitDir), "info", "exclude")} {
+		if name == "" {
 			continue
 		}
+		patterns, err := readGitIgnoreFile(name)
+		if err != nil {
+			return err
+		}
+		excludes = append(excludes, patterns...)
+	}
+	for i := range excludes {
+		excludes[i].workTree = true
+	}
+	gi.patterns = append(excludes, gi.patterns...)
+	if prefix = filepath.ToSlash(prefix); prefix != "." {
+		gi.prefix = prefix + "/"
+	}
+	return nil
+}
 
-		// Remove the leading ! for matching
-		negatedPattern := pattern[1:]
-		if matchGitIgnorePattern(relPath, negatedPattern) {
-			// This file is explicitly included
-			return false
+// findGitDir returns the top of the git work tree containing dir and its
+// git directory, or "" if dir is not in one. A .git file, as in linked
+// work trees and submodules, points to the git directory.
+func findGitDir(dir string) (string, string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			if info.IsDir() {
+				return dir, dotGit
+			}
+			data, err := os.ReadFile(dotGit)
+			if err != nil {
+				return "", ""
+			}
+			gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+			if !ok {
+				return "", ""
+			}
+			gitDir = filepath.FromSlash(strings.TrimSpace(gitDir))
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(dir, gitDir)
+			}
+			return dir, gitDir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
 		}
+		dir = parent
 	}
+}
 
-	// File is ignored by at least one pattern and not explicitly included
-	return true
+// gitCommonDir returns the directory holding the config and info/exclude
+// shared by the work trees of gitDir's repository
+func gitCommonDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	commonDir := filepath.FromSlash(strings.TrimSpace(string(data)))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	return commonDir
 }
 
-// matchGitIgnorePattern checks if a path matches a gitignore pattern
-func matchGitIgnorePattern(path, pattern string) bool {
-	// Handle root pattern (starting with /)
-	if strings.HasPrefix(pattern, "/") {
-		// Remove the leading / for matching against relative paths
-		pattern = pattern[1:]
-		// For root patterns, the path must match from the root
-		return path == pattern || strings.HasPrefix(path, pattern+"/")
+// gitExcludesFile returns the file named by core.excludesFile in the user's
+// and the repository's git config, or git's default, git/ignore in the user
+// config directory
+func gitExcludesFile(gitDir string) string {
+	home, _ := os.UserHomeDir()
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" && home != "" {
+		configHome = filepath.Join(home, ".config")
 	}
 
-	// Handle directory pattern (ending with /)
-	if strings.HasSuffix(pattern, "/") {
-		// Remove the trailing / for exact directory name matching
-		dirPattern := pattern[:len(pattern)-1]
-		
-		// Match exact directory name or path starting with the directory
-		return path == dirPattern || 
-			   strings.HasPrefix(path, pattern) || 
-			   strings.HasSuffix(path, "/"+dirPattern) ||
-			   strings.Contains(path, "/"+dirPattern+"/")
+	// Later files override earlier ones, as in git
+	var configs []string
+	if configHome != "" {
+		configs = append(configs, filepath.Join(configHome, "git", "config"))
+	}
+	if home != "" {
+		configs = append(configs, filepath.Join(home, ".gitconfig"))
 	}
+	configs = append(configs, filepath.Join(gitCommonDir(gitDir), "config"))
 
-	// Handle negated pattern (patterns beginning with !)
-	if strings.HasPrefix(pattern, "!") {
-		// This is handled at a higher level in ShouldIgnore
-		return false
+	excludesFile := ""
+	if configHome != "" {
+		excludesFile = filepath.Join(configHome, "git", "ignore")
+	}
+	for _, config := range configs {
+		if value, ok := gitConfigValue(config, "core", "excludesfile"); ok {
+			excludesFile = value
+		}
+	}
+	if rest, ok := strings.CutPrefix(excludesFile, "~/"); ok && home != "" {
+		excludesFile = filepath.Join(home, filepath.FromSlash(rest))
 	}
+	return excludesFile
+}
 
-	// Handle wildcard patterns
-	if strings.Contains(pattern, "*") {
-		parts := strings.Split(pattern, "*")
-		if len(parts) == 2 {
-			// Simple case: pattern is something like "*.log" or "dir/*.js"
-			prefix, suffix := parts[0], parts[1]
-			
-			// For "*.ext" pattern
-			if prefix == "" && suffix != "" {
-				return strings.HasSuffix(path, suffix)
-			}
-			
-			// For "prefix*" pattern
-			if prefix != "" && suffix == "" {
-				return strings.HasPrefix(path, prefix)
-			}
+// readGitIgnoreFile returns the patterns of a .gitignore-style file, or
+// none if it does not exist
+func readGitIgnoreFile(name string) ([]gitIgnorePattern, error) {
+	file, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-			// For "prefix*suffix" pattern
-			if prefix != "" && suffix != "" {
-				// If the pattern starts with dir/, make sure we're matching the whole directory path
-				if strings.Contains(prefix, "/") {
-					return strings.HasPrefix(path, prefix) && strings.HasSuffix(path, suffix)
-				} else {
-					// Otherwise, match the pattern anywhere in the path components
-					filename := filepath.Base(path)
-					return strings.HasPrefix(filename, prefix) && strings.HasSuffix(filename, suffix)
-				}
-			}
-		}
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
+	return parseGitIgnore(lines), nil
+}
 
-	// Exact match
-	if strings.Contains(pattern, "/") {
-		// If pattern has a slash, it's a specific path
-		return path == pattern || strings.HasPrefix(path, pattern+"/")
-	} else {
-		// Otherwise, look for the pattern in any part of the path
-		pathParts := strings.Split(path, "/")
-		for _, part := range pathParts {
-			if part == pattern {
-				return true
-			}
-		}
+// gitIgnoreCase reports whether the repository at rootDir sets core.ignorecase,
+// which git enables when the working tree is on a case-insensitive file system
+func gitIgnoreCase(rootDir string) bool {
+	value, _ := gitConfigValue(filepath.Join(rootDir, ".git", "config"), "core", "ignorecase")
+	return strings.EqualFold(value, "true")
+}
+
+// gitConfigValue returns the last value of a key in a section of a git
+// config file, and whether it is set. Section and key names are lower case;
+// subsections and includes are not supported.
+func gitConfigValue(name, section, key string) (string, bool) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", false
 	}
+	defer file.Close()
 
-	return false
-}
\ No newline at end of file
+	current := ""
+	value, found := "", false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			current = strings.ToLower(strings.Trim(line, "[] "))
+			continue
+		}
+		if current != section {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(k), key) {
+			value, found = strings.Trim(strings.TrimSpace(v), `"`), true
+		}
+	}
+	return value, found
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
 )
 
 func TestGitIgnoreParsing(t *testing.T) {
@@ -64,7 +67,8 @@ node_modules/
 		{filepath.Join(tempDir, "src", "nested.log"), true},     // Matches *.log
 		{filepath.Join(tempDir, "dist", "app.js"), true},        // Matches dist/
 		{filepath.Join(tempDir, "build", "output.txt"), true},   // Matches build/
-		{filepath.Join(tempDir, "build", "important.txt"), false}, // Negated by !build/important.txt
+		{filepath.Join(tempDir, "build", "important.txt"), true}, // Can't be re-included from an excluded directory
+		{filepath.Join(tempDir, "debug.log", "keep.log"), true},   // Matches *.log as a directory
 		{filepath.Join(tempDir, "node_modules", "package", "index.js"), true}, // Matches node_modules/
 		{filepath.Join(tempDir, "src", "main.go"), false},       // No pattern matches
 		{filepath.Join(tempDir, "README.md"), false},            // No pattern matches
@@ -109,10 +113,10 @@ func TestGitIgnorePatternMatching(t *testing.T) {
 		{"dir/file.txt", "file.txt", true},  // This should match in our implementation
 		{"file.log", "*.log", true},
 		{"dir/file.log", "*.log", true},
-		{"dir/subdir/file.log", "dir/*.log", true}, // Our implementation matches this
+		{"dir/subdir/file.log", "dir/*.log", false}, // * doesn't match a slash
 		{"dir/file.log", "dir/*.log", true},
 		{"build/output.txt", "build/", true},
-		{"build", "build/", true}, // Directory name without trailing slash
+		{"build", "build/", false}, // A file, not a directory
 		{"docs/build/output.txt", "build/", true}, // Our implementation matches subdirectories
 		// Additional specific test cases for our implementation
 		{"src/main.go", "*.js", false},
@@ -121,6 +125,23 @@ func TestGitIgnorePatternMatching(t *testing.T) {
 		{"dist/bundle.js", "dist/", true}, // Directory pattern
 		{"src/dist/file.js", "/dist/", false}, // Root pattern shouldn't match in subdirs
 		{"README.md", "*.md", true},
+		// Double asterisks, classes and escapes
+		{"a/b/c/file.log", "a/**/file.log", true},
+		{"a/file.log", "a/**/file.log", true},
+		{"b/a/file.log", "a/**/file.log", false},
+		{"deep/down/foo/bar.go", "**/foo/bar.go", true},
+		{"foo/bar.go", "**/foo/bar.go", true},
+		{"out/x/y.js", "out/**", true},
+		{"out", "out/**", false},
+		{"x/out/y.js", "/out/**", false},
+		{"abc/d.go", "ab[a-c]/", true},
+		{"abd/d.go", "ab[!a-c]/", true},
+		{"abc/d.go", "ab[^a-c]/", false},
+		{"#notes", "\\#notes", true},
+		{"!important", "\\!important", true},
+		{"file.txt", "file.txt  ", true},
+		{"file.txt ", "file.txt\\ ", true},
+		{"file.txt", "# file.txt", false},
 	}
 
 	for _, test := range tests {
@@ -130,4 +151,172 @@ func TestGitIgnorePatternMatching(t *testing.T) {
 				test.path, test.pattern, result, test.match)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestGitIgnoreIgnoreCase(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "ignorecase-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("build/\n*.LOG\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .gitignore: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	gitConfig := "[core]\n\trepositoryformatversion = 0\n\tignorecase = true\n"
+	if err := os.WriteFile(filepath.Join(tempDir, ".git", "config"), []byte(gitConfig), 0644); err != nil {
+		t.Fatalf("Failed to write git config: %v", err)
+	}
+
+	if !gitIgnoreCase(tempDir) {
+		t.Fatalf("Expected core.ignorecase to be detected")
+	}
+
+	gitignore, err := NewGitIgnore(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to parse .gitignore: %v", err)
+	}
+
+	paths := []string{
+		filepath.Join(tempDir, "Build", "out.go"),
+		filepath.Join(tempDir, "debug.log"),
+	}
+	for _, path := range paths {
+		if gitignore.ShouldIgnore(path) {
+			t.Errorf("ShouldIgnore(%s) should be case-sensitive by default", path)
+		}
+	}
+
+	gitignore.SetIgnoreCase(true)
+	for _, path := range paths {
+		if !gitignore.ShouldIgnore(path) {
+			t.Errorf("ShouldIgnore(%s) should match case-insensitively", path)
+		}
+	}
+}
+
+func TestGitExcludes(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "gitexcludes-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	home := filepath.Join(tempDir, "home")
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	repo := filepath.Join(tempDir, "repo")
+	files := map[string]string{
+		filepath.Join(home, ".gitconfig"):               "[core]\n\texcludesFile = ~/global-ignore\n",
+		filepath.Join(home, "global-ignore"):            "secret/\n*.gen.go\n",
+		filepath.Join(home, ".config", "git", "ignore"): "*.go\n", // Not used, as core.excludesFile is set
+		filepath.Join(repo, ".git", "info", "exclude"):  "/sub/local.go\nscratch.go\n",
+		filepath.Join(repo, "sub", ".gitignore"):        "!keep.gen.go\n",
+		filepath.Join(repo, "sub", "local.go"):          "package sub\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	// The root is a subdirectory of the work tree
+	root := filepath.Join(repo, "sub")
+	gitignore, err := NewGitIgnore(root)
+	if err != nil {
+		t.Fatalf("Failed to parse .gitignore: %v", err)
+	}
+	if err := gitignore.LoadGitExcludes(); err != nil {
+		t.Fatalf("LoadGitExcludes() failed: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"local.go", true},         // /sub/local.go in info/exclude
+		{"deeper/local.go", false}, // Anchored to the top of the work tree
+		{"scratch.go", true},       // scratch.go in info/exclude
+		{"secret/key.go", true},    // secret/ in core.excludesFile
+		{"api.gen.go", true},       // *.gen.go in core.excludesFile
+		{"keep.gen.go", false},     // Re-included by .gitignore
+		{"main.go", false},
+	}
+	for _, test := range tests {
+		path := filepath.Join(root, filepath.FromSlash(test.path))
+		if result := gitignore.ShouldIgnore(path); result != test.expected {
+			t.Errorf("ShouldIgnore(%s) = %v, expected %v", test.path, result, test.expected)
+		}
+	}
+
+	// Outside a work tree there is nothing to load
+	gitignore, _ = NewGitIgnore(home)
+	if err := gitignore.LoadGitExcludes(); err != nil || gitignore.ShouldIgnore(filepath.Join(home, "api.gen.go")) {
+		t.Errorf("LoadGitExcludes() outside a work tree = %v, expected no patterns", err)
+	}
+
+	// GitIgnoreOnly leaves git's exclude files out
+	for _, only := range []bool{false, true} {
+		p := NewProcessor(root, &Options{DryRun: true, GitIgnoreOnly: only})
+		p.Process()
+		results := p.Results()
+		if len(results) != 2 || (results[1].Reason == "gitignored file") == only {
+			t.Errorf("Process() with GitIgnoreOnly %v = %+v, expected local.go to be gitignored %v", only, results, !only)
+		}
+	}
+}
+
+func TestFixBufferGitIgnore(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-buffer-gitignore-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	gitignorePath := filepath.Join(tempDir, ".gitignore")
+	if err := os.WriteFile(gitignorePath, []byte("gen/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+
+	config := models.NewConfig()
+	config.Verbose = true
+	p := NewProcessor(tempDir, &Options{DryRun: true, GitIgnoreOnly: true, Config: config})
+	if _, result := p.FixBuffer("gen/a.go", []byte("package gen\n")); result.Reason != "gitignored file" {
+		t.Errorf("FixBuffer(gen/a.go) = %s %q, expected a gitignored file", result.Action, result.Reason)
+	}
+	rules := p.bufferIgnore
+	if _, result := p.FixBuffer("src/a.go", []byte("package src\n")); result.Action != models.ActionUpdated {
+		t.Errorf("FixBuffer(src/a.go) = %s %q, expected %s", result.Action, result.Reason, models.ActionUpdated)
+	}
+	if rules == nil || p.bufferIgnore != rules {
+		t.Errorf("FixBuffer() read .gitignore again while it was unchanged")
+	}
+
+	// Verbose lines are not kept between buffers
+	if _, result := p.FixBuffer("src/b.go", []byte("\xff\xfep\x00a\x00c\x00k\x00\n")); result.Reason != reasonEncoding+"UTF-16LE" {
+		t.Errorf("FixBuffer(src/b.go) = %s %q, expected %q", result.Action, result.Reason, reasonEncoding+"UTF-16LE")
+	}
+	if len(p.output.chunks) != 0 {
+		t.Errorf("FixBuffer() kept %d chunks of output, expected none", len(p.output.chunks))
+	}
+
+	// A changed .gitignore is read again
+	if err := os.WriteFile(gitignorePath, []byte("src/\n"), 0644); err != nil {
+		t.Fatalf("Failed to update .gitignore: %v", err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(gitignorePath, later, later); err != nil {
+		t.Fatalf("Failed to touch .gitignore: %v", err)
+	}
+	if _, result := p.FixBuffer("src/a.go", []byte("package src\n")); result.Reason != "gitignored file" {
+		t.Errorf("FixBuffer(src/a.go) after .gitignore changed = %s %q, expected a gitignored file", result.Action, result.Reason)
+	}
+}
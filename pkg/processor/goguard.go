@@ -0,0 +1,37 @@
+// File: pkg/processor/goguard.go
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+)
+
+// errUnsafeGoEdit marks Go files whose header change was refused because
+// the result would no longer parse or would no longer be gofmt-formatted
+var errUnsafeGoEdit = errors.New("unsafe Go edit")
+
+// checkGoEdit verifies that a header change keeps a Go file parseable and,
+// when formatCheck is set, gofmt-formatted. Files that were already broken
+// or unformatted are not held to a standard they did not meet.
+func checkGoEdit(name string, original, updated []byte, formatCheck bool) error {
+	if _, err := parser.ParseFile(token.NewFileSet(), name, updated, parser.ParseComments); err != nil {
+		if _, origErr := parser.ParseFile(token.NewFileSet(), name, original, parser.ParseComments); origErr == nil {
+			return fmt.Errorf("%w: result does not parse: %v", errUnsafeGoEdit, err)
+		}
+		return nil
+	}
+
+	if !formatCheck {
+		return nil
+	}
+	if formatted, err := format.Source(updated); err == nil && !bytes.Equal(formatted, updated) {
+		if formattedOrig, err := format.Source(original); err == nil && bytes.Equal(formattedOrig, original) {
+			return fmt.Errorf("%w: result is not gofmt-formatted", errUnsafeGoEdit)
+		}
+	}
+	return nil
+}
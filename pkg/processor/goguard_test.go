@@ -0,0 +1,61 @@
+// File: pkg/processor/goguard_test.go
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckGoEdit(t *testing.T) {
+	tests := []struct {
+		name        string
+		original    string
+		updated     string
+		formatCheck bool
+		unsafe      bool
+	}{
+		{"header added", "package main\n", "// File: main.go\npackage main\n", true, false},
+		{"broken result", "package main\n", "File: main.go\npackage main\n", false, true},
+		{"already broken", "package main\nfunc {\n", "// File: main.go\npackage main\nfunc {\n", false, false},
+		{"unformatted result", "package main\n", "// File: main.go   \npackage main\n", true, true},
+		{"unformatted result without check", "package main\n", "// File: main.go   \npackage main\n", false, false},
+		{"already unformatted", "package  main\n", "// File: main.go   \npackage  main\n", true, false},
+	}
+
+	for _, test := range tests {
+		err := checkGoEdit("main.go", []byte(test.original), []byte(test.updated), test.formatCheck)
+		if unsafe := errors.Is(err, errUnsafeGoEdit); unsafe != test.unsafe {
+			t.Errorf("checkGoEdit(%s) = %v, expected unsafe %v", test.name, err, test.unsafe)
+		}
+	}
+}
+
+func TestGoGuardKeepsFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-goguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A script that returns a header which is not a comment
+	script := "def header(file):\n    return 'File: ' + file.path\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "rules.star"), []byte(script), 0644); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	goFile := filepath.Join(tempDir, "main.go")
+	if err := os.WriteFile(goFile, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	processor.config.Script = "rules.star"
+	if _, err := processor.processFile(goFile, "main.go"); !errors.Is(err, errUnsafeGoEdit) {
+		t.Errorf("processFile(main.go) = %v, expected %v", err, errUnsafeGoEdit)
+	}
+	content, _ := os.ReadFile(goFile)
+	if string(content) != "package main\n" {
+		t.Errorf("processFile(main.go) wrote %q, expected the file to be unchanged", content)
+	}
+}
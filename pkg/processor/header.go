@@ -0,0 +1,70 @@
+// File: pkg/processor/header.go
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// errNoCommentStyle is returned when a style has neither usable line nor block markers
+var errNoCommentStyle = errors.New("no valid comment style for file type")
+
+// renderHeader formats text as a header comment in the given style,
+// including the style's wrapper text and the trailing newline
+func renderHeader(style models.CommentStyle, text string) (string, error) {
+	var comment string
+	if style.Preferred == "line" && style.LineComment != "" {
+		comment = style.LineComment + " " + text
+	} else if style.BlockCommentStart != "" && style.BlockCommentEnd != "" {
+		comment = style.BlockCommentStart + " " + text + " " + style.BlockCommentEnd
+	} else {
+		return "", errNoCommentStyle
+	}
+	return style.HeaderPrefix + comment + "\n" + style.HeaderSuffix, nil
+}
+
+// existingHeaderLen returns the length of a header at the start of rest,
+// including its wrapper text, or 0 if rest does not start with a header
+// written with one of prefixes. A bare header without the configured
+// wrappers is also recognized.
+func existingHeaderLen(rest []byte, style models.CommentStyle, prefixes ...string) int {
+	n := 0
+	if style.HeaderPrefix != "" && bytes.HasPrefix(rest, []byte(style.HeaderPrefix)) {
+		n = len(style.HeaderPrefix)
+		if line, _ := firstLine(rest[n:]); !isHeaderLine(line, style, prefixes...) {
+			n = 0
+		}
+	}
+
+	line, lineLen := firstLine(rest[n:])
+	if !isHeaderLine(line, style, prefixes...) {
+		return 0
+	}
+	n += lineLen
+
+	if style.HeaderSuffix != "" && bytes.HasPrefix(rest[n:], []byte(style.HeaderSuffix)) {
+		n += len(style.HeaderSuffix)
+	}
+	return n
+}
+
+// isHeaderLine checks whether a line is an existing path header comment
+// written with one of prefixes
+func isHeaderLine(line string, style models.CommentStyle, prefixes ...string) bool {
+	line = strings.TrimSpace(line)
+	for _, marker := range []string{style.LineComment, style.BlockCommentStart} {
+		marker = strings.TrimSpace(marker)
+		if marker == "" || !strings.HasPrefix(line, marker) {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.Contains(line, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
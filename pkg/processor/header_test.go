@@ -0,0 +1,49 @@
+// File: pkg/processor/header_test.go
+package processor
+
+import (
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestHeaderWrappers(t *testing.T) {
+	style := models.CommentStyle{
+		LineComment:  "//",
+		Preferred:    "line",
+		HeaderPrefix: "  ",
+		HeaderSuffix: "// ----\n",
+	}
+
+	header, err := renderHeader(style, "File: a.go")
+	if err != nil {
+		t.Fatalf("renderHeader failed: %v", err)
+	}
+	if header != "  // File: a.go\n// ----\n" {
+		t.Errorf("Unexpected header: %q", header)
+	}
+
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"  // File: a.go\n// ----\nbody\n", 24},
+		{"// File: a.go\nbody\n", 14},   // Bare header written before wrappers were configured
+		{"  // File: a.go\nbody\n", 16}, // Suffix missing
+		{"  body\n", 0},
+		{"// ----\nbody\n", 0},
+	}
+
+	for _, test := range tests {
+		result := existingHeaderLen([]byte(test.content), style, "File: ")
+		if result != test.expected {
+			t.Errorf("existingHeaderLen(%q) = %d, expected %d", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestRenderHeaderWithoutStyle(t *testing.T) {
+	if _, err := renderHeader(models.CommentStyle{Preferred: "line"}, "File: a"); err == nil {
+		t.Errorf("Expected an error for a style without comment markers")
+	}
+}
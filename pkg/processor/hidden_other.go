@@ -0,0 +1,9 @@
+//go:build !windows
+
+// File: pkg/processor/hidden_other.go
+package processor
+
+// hasHiddenAttribute always reports false; only the leading-dot convention applies here
+func hasHiddenAttribute(path string) bool {
+	return false
+}
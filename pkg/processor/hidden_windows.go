@@ -0,0 +1,19 @@
+//go:build windows
+
+// File: pkg/processor/hidden_windows.go
+package processor
+
+import "syscall"
+
+// hasHiddenAttribute checks the FILE_ATTRIBUTE_HIDDEN flag set by Explorer and attrib +h
+func hasHiddenAttribute(path string) bool {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false
+	}
+	attrs, err := syscall.GetFileAttributes(pathPtr)
+	if err != nil {
+		return false
+	}
+	return attrs&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+}
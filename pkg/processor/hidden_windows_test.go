@@ -0,0 +1,39 @@
+//go:build windows
+
+// File: pkg/processor/hidden_windows_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestHiddenAttribute(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "hidden-attr-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	hiddenFile := filepath.Join(tempDir, "secret.go")
+	if err := os.WriteFile(hiddenFile, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	pathPtr, err := syscall.UTF16PtrFromString(hiddenFile)
+	if err != nil {
+		t.Fatalf("Failed to convert path: %v", err)
+	}
+	if err := syscall.SetFileAttributes(pathPtr, syscall.FILE_ATTRIBUTE_HIDDEN); err != nil {
+		t.Fatalf("Failed to set hidden attribute: %v", err)
+	}
+
+	if !isHiddenPath(hiddenFile, "secret.go") {
+		t.Errorf("Expected %s to be hidden", hiddenFile)
+	}
+	if isHiddenPath(tempDir, filepath.Base(tempDir)) {
+		t.Errorf("Expected %s not to be hidden", tempDir)
+	}
+}
@@ -0,0 +1,84 @@
+// File: pkg/processor/hooks.go
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// Report returns the statistics and per-file results of the run so far
+func (p *Processor) Report() models.Report {
+	return models.Report{
+		Root:     p.rootDir,
+		DryRun:   p.options.DryRun,
+		FailFast: p.options.FailFast,
+		Stopped:  p.stopped,
+		Stats:    p.statistics,
+		Results:  p.results,
+	}
+}
+
+// runPreRunHook runs the configured pre-run hook, if any
+func (p *Processor) runPreRunHook() error {
+	if err := p.runHook(p.config.Hooks.PreRun, nil); err != nil {
+		return fmt.Errorf("pre-run hook failed: %w", err)
+	}
+	return nil
+}
+
+// runPostRunHook runs the configured post-run hook, if any, with the report on stdin
+func (p *Processor) runPostRunHook() error {
+	if len(p.config.Hooks.PostRun) == 0 {
+		return nil
+	}
+	report, err := json.Marshal(p.Report())
+	if err != nil {
+		return err
+	}
+	if err := p.runHook(p.config.Hooks.PostRun, bytes.NewReader(report)); err != nil {
+		return fmt.Errorf("post-run hook failed: %w", err)
+	}
+	return nil
+}
+
+// runPerFileHook runs the configured per-file hook, if any, for a modified file
+func (p *Processor) runPerFileHook(relPath string) error {
+	if len(p.config.Hooks.PerFile) == 0 {
+		return nil
+	}
+	command := append(append([]string(nil), p.config.Hooks.PerFile...), relPath)
+	if err := p.runHook(command, nil); err != nil {
+		return fmt.Errorf("per-file hook failed: %w", err)
+	}
+	return nil
+}
+
+// runHook runs a hook command from the root directory. Its output is passed
+// through, and PATHFIX_ROOT and PATHFIX_DRY_RUN describe the run.
+func (p *Processor) runHook(command []string, stdin io.Reader) error {
+	if len(command) == 0 {
+		return nil
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = p.rootDir
+	cmd.Stdin = stdin
+	cmd.Stdout = p.stdout()
+	cmd.Stderr = p.stderr()
+	cmd.Env = append(os.Environ(),
+		"PATHFIX_ROOT="+p.rootDir,
+		"PATHFIX_DRY_RUN="+strconv.FormatBool(p.options.DryRun),
+	)
+	if p.options.Verbose {
+		p.printf("Running hook: %v\n", command)
+	}
+	p.flushOutput()
+	return cmd.Run()
+}
@@ -0,0 +1,112 @@
+// File: pkg/processor/hooks_test.go
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// TestHookHelperProcess is not a real test: it is run as a hook by
+// TestHooks and appends what it was given to PATHFIX_HOOK_LOG
+func TestHookHelperProcess(t *testing.T) {
+	logPath := os.Getenv("PATHFIX_HOOK_LOG")
+	if logPath == "" {
+		return
+	}
+
+	// Arguments after "--" are the hook's own
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	if len(args) > 0 && args[0] == os.Getenv("PATHFIX_HOOK_FAIL") {
+		os.Exit(1)
+	}
+
+	line := strings.Join(args, " ")
+	if len(args) > 0 && args[0] == "post" {
+		var report models.Report
+		if err := json.NewDecoder(os.Stdin).Decode(&report); err != nil {
+			os.Exit(2)
+		}
+		line += fmt.Sprintf(" updated=%d results=%d", report.Stats.Updated, len(report.Results))
+	}
+	line += " dry-run=" + os.Getenv("PATHFIX_DRY_RUN")
+
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		os.Exit(2)
+	}
+	fmt.Fprintln(file, line)
+	file.Close()
+	os.Exit(0)
+}
+
+func TestHooks(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-hooks-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	helper := func(name string) []string {
+		return []string{os.Args[0], "-test.run=^TestHookHelperProcess$", "--", name}
+	}
+
+	tests := []struct {
+		name     string
+		dryRun   bool
+		fail     string
+		expected []string
+		failed   bool
+	}{
+		{"run", false, "", []string{"pre dry-run=false", "file main.go dry-run=false", "post updated=1 results=2 dry-run=false"}, false},
+		{"dry run", true, "", []string{"pre dry-run=true", "post updated=1 results=2 dry-run=true"}, false},
+		{"failed pre-run hook", false, "pre", nil, true},
+		{"failed post-run hook", false, "post", []string{"pre dry-run=false", "file main.go dry-run=false"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logPath := filepath.Join(tempDir, "hooks.log")
+			os.Remove(logPath)
+			t.Setenv("PATHFIX_HOOK_LOG", logPath)
+			t.Setenv("PATHFIX_HOOK_FAIL", test.fail)
+
+			root := filepath.Join(tempDir, "root")
+			os.RemoveAll(root)
+			os.MkdirAll(root, 0755)
+			os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644)
+			os.WriteFile(filepath.Join(root, "notes.xyz"), []byte("notes\n"), 0644)
+
+			processor := NewProcessor(root, &Options{DryRun: test.dryRun})
+			processor.config.Hooks = models.Hooks{
+				PreRun:  helper("pre"),
+				PostRun: helper("post"),
+				PerFile: helper("file"),
+			}
+			_, err := processor.Process()
+			if (err != nil) != test.failed {
+				t.Errorf("Process() error = %v, expected failure %v", err, test.failed)
+			}
+
+			data, _ := os.ReadFile(logPath)
+			var lines []string
+			if log := strings.TrimSpace(string(data)); log != "" {
+				lines = strings.Split(log, "\n")
+			}
+			if strings.Join(lines, "|") != strings.Join(test.expected, "|") {
+				t.Errorf("hooks ran as %q, expected %q", lines, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// File: pkg/processor/languages.go
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// reasonExcludedLanguage is why files outside Languages, or in
+// ExcludeLanguages, are skipped
+const reasonExcludedLanguage = "excluded language"
+
+// languages maps the names accepted by Languages and ExcludeLanguages to the
+// extensions of the built-in file types they cover, and the lowercased
+// FileNames patterns of those known by name
+var languages = map[string][]string{
+	"ada":           {".ads", ".adb"},
+	"apache":        {".htaccess"},
+	"asciidoc":      {".adoc"},
+	"blade":         {".blade.php"},
+	"assembly":      {".asm", ".nasm", ".s"},
+	"batch":         {".bat", ".cmd"},
+	"c":             {".c", ".h"},
+	"caddy":         {"caddyfile"},
+	"clojure":       {".clj", ".cljs", ".cljc", ".edn"},
+	"cmake":         {"cmakelists.txt"},
+	"cobol":         {".cbl", ".cob", ".cpy"},
+	"conf":          {".conf"},
+	"cpp":           {".cpp", ".hpp"},
+	"csharp":        {".cs"},
+	"css":           {".css"},
+	"dart":          {".dart"},
+	"desktop":       {".desktop"},
+	"docker":        {"dockerfile", "dockerfile.*", "*.dockerfile", "containerfile", "containerfile.*"},
+	"earthly":       {"earthfile"},
+	"elixir":        {".ex", ".exs"},
+	"erb":           {".erb"},
+	"erlang":        {".erl", ".hrl"},
+	"fortran":       {".f", ".for", ".f77", ".f90", ".f95", ".f03", ".f08"},
+	"fsharp":        {".fs", ".fsi", ".fsx"},
+	"gitconfig":     {".gitconfig", ".gitmodules"},
+	"go":            {".go"},
+	"gotemplate":    {".gotmpl", ".tmpl"},
+	"graphql":       {".graphql", ".gql"},
+	"groovy":        {".groovy", ".gradle", "jenkinsfile"},
+	"handlebars":    {".hbs"},
+	"hcl":           {".tf", ".tfvars", ".hcl"},
+	"haskell":       {".hs"},
+	"html":          {".html"},
+	"ini":           {".ini"},
+	"inno":          {".iss"},
+	"java":          {".java"},
+	"javascript":    {".js", ".jsx"},
+	"jinja":         {".j2", ".jinja", ".jinja2"},
+	"json":          {".json", ".jsonc", ".json5"},
+	"julia":         {".jl"},
+	"just":          {"justfile"},
+	"kotlin":        {".kt", ".kts"},
+	"latex":         {".tex", ".sty", ".ltx"},
+	"less":          {".less"},
+	"lisp":          {".lisp", ".lsp", ".scm"},
+	"lua":           {".lua"},
+	"make":          {"makefile", "gnumakefile"},
+	"markdown":      {".md", ".markdown", ".mdx"},
+	"matlab":        {".m"},
+	"nsis":          {".nsi", ".nsh"},
+	"ocaml":         {".ml", ".mli"},
+	"pascal":        {".pas", ".pp", ".dpr"},
+	"perl":          {".pl"},
+	"php":           {".php", ".blade.php"},
+	"powershell":    {".ps1", ".psm1", ".psd1"},
+	"protobuf":      {".proto"},
+	"python":        {".py", "snakefile", "pipfile"},
+	"r":             {".r"},
+	"rc":            {".rc", ".rc2"},
+	"registry":      {".reg"},
+	"rpg":           {".rpg", ".rpgle", ".sqlrpgle"},
+	"rst":           {".rst"},
+	"ruby":          {".rb", "vagrantfile", "gemfile", "rakefile", "podfile", "brewfile", "fastfile"},
+	"rust":          {".rs"},
+	"sass":          {".scss", ".sass"},
+	"scala":         {".scala", ".sbt"},
+	"shell":         {".sh", ".bash", ".zsh", ".ksh", ".csh", ".fish"},
+	"starlark":      {"build", "build.bazel", "workspace", "workspace.bazel", "module.bazel", "tiltfile"},
+	"stylus":        {".styl"},
+	"swift":         {".swift"},
+	"systemd":       {".service", ".socket", ".timer", ".target", ".mount", ".path"},
+	"systemverilog": {".sv", ".svh"},
+	"terraform":     {".tf", ".tfvars"},
+	"toml":          {".toml"},
+	"twig":          {".twig"},
+	"typescript":    {".ts", ".tsx", ".d.ts"},
+	"vb":            {".vb"},
+	"vba":           {".bas", ".cls", ".frm"},
+	"vbscript":      {".vbs"},
+	"verilog":       {".v", ".vh"},
+	"vhdl":          {".vhd", ".vhdl"},
+	"xml":           {".xml"},
+	"yaml":          {".yaml", ".yml"},
+}
+
+// LanguageNames returns the language names accepted by Languages and
+// ExcludeLanguages, sorted
+func LanguageNames() []string {
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// languageExtensions returns the extensions selected by a list of language
+// names and extensions (such as ".vue", for types without a name), or an
+// error naming the first unknown language
+func languageExtensions(field string, names []string) (map[string]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	extensions := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.HasPrefix(name, ".") {
+			extensions[name] = true
+			continue
+		}
+		exts, ok := languages[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: unknown language %q (expected one of %s, or an extension such as .go)",
+				models.ErrInvalidConfig, field, name, strings.Join(LanguageNames(), ", "))
+		}
+		for _, ext := range exts {
+			extensions[ext] = true
+		}
+	}
+	return extensions, nil
+}
+
+// languageSelected reports whether files with the extensions exts are in
+// the languages to process, through any of them, and not in those excluded
+func (p *Processor) languageSelected(exts ...string) bool {
+	selected := p.languages == nil
+	for _, ext := range exts {
+		ext = strings.ToLower(ext)
+		if p.excluded[ext] {
+			return false
+		}
+		if p.languages[ext] {
+			selected = true
+		}
+	}
+	return selected
+}
+
+// languageKeys returns what Languages and ExcludeLanguages select relPath
+// by: the lowercased FileNames pattern it matches, or else its extensions,
+// so that ".d.ts" files are selected by ".ts" and can be excluded alone
+func (p *Processor) languageKeys(relPath string) []string {
+	if pattern, _, ok := p.fileNameType(relPath); ok {
+		return []string{strings.ToLower(pattern)}
+	}
+	return fileExtensions(relPath)
+}
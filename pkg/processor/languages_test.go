@@ -0,0 +1,123 @@
+// File: pkg/processor/languages_test.go
+package processor
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestLanguagesCoverFileTypes(t *testing.T) {
+	code, docs := DefaultFileTypes()
+	fileNames := make(map[string]bool)
+	for pattern := range DefaultFileNames() {
+		fileNames[strings.ToLower(pattern)] = true
+	}
+	for name, exts := range languages {
+		for _, ext := range exts {
+			_, isCode := code[ext]
+			_, isDoc := docs[ext]
+			if !isCode && !isDoc && !fileNames[ext] && ext != ".json" {
+				t.Errorf("language %s lists %s, which is not a built-in file type", name, ext)
+			}
+		}
+	}
+}
+
+func TestLanguageSelected(t *testing.T) {
+	tests := []struct {
+		include, exclude []string
+		ext              string
+		expected         bool
+	}{
+		{nil, nil, ".go", true},
+		{[]string{"go", "python"}, nil, ".go", true},
+		{[]string{"go", "python"}, nil, ".PY", true},
+		{[]string{"go", "python"}, nil, ".rs", false},
+		{[]string{"shell"}, nil, ".zsh", true},
+		{[]string{".proto"}, nil, ".proto", true},
+		{nil, []string{"yaml"}, ".yml", false},
+		{nil, []string{"yaml"}, ".go", true},
+		{[]string{"c", "cpp"}, []string{"c"}, ".h", false},
+		{[]string{"c", "cpp"}, []string{"c"}, ".hpp", true},
+		{[]string{"docker"}, nil, "dockerfile.*", true},
+		{[]string{"go"}, nil, "makefile", false},
+		{nil, []string{"ruby"}, "gemfile", false},
+	}
+
+	for _, test := range tests {
+		languages, err := languageExtensions("Languages", test.include)
+		if err != nil {
+			t.Fatalf("languageExtensions(%v) failed: %v", test.include, err)
+		}
+		excluded, err := languageExtensions("ExcludeLanguages", test.exclude)
+		if err != nil {
+			t.Fatalf("languageExtensions(%v) failed: %v", test.exclude, err)
+		}
+		p := &Processor{languages: languages, excluded: excluded}
+		if result := p.languageSelected(test.ext); result != test.expected {
+			t.Errorf("languageSelected(%s) with %v, excluding %v = %v, expected %v", test.ext, test.include, test.exclude, result, test.expected)
+		}
+	}
+
+	if _, err := languageExtensions("Languages", []string{"go", "golang"}); !errors.Is(err, models.ErrInvalidConfig) {
+		t.Errorf("languageExtensions(golang) = %v, expected an invalid config error", err)
+	}
+}
+
+func TestLanguageProcessing(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "languages-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"main.go":     "package main\n",
+		"tool.py":     "x = 1\n",
+		"ci.yml":      "a: 1\n",
+		"lib/util.sh": "echo\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	config := models.NewConfig()
+	config.Languages = []string{"go", "python", "shell"}
+	options := &Options{DryRun: true, Config: config, ExcludeLanguages: []string{"shell"}}
+	p := NewProcessor(tempDir, options)
+	if p.ConfigError() != nil {
+		t.Fatalf("NewProcessor failed: %v", p.ConfigError())
+	}
+	stats, err := p.Process()
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.Updated != 2 {
+		t.Errorf("Process() updated %d files, expected 2", stats.Updated)
+	}
+	for _, result := range p.Results() {
+		excluded := result.Path == "ci.yml" || result.Path == "lib/util.sh"
+		if excluded != (result.Reason == reasonExcludedLanguage) {
+			t.Errorf("Process(%s) = %s %q, expected it excluded: %v", result.Path, result.Action, result.Reason, excluded)
+		}
+	}
+
+	// Unknown names in the config are rejected
+	config.Languages = []string{"golang"}
+	p = NewProcessor(tempDir, &Options{Config: config, Stderr: io.Discard})
+	if !errors.Is(p.ConfigError(), models.ErrInvalidConfig) {
+		t.Errorf("NewProcessor with Languages [golang] = %v, expected an invalid config error", p.ConfigError())
+	}
+}
@@ -0,0 +1,70 @@
+// File: pkg/processor/limits.go
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTooManyChanges is returned when a run would modify more files than the
+// change limits allow and the change was not confirmed
+var ErrTooManyChanges = errors.New("too many files would change")
+
+// changeLimits returns the largest number and percentage of files a run
+// may modify without confirmation; zero means no limit
+func (p *Processor) changeLimits() (int, float64) {
+	maxFiles, maxPercent := p.config.MaxChangedFiles, p.config.MaxChangedPercent
+	if p.options.MaxChangedFiles != 0 {
+		maxFiles = p.options.MaxChangedFiles
+	}
+	if p.options.MaxChangedPercent != 0 {
+		maxPercent = p.options.MaxChangedPercent
+	}
+	return maxFiles, maxPercent
+}
+
+// checkChangeLimits does a dry run first when a run that writes files is
+// subject to change limits. Exceeding them needs the Confirm callback's
+// approval; without one the run is refused before anything is written.
+func (p *Processor) checkChangeLimits(ctx context.Context) error {
+	maxFiles, maxPercent := p.changeLimits()
+	if p.options.DryRun || p.options.ListOnly || (maxFiles <= 0 && maxPercent <= 0) {
+		return nil
+	}
+
+	// The plan shares the configuration but not the callbacks, log or hooks
+	options := p.quietOptions()
+	options.DryRun = true
+	plan := p.fork(&options)
+	plan.planning = true
+	stats, err := plan.process(ctx)
+	var fileErrors FileErrors
+	if err != nil && !errors.As(err, &fileErrors) {
+		return err
+	}
+
+	percent := 0.0
+	if stats.Processed > 0 {
+		percent = 100 * float64(stats.Updated) / float64(stats.Processed)
+	}
+	if (maxFiles <= 0 || stats.Updated <= maxFiles) && (maxPercent <= 0 || percent <= maxPercent) {
+		return nil
+	}
+	if p.options.Confirm != nil && p.options.Confirm(stats.Updated, stats.Processed) {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d files (%.1f%%) exceed the limit of %s", ErrTooManyChanges,
+		stats.Updated, stats.Processed, percent, describeChangeLimits(maxFiles, maxPercent))
+}
+
+// describeChangeLimits formats the limits for an error message
+func describeChangeLimits(maxFiles int, maxPercent float64) string {
+	switch {
+	case maxFiles > 0 && maxPercent > 0:
+		return fmt.Sprintf("%d files or %g%%", maxFiles, maxPercent)
+	case maxFiles > 0:
+		return fmt.Sprintf("%d files", maxFiles)
+	}
+	return fmt.Sprintf("%g%%", maxPercent)
+}
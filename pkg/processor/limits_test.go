@@ -0,0 +1,63 @@
+// File: pkg/processor/limits_test.go
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChangeLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxFiles   int
+		maxPercent float64
+		confirm    func(changed, total int) bool
+		refused    bool
+	}{
+		{name: "no limit"},
+		{name: "under the file limit", maxFiles: 3},
+		{name: "over the file limit", maxFiles: 2, refused: true},
+		{name: "under the percentage", maxPercent: 75},
+		{name: "over the percentage", maxPercent: 50, refused: true},
+		{name: "confirmed", maxFiles: 1, confirm: func(changed, total int) bool { return changed == 3 && total == 4 }},
+		{name: "declined", maxFiles: 1, confirm: func(changed, total int) bool { return false }, refused: true},
+	}
+
+	for _, test := range tests {
+		tempDir, err := os.MkdirTemp("", "pathfix-limits-test")
+		if err != nil {
+			t.Fatalf("Failed to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		// Three of the four files need a header
+		for i := 1; i <= 3; i++ {
+			if err := os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("f%d.go", i)), []byte("package x\n"), 0644); err != nil {
+				t.Fatalf("Failed to create file: %v", err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, "done.go"), []byte("// File: done.go\npackage x\n"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+
+		processor := NewProcessor(tempDir, &Options{MaxChangedFiles: test.maxFiles, MaxChangedPercent: test.maxPercent, Confirm: test.confirm})
+		stats, err := processor.Process()
+		if test.refused {
+			if !errors.Is(err, ErrTooManyChanges) || stats.Updated != 0 {
+				t.Errorf("Process(%s) = %d updated, %v, expected ErrTooManyChanges", test.name, stats.Updated, err)
+			}
+			content, _ := os.ReadFile(filepath.Join(tempDir, "f1.go"))
+			if strings.Contains(string(content), "File:") {
+				t.Errorf("Process(%s) modified f1.go after refusing", test.name)
+			}
+			continue
+		}
+		if err != nil || stats.Updated != 3 {
+			t.Errorf("Process(%s) = %d updated, %v, expected 3", test.name, stats.Updated, err)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+// File: pkg/processor/link_other.go
+package processor
+
+import "io/fs"
+
+// linkKind reports "symlink" for symbolic links and "" for anything else
+func linkKind(path string, d fs.DirEntry) string {
+	if d.Type()&fs.ModeSymlink != 0 {
+		return "symlink"
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+// File: pkg/processor/link_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSymlinkPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires extra privileges on Windows")
+	}
+
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "symlink-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Shared code lives outside the root and is linked in
+	shared := filepath.Join(tempDir, "shared")
+	root := filepath.Join(tempDir, "root")
+	for _, dir := range []string{shared, filepath.Join(root, "src")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	sharedFile := filepath.Join(shared, "util.go")
+	if err := os.WriteFile(sharedFile, []byte("package util\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Symlink(shared, filepath.Join(root, "src", "shared")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	// A link back to the root must not cause an endless walk
+	if err := os.Symlink(root, filepath.Join(root, "src", "loop")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	// Links are skipped by default
+	stats, err := NewProcessor(root, &Options{}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Updated != 0 {
+		t.Errorf("Expected no updates without following links, got: %d", stats.Updated)
+	}
+
+	// Following links processes the linked tree exactly once
+	stats, err = NewProcessor(root, &Options{FollowSymlinks: true}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Updated != 1 {
+		t.Errorf("Expected 1 update when following links, got: %d", stats.Updated)
+	}
+
+	content, err := os.ReadFile(sharedFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "// File: src/shared/util.go\n") {
+		t.Errorf("Unexpected content: %q", string(content))
+	}
+}
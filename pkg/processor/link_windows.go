@@ -0,0 +1,34 @@
+//go:build windows
+
+// File: pkg/processor/link_windows.go
+package processor
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// linkKind reports "symlink" for symbolic links, "junction" for directory
+// junctions and other directory reparse points, and "" for anything else.
+// File reparse points (e.g. deduplicated files) are ordinary files.
+func linkKind(path string, d fs.DirEntry) string {
+	if d.Type()&fs.ModeSymlink != 0 {
+		return "symlink"
+	}
+	if !d.IsDir() && d.Type()&fs.ModeIrregular == 0 {
+		return ""
+	}
+
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return ""
+	}
+	attrs, err := syscall.GetFileAttributes(pathPtr)
+	if err != nil {
+		return ""
+	}
+	if attrs&syscall.FILE_ATTRIBUTE_REPARSE_POINT != 0 && attrs&syscall.FILE_ATTRIBUTE_DIRECTORY != 0 {
+		return "junction"
+	}
+	return ""
+}
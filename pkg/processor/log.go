@@ -0,0 +1,64 @@
+// File: pkg/processor/log.go
+package processor
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// logEvent writes a line to the structured log, if one is configured. Each
+// entry is written with a single call so that concurrent runs can share a
+// log. Write errors are ignored; the log must not fail the run.
+func (p *Processor) logEvent(entry models.LogEntry) {
+	if p.options.Log == nil {
+		return
+	}
+	entry.Time = time.Now().UTC()
+	entry.Root = p.rootDir
+	entry.DryRun = p.options.DryRun
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	p.options.Log.Write(append(data, '\n'))
+}
+
+// logRun logs the start of a run and returns a function that logs its end
+// and records it in the history
+func (p *Processor) logRun() func(error) {
+	start := time.Now()
+	p.logEvent(models.LogEntry{Event: models.LogStart})
+	return func(err error) {
+		entry := models.LogEntry{Event: models.LogFinish, Stats: &p.statistics}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		p.logEvent(entry)
+		p.recordHistory(start, err)
+	}
+}
+
+// recordHistory appends a run to the state directory's history when enabled.
+// Like the log, the history must not fail the run, so errors are only
+// reported in verbose mode.
+func (p *Processor) recordHistory(start time.Time, err error) {
+	if !p.options.History {
+		return
+	}
+	entry := models.HistoryEntry{
+		Start:    start.UTC(),
+		Duration: time.Since(start).Seconds(),
+		Root:     p.rootDir,
+		DryRun:   p.options.DryRun,
+		Stopped:  p.stopped,
+		Stats:    p.statistics,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := p.State().AppendHistory(entry); err != nil && p.options.Verbose {
+		p.errorf("Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,153 @@
+// File: pkg/processor/log_test.go
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestLog(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-log-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for name, content := range map[string]string{"main.go": "package main\n", "notes.txt": "notes\n"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	// The log is written whether or not the run is verbose
+	var log bytes.Buffer
+	processor := NewProcessor(tempDir, &Options{DryRun: true, Log: &log})
+	if _, err := processor.Process(); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	var events []string
+	for _, line := range lines {
+		var entry models.LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		if entry.Root != tempDir || !entry.DryRun {
+			t.Errorf("log entry %q does not describe the run", line)
+		}
+		event := entry.Event
+		if entry.File != nil {
+			event += " " + entry.File.Path + " " + entry.File.Action
+		}
+		events = append(events, event)
+	}
+
+	expected := []string{"start", "file main.go updated", "file notes.txt skipped", "finish"}
+	if strings.Join(events, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("log events = %v, expected %v", events, expected)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-history-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	// The second run must not process the state directory written by the first
+	for i := 0; i < 2; i++ {
+		processor := NewProcessor(tempDir, &Options{IncludeHidden: true, History: true})
+		if _, err := processor.Process(); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+
+	entries, err := NewProcessor(tempDir, &Options{}).State().History()
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("History() has %d runs, expected 2", len(entries))
+	}
+	if entries[0].Stats.Updated != 1 || entries[1].Stats.Processed != 1 {
+		t.Errorf("History() = %v, expected an update then a run over main.go alone", entries)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-backup-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"main.go":     "package main\n",
+		"done.go":     "// File: done.go\npackage main\n",
+		"sub/tool.py": "print(1)\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	// Dry runs and runs without Backup save nothing
+	for _, options := range []*Options{{DryRun: true, Backup: true}, {DryRun: true}} {
+		if _, err := NewProcessor(tempDir, options).Process(); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+	dir := NewProcessor(tempDir, &Options{}).State()
+	if backup, err := dir.LatestBackup(); err != nil || backup != nil {
+		t.Fatalf("LatestBackup() after dry runs = %v, %v, expected none", backup, err)
+	}
+
+	if _, err := NewProcessor(tempDir, &Options{Backup: true, Workers: 2}).Process(); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	backup, err := dir.LatestBackup()
+	if err != nil || backup == nil {
+		t.Fatalf("LatestBackup() = %v, %v, expected the run's backup", backup, err)
+	}
+	saved, err := backup.Files()
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	var names []string
+	for _, file := range saved {
+		names = append(names, file.Path)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "main.go,sub/tool.py" {
+		t.Errorf("backed up files = %v, expected the two modified files", names)
+	}
+
+	restored, conflicts, err := backup.Restore(tempDir, false)
+	if err != nil || len(restored) != 2 || len(conflicts) != 0 {
+		t.Fatalf("Restore() = %v, %v, %v, expected both files restored", restored, conflicts, err)
+	}
+	for name, expected := range files {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(name)))
+		if err != nil || string(content) != expected {
+			t.Errorf("%s after Restore() = %q, %v, expected %q", name, content, err, expected)
+		}
+	}
+}
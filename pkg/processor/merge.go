@@ -0,0 +1,127 @@
+// File: pkg/processor/merge.go
+package processor
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// headerBlockLen returns the length of a block comment header spanning
+// several lines at the start of rest, through the end of the line holding
+// its end marker, or 0 if rest does not start with one. The block is a
+// header if one of its lines is a path field written with one of prefixes,
+// e.g.
+//
+//	/*
+//	 * File: src/main.c
+//	 * Author: Jane Doe
+//	 */
+func headerBlockLen(rest []byte, style models.CommentStyle, prefixes ...string) int {
+	start := strings.TrimSpace(style.BlockCommentStart)
+	end := strings.TrimSpace(style.BlockCommentEnd)
+	if start == "" || end == "" || start == end {
+		// Python-style ''' blocks are string literals, not comments
+		return 0
+	}
+
+	trimmed := bytes.TrimLeft(rest, " \t")
+	if !bytes.HasPrefix(trimmed, []byte(start)) {
+		return 0
+	}
+	bodyStart := len(rest) - len(trimmed) + len(start)
+	bodyEnd := bytes.Index(rest[bodyStart:], []byte(end))
+	if bodyEnd < 0 {
+		return 0
+	}
+	bodyEnd += bodyStart + len(end)
+
+	// Single-line headers are replaced whole, and code after the end
+	// marker is not part of the header
+	line, n := firstLine(rest[bodyEnd:])
+	if !bytes.Contains(rest[:bodyEnd], []byte("\n")) || strings.TrimSpace(line) != "" {
+		return 0
+	}
+	if _, ok := fieldSpan(string(rest[:bodyEnd]), style, prefixes...); !ok {
+		return 0
+	}
+	return bodyEnd + n
+}
+
+// mergeHeader returns the multi-line header block with its path field
+// replaced by that of rendered, the header pathfix would write, keeping
+// every other line as it is. If rendered has no path field, for example
+// because a plugin rewrote it, rendered replaces the block.
+func mergeHeader(block, rendered string, style models.CommentStyle, prefixes ...string) string {
+	from, ok := fieldSpan(rendered, style, prefixes...)
+	if !ok {
+		return rendered
+	}
+	to, _ := fieldSpan(block, style, prefixes...)
+	return block[:to[0]] + rendered[from[0]:from[1]] + block[to[1]:]
+}
+
+// fieldSpan returns the start and end offsets of the path field in a header
+// comment: the text from one of the comment prefixes to the end of its line,
+// less a block end marker and the spaces before it. The field must be the
+// first text on its line after the comment markers and any " * " decoration.
+func fieldSpan(text string, style models.CommentStyle, prefixes ...string) ([2]int, bool) {
+	start := strings.TrimSpace(style.BlockCommentStart)
+	end := strings.TrimSpace(style.BlockCommentEnd)
+	line := strings.TrimSpace(style.LineComment)
+
+	offset := 0
+	for offset < len(text) {
+		current, lineLen := firstLine([]byte(text[offset:]))
+		current = strings.TrimSuffix(current, "\r")
+
+		// Strip the comment markers and decoration before the field
+		rest := strings.TrimLeft(current, " \t")
+		for _, marker := range []string{start, line, "*"} {
+			if marker != "" && strings.HasPrefix(rest, marker) {
+				rest = strings.TrimLeft(rest[len(marker):], " \t")
+			}
+		}
+		if hasField(rest, prefixes) {
+			from := len(current) - len(rest)
+			to := len(current)
+			if end != "" {
+				if i := strings.LastIndex(current, end); i >= from {
+					to = i
+				}
+			}
+			to = from + len(strings.TrimRight(current[from:to], " \t"))
+			return [2]int{offset + from, offset + to}, true
+		}
+		offset += lineLen
+	}
+	return [2]int{}, false
+}
+
+// headerField returns the path a header comment names, the text of its path
+// field after the prefix, or "" if it has none
+func headerField(text string, style models.CommentStyle, prefixes ...string) string {
+	span, ok := fieldSpan(text, style, prefixes...)
+	if !ok {
+		return ""
+	}
+	field := text[span[0]:span[1]]
+	for _, prefix := range prefixes {
+		if name := strings.TrimSpace(prefix); name != "" && strings.HasPrefix(field, name) {
+			return strings.TrimSpace(field[len(name):])
+		}
+	}
+	return ""
+}
+
+// hasField reports whether text starts with one of prefixes, ignoring their
+// surrounding spaces
+func hasField(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if field := strings.TrimSpace(prefix); field != "" && strings.HasPrefix(text, field) {
+			return true
+		}
+	}
+	return false
+}
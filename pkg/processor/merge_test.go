@@ -0,0 +1,186 @@
+// File: pkg/processor/merge_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestMergeHeader(t *testing.T) {
+	cStyle := models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"}
+	htmlStyle := models.CommentStyle{BlockCommentStart: "<!--", BlockCommentEnd: "-->", Preferred: "block"}
+	pyStyle := models.CommentStyle{LineComment: "#", BlockCommentStart: "'''", BlockCommentEnd: "'''", Preferred: "line"}
+
+	tests := []struct {
+		content  string
+		style    models.CommentStyle
+		rendered string
+		expected string
+	}{
+		{"/* File: old.c\n * Author: Jane\n */\nint x;\n", cStyle, "// File: a.c\n", "/* File: a.c\n * Author: Jane\n */\n"},
+		{"/*\n * Author: Jane\n * File: old.c */\nint x;\n", cStyle, "// File: a.c\n", "/*\n * Author: Jane\n * File: a.c */\n"},
+		{"/*\r\n * File: old.c\r\n */\r\nint x;\r\n", cStyle, "// File: a.c\n", "/*\r\n * File: a.c\r\n */\r\n"},
+		{"<!--\n  File: old.html\n  Owner: web\n-->\n<p>\n", htmlStyle, "<!-- File: a.html -->\n", "<!--\n  File: a.html\n  Owner: web\n-->\n"},
+		{"/* File: old.c\n * Author: Jane\n */\nint x;\n", cStyle, "// Generated\n", "// Generated\n"},
+	}
+
+	for _, test := range tests {
+		n := headerBlockLen([]byte(test.content), test.style, "File: ")
+		if n == 0 {
+			t.Errorf("headerBlockLen(%q) = 0, expected a header block", test.content)
+			continue
+		}
+		result := mergeHeader(test.content[:n], test.rendered, test.style, "File: ")
+		if result != test.expected {
+			t.Errorf("mergeHeader(%q) = %q, expected %q", test.content, result, test.expected)
+		}
+	}
+
+	// Not header blocks
+	for _, test := range []struct {
+		content string
+		style   models.CommentStyle
+	}{
+		{"/* File: a.c */\nint x;\n", cStyle},
+		{"/*\n * Copyright Acme\n */\nint x;\n", cStyle},
+		{"/*\n * File: a.c\n */ int x;\n", cStyle},
+		{"/*\n * File: a.c\n", cStyle},
+		{"// File: a.c\n// Author: Jane\n", cStyle},
+		{"'''\nFile: a.py\n'''\n", pyStyle},
+	} {
+		if n := headerBlockLen([]byte(test.content), test.style, "File: "); n != 0 {
+			t.Errorf("headerBlockLen(%q) = %d, expected 0", test.content, n)
+		}
+	}
+}
+
+func TestMergeHeaderProcessing(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "merge-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"moved.c": "/*\n * File: old/moved.c\n * Author: Jane Doe\n * Description: Parses things\n */\nint x;\n",
+		"ok.go":   "// File: ok.go\n// Author: Jane Doe\n\npackage a\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	stats, err := NewProcessor(tempDir, &Options{}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Updated != 1 || stats.Stale != 1 {
+		t.Errorf("Expected 1 stale header updated, got: %d updated, %d stale", stats.Updated, stats.Stale)
+	}
+
+	expected := map[string]string{
+		"moved.c": "/*\n * File: moved.c\n * Author: Jane Doe\n * Description: Parses things\n */\nint x;\n",
+		"ok.go":   files["ok.go"],
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+}
+
+func TestHeaderField(t *testing.T) {
+	cStyle := models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"}
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"// File: src/a.c\n", "src/a.c"},
+		{"/* File: src/a.c */\n", "src/a.c"},
+		{"//File:  src/a.c\r\n", "src/a.c"},
+		{"/*\n * Author: Jane\n * File: lib/a.c\n */\n", "lib/a.c"},
+		{"// Generated\n", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if result := headerField(test.header, cStyle, "File: "); result != test.expected {
+			t.Errorf("headerField(%q) = %q, expected %q", test.header, result, test.expected)
+		}
+	}
+}
+
+func TestMovedOnly(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-moved-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Only the headers naming another path change
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		oldPath  string
+	}{
+		{"src/moved.go", "// File: lib/moved.go\npackage src\n", "// File: src/moved.go\npackage src\n", "lib/moved.go"},
+		{"src/block.c", "/*\n * File: block.c\n * Author: Jane\n */\nint x;\n", "/*\n * File: src/block.c\n * Author: Jane\n */\nint x;\n", "block.c"},
+		{"src/current.go", "// File: src/current.go\npackage src\n", "// File: src/current.go\npackage src\n", ""},
+		{"src/spacing.go", "//File:   src/spacing.go\npackage src\n", "//File:   src/spacing.go\npackage src\n", ""},
+		{"src/missing.go", "package src\n", "package src\n", ""},
+	}
+	for _, test := range tests {
+		path := filepath.Join(tempDir, filepath.FromSlash(test.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", test.name, err)
+		}
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", test.name, err)
+		}
+	}
+
+	p := NewProcessor(tempDir, &Options{MovedOnly: true})
+	stats, err := p.Process()
+	if err != nil {
+		t.Fatalf("Process() failed: %v", err)
+	}
+	if stats.Updated != 2 || stats.Stale != 2 {
+		t.Errorf("Process() = %+v, expected 2 stale headers updated", stats)
+	}
+	results := make(map[string]models.FileResult)
+	for _, result := range p.Results() {
+		results[result.Path] = result
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(test.name)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("%s = %q, expected %q", test.name, content, test.expected)
+		}
+		if result := results[test.name]; result.OldPath != test.oldPath {
+			t.Errorf("%s recorded old path %q, expected %q", test.name, result.OldPath, test.oldPath)
+		}
+	}
+	if result := results["src/missing.go"]; result.Action != models.ActionSkipped || result.Reason != reasonNoHeader {
+		t.Errorf("src/missing.go was %s (%s), expected skipped for having no header", result.Action, result.Reason)
+	}
+
+	// FixBuffer reports the old path too
+	_, result := p.FixBuffer("src/again.go", []byte("// File: src/moved.go\npackage src\n"))
+	if result.Action != models.ActionUpdated || result.OldPath != "src/moved.go" {
+		t.Errorf("FixBuffer() = %+v, expected an update from src/moved.go", result)
+	}
+}
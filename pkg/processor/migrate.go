@@ -0,0 +1,151 @@
+// File: pkg/processor/migrate.go
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// ConfigVersion is the current config file schema version. Files without a
+// Version key are version 0.
+const ConfigVersion = models.ConfigVersion
+
+// configMigration upgrades a decoded config from the previous version in
+// place and describes each change it made
+type configMigration func(config map[string]interface{}) []string
+
+// configMigrations[i] upgrades a config from version i to i+1
+var configMigrations = []configMigration{
+	migrateKeyNames,
+}
+
+// MigrateConfig upgrades the content of a config file to ConfigVersion. It
+// returns the upgraded JSON and a description of each change, or the content
+// unchanged when it is already current.
+func MigrateConfig(data []byte) ([]byte, []string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var config map[string]interface{}
+	if err := decoder.Decode(&config); err != nil {
+		return nil, nil, err
+	}
+
+	version := 0
+	if key, ok := findKey(config, "Version"); ok {
+		number, isNumber := config[key].(json.Number)
+		v, err := number.Int64()
+		if !isNumber || err != nil || v < 0 {
+			return nil, nil, fmt.Errorf("invalid config version %v", config[key])
+		}
+		version = int(v)
+	}
+	if version > ConfigVersion {
+		return nil, nil, fmt.Errorf("config version %d is newer than this pathfix supports (%d)", version, ConfigVersion)
+	}
+	if version == ConfigVersion {
+		return data, nil, nil
+	}
+
+	var changes []string
+	for ; version < ConfigVersion; version++ {
+		changes = append(changes, configMigrations[version](config)...)
+	}
+	config["Version"] = ConfigVersion
+	changes = append(changes, fmt.Sprintf("set Version to %d", ConfigVersion))
+
+	migrated, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(migrated, '\n'), changes, nil
+}
+
+// migrateKeyNames upgrades version 0, which was decoded leniently: key names
+// in any case, and file type extensions without the leading dot or in upper
+// case, which never matched a file
+func migrateKeyNames(config map[string]interface{}) []string {
+	changes := canonicalKeys(config, reflect.TypeOf(models.Config{}), "")
+
+	if fileTypes, ok := config["FileTypes"].(map[string]interface{}); ok {
+		exts := make([]string, 0, len(fileTypes))
+		for ext := range fileTypes {
+			exts = append(exts, ext)
+		}
+		sort.Strings(exts)
+		for _, ext := range exts {
+			style := fileTypes[ext]
+			if fields, ok := style.(map[string]interface{}); ok {
+				changes = append(changes, canonicalKeys(fields, reflect.TypeOf(models.CommentStyle{}), "FileTypes."+ext+".")...)
+			}
+			normalized := strings.ToLower(ext)
+			if !strings.HasPrefix(normalized, ".") {
+				normalized = "." + normalized
+			}
+			if normalized == ext {
+				continue
+			}
+			delete(fileTypes, ext)
+			if _, exists := fileTypes[normalized]; exists {
+				changes = append(changes, fmt.Sprintf("removed file type %q, which duplicates %q", ext, normalized))
+				continue
+			}
+			fileTypes[normalized] = style
+			changes = append(changes, fmt.Sprintf("renamed file type %q to %q", ext, normalized))
+		}
+	}
+
+	if hooks, ok := config["Hooks"].(map[string]interface{}); ok {
+		changes = append(changes, canonicalKeys(hooks, reflect.TypeOf(models.Hooks{}), "Hooks.")...)
+	}
+	if plugins, ok := config["Plugins"].([]interface{}); ok {
+		for i, plugin := range plugins {
+			if fields, ok := plugin.(map[string]interface{}); ok {
+				changes = append(changes, canonicalKeys(fields, reflect.TypeOf(models.Plugin{}), fmt.Sprintf("Plugins[%d].", i))...)
+			}
+		}
+	}
+	return changes
+}
+
+// canonicalKeys renames the keys of object that match a field of the struct
+// type t in a different case, and reports keys that match no field
+func canonicalKeys(object map[string]interface{}, t reflect.Type, prefix string) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	for _, key := range keys {
+		field, ok := t.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, key) })
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("kept unknown key %s%s, which pathfix ignores", prefix, key))
+		case ok && field.Name != key:
+			object[field.Name] = object[key]
+			delete(object, key)
+			changes = append(changes, fmt.Sprintf("renamed %s%s to %s%s", prefix, key, prefix, field.Name))
+		}
+	}
+	return changes
+}
+
+// findKey returns the key of object that equals name ignoring case
+func findKey(object map[string]interface{}, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
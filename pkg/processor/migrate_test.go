@@ -0,0 +1,112 @@
+// File: pkg/processor/migrate_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changes  []string
+	}{
+		{
+			name:     "current version is unchanged",
+			input:    `{"Version": 1, "commentprefix": "Path: "}`,
+			expected: `{"Version": 1, "commentprefix": "Path: "}`,
+		},
+		{
+			name:     "key names",
+			input:    `{"commentPrefix": "Path: ", "includehidden": true}`,
+			expected: "{\n  \"CommentPrefix\": \"Path: \",\n  \"IncludeHidden\": true,\n  \"Version\": 1\n}\n",
+			changes: []string{
+				"renamed commentPrefix to CommentPrefix",
+				"renamed includehidden to IncludeHidden",
+				"set Version to 1",
+			},
+		},
+		{
+			name:     "file type extensions",
+			input:    `{"FileTypes": {"rs": {"linecomment": "//"}, ".PY": {"LineComment": "#"}, ".go": {"LineComment": "//"}}}`,
+			expected: "{\n  \"FileTypes\": {\n    \".go\": {\n      \"LineComment\": \"//\"\n    },\n    \".py\": {\n      \"LineComment\": \"#\"\n    },\n    \".rs\": {\n      \"LineComment\": \"//\"\n    }\n  },\n  \"Version\": 1\n}\n",
+			changes: []string{
+				`renamed file type ".PY" to ".py"`,
+				"renamed FileTypes.rs.linecomment to FileTypes.rs.LineComment",
+				`renamed file type "rs" to ".rs"`,
+				"set Version to 1",
+			},
+		},
+		{
+			name:     "nested sections and unknown keys",
+			input:    `{"hooks": {"prerun": ["make"]}, "Plugins": [{"command": ["lint"]}], "Color": true, "BinarySampleSize": 4096}`,
+			expected: "{\n  \"BinarySampleSize\": 4096,\n  \"Color\": true,\n  \"Hooks\": {\n    \"PreRun\": [\n      \"make\"\n    ]\n  },\n  \"Plugins\": [\n    {\n      \"Command\": [\n        \"lint\"\n      ]\n    }\n  ],\n  \"Version\": 1\n}\n",
+			changes: []string{
+				"kept unknown key Color, which pathfix ignores",
+				"renamed hooks to Hooks",
+				"renamed Hooks.prerun to Hooks.PreRun",
+				"renamed Plugins[0].command to Plugins[0].Command",
+				"set Version to 1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		migrated, changes, err := MigrateConfig([]byte(test.input))
+		if err != nil {
+			t.Errorf("MigrateConfig(%s) failed: %v", test.name, err)
+			continue
+		}
+		if string(migrated) != test.expected {
+			t.Errorf("MigrateConfig(%s) = %q, expected %q", test.name, migrated, test.expected)
+		}
+		if !reflect.DeepEqual(changes, test.changes) {
+			t.Errorf("MigrateConfig(%s) changes = %q, expected %q", test.name, changes, test.changes)
+		}
+	}
+}
+
+func TestMigrateConfigErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"Version": 2}`, "newer than this pathfix supports"},
+		{`{"Version": "one"}`, "invalid config version"},
+		{`[]`, "cannot unmarshal"},
+	}
+
+	for _, test := range tests {
+		_, _, err := MigrateConfig([]byte(test.input))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("MigrateConfig(%s) error = %v, expected %q", test.input, err, test.expected)
+		}
+	}
+}
+
+func TestLoadConfigMigrates(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-migrate-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A version 0 file whose extension never matched before migration
+	configPath := filepath.Join(tempDir, "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"filetypes": {"RS": {"LineComment": "//"}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if _, ok := config.FileTypes[".rs"]; !ok || config.Version != ConfigVersion {
+		t.Errorf("LoadConfig() = version %d, file types %v, expected version %d with .rs", config.Version, config.FileTypes, ConfigVersion)
+	}
+}
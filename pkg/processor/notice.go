@@ -0,0 +1,149 @@
+// File: pkg/processor/notice.go
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// loadNotice reads and parses the LicenseFile of config, or returns nil if
+// it names none
+func (p *Processor) loadNotice(config *models.Config) (*template.Template, error) {
+	if config.LicenseFile == "" {
+		return nil, nil
+	}
+	noticePath := config.LicenseFile
+	if !filepath.IsAbs(noticePath) {
+		noticePath = filepath.Join(p.rootDir, noticePath)
+	}
+	// A config that comes with the files can only use a notice among them
+	if p.confined {
+		rel, err := filepath.Rel(p.rootDir, noticePath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("error loading license file %s: it is outside the root directory; pass --allow-commands or list the config's directory in TrustedConfigs of the user config to use it", config.LicenseFile)
+		}
+	}
+	data, err := p.readFile(noticePath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading license file: %w", err)
+	}
+
+	text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	notice, err := models.ParseNoticeTemplate(text)
+	if err != nil {
+		return nil, fmt.Errorf("error loading license file %s: %w", config.LicenseFile, err)
+	}
+	// The notice is found again by its license keywords
+	if !containsLicense([]byte(text)) {
+		return nil, fmt.Errorf("error loading license file %s: the notice mentions no license or copyright", config.LicenseFile)
+	}
+	return notice, nil
+}
+
+// noticeComment returns the license notice for relPath as a comment in
+// style, the same comment with markers in place of the date and year, and
+// whether it is a block comment
+func (p *Processor) noticeComment(prefix, relPath string, style models.CommentStyle) (string, string, bool, error) {
+	text, marked, err := expand(p.notice, p.headerData(prefix, relPath))
+	if err != nil {
+		return "", "", false, err
+	}
+	comment, err := renderNotice(style, text)
+	if err != nil {
+		return "", "", false, err
+	}
+	markedComment, err := renderNotice(style, marked)
+	if err != nil {
+		return "", "", false, err
+	}
+	return comment, markedComment, noticeBlock(style, text), nil
+}
+
+// setNotice writes the license notice next to a file's header, returning the
+// text before the header, the header and the text after it. The notice goes
+// below the header, or with AfterLicense above it, replacing the license
+// comment found there, which the notice is taken to be an earlier version
+// of. A comment that differs from the notice only in its dates is kept.
+func (p *Processor) setNotice(relPath, prefix string, before []byte, bannerAt int, commentText string, rest []byte, style models.CommentStyle, prefixes ...string) ([]byte, string, []byte, error) {
+	notice, marked, block, err := p.noticeComment(prefix, relPath, style)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	current := func(existing string) string {
+		if dated := datePattern(marked); existing == notice || dated != nil && dated.MatchString(existing) {
+			return existing
+		}
+		return notice
+	}
+
+	if p.config.AfterLicense {
+		// The banner, if any, is the part of before from bannerAt
+		text := current(string(before[bannerAt:]))
+		return append(before[:bannerAt:bannerAt], text...), commentText, rest, nil
+	}
+	banner := licenseBannerLen(rest, style, prefixes...)
+	text := current(string(rest[:banner]))
+	if !block {
+		// A blank line ends a notice of line comments, so that a comment
+		// below it is not taken for part of the notice on the next run
+		text += "\n"
+		if line, n := firstLine(rest[banner:]); banner > 0 && n > 0 && strings.TrimSpace(line) == "" {
+			banner += n
+		}
+	}
+	return before, commentText + text, rest[banner:], nil
+}
+
+// renderNotice formats a license notice spanning lines as a comment in
+// style: a block comment where the language has one, decorated with " * "
+// as C-style comments usually are, and line comments otherwise
+func renderNotice(style models.CommentStyle, text string) (string, error) {
+	start := strings.TrimSpace(style.BlockCommentStart)
+	end := strings.TrimSpace(style.BlockCommentEnd)
+	line := strings.TrimSpace(style.LineComment)
+
+	var sb strings.Builder
+	writeLine := func(marker, text string) {
+		text = strings.TrimRight(text, " \t")
+		if marker != "" && text != "" {
+			marker += " "
+		}
+		sb.WriteString(marker + text + "\n")
+	}
+	switch {
+	case noticeBlock(style, text):
+		decoration := ""
+		if strings.HasSuffix(start, "*") {
+			decoration = " *"
+		}
+		sb.WriteString(start + "\n")
+		for _, text := range strings.Split(text, "\n") {
+			writeLine(decoration, text)
+		}
+		if decoration != "" {
+			end = " " + end
+		}
+		sb.WriteString(end + "\n")
+	case line != "":
+		for _, text := range strings.Split(text, "\n") {
+			writeLine(line, text)
+		}
+	default:
+		return "", errNoCommentStyle
+	}
+	return sb.String(), nil
+}
+
+// noticeBlock reports whether a notice is written as a block comment in
+// style. Blocks that open and close with the same marker, such as Python's
+// triple quotes, are string literals rather than comments, and the notice
+// must not hold the end marker.
+func noticeBlock(style models.CommentStyle, text string) bool {
+	start := strings.TrimSpace(style.BlockCommentStart)
+	end := strings.TrimSpace(style.BlockCommentEnd)
+	return start != "" && end != "" && start != end && !strings.Contains(text, end)
+}
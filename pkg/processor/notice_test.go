@@ -0,0 +1,189 @@
+// File: pkg/processor/notice_test.go
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestRenderNotice(t *testing.T) {
+	notice := "Copyright 2024 Acme\n\nLicensed under the MIT License."
+	testCases := []struct {
+		name     string
+		style    models.CommentStyle
+		text     string
+		expected string
+	}{
+		{"c block", models.LineStyle("//").WithBlock("/*", "*/"), notice, "/*\n * Copyright 2024 Acme\n *\n * Licensed under the MIT License.\n */\n"},
+		{"html block", models.BlockStyle("<!--", "-->"), notice, "<!--\nCopyright 2024 Acme\n\nLicensed under the MIT License.\n-->\n"},
+		{"python", models.LineStyle("#").WithBlock("'''", "'''"), notice, "# Copyright 2024 Acme\n#\n# Licensed under the MIT License.\n"},
+		{"end marker", models.LineStyle("//").WithBlock("/*", "*/"), "See */LICENSE", "// See */LICENSE\n"},
+	}
+
+	for _, tc := range testCases {
+		result, err := renderNotice(tc.style, tc.text)
+		if err != nil || result != tc.expected {
+			t.Errorf("renderNotice(%s) = %q, %v, expected %q", tc.name, result, err, tc.expected)
+		}
+	}
+	if _, err := renderNotice(models.CommentStyle{}, notice); err == nil {
+		t.Errorf("renderNotice(no markers) succeeded, expected an error")
+	}
+}
+
+func TestLicenseFile(t *testing.T) {
+	year := time.Now().Format("2006")
+	notice := "Copyright {{.Year}} Acme\n\nLicensed under the Apache License, Version 2.0.\n"
+	block := func(year string) string {
+		return "/*\n * Copyright " + year + " Acme\n *\n * Licensed under the Apache License, Version 2.0.\n */\n"
+	}
+	testCases := []struct {
+		name         string
+		afterLicense bool
+		files        map[string]string
+		expected     map[string]string
+	}{
+		{
+			name: "below header",
+			files: map[string]string{
+				"new.go":   "package a\n",
+				"dated.go": "// File: dated.go\n" + block("2019") + "package a\n",
+				"stale.go": "// File: stale.go\n/* Copyright 2019 Acme. MIT License. */\npackage a\n",
+				"bare.go":  "/* Copyright 2019 Acme. MIT License. */\npackage a\n",
+				"lib.py":   "import os\n",
+				"old.py":   "# File: old.py\n# Copyright 2019 Acme\n# Licensed under the MIT License.\n\nimport os\n",
+			},
+			expected: map[string]string{
+				"new.go":   "// File: new.go\n" + block(year) + "package a\n",
+				"dated.go": "// File: dated.go\n" + block("2019") + "package a\n",
+				"stale.go": "// File: stale.go\n" + block(year) + "package a\n",
+				"bare.go":  "// File: bare.go\n" + block(year) + "package a\n",
+				"lib.py":   "# File: lib.py\n# Copyright " + year + " Acme\n#\n# Licensed under the Apache License, Version 2.0.\n\nimport os\n",
+				"old.py":   "# File: old.py\n# Copyright " + year + " Acme\n#\n# Licensed under the Apache License, Version 2.0.\n\nimport os\n",
+			},
+		},
+		{
+			name:         "after license",
+			afterLicense: true,
+			files: map[string]string{
+				"new.go":   "package a\n",
+				"stale.go": "/* Copyright 2019 Acme. MIT License. */\n// File: stale.go\npackage a\n",
+				"lib.py":   "import os\n",
+			},
+			expected: map[string]string{
+				"new.go":   block(year) + "// File: new.go\npackage a\n",
+				"stale.go": block(year) + "// File: stale.go\npackage a\n",
+				"lib.py":   "# Copyright " + year + " Acme\n#\n# Licensed under the Apache License, Version 2.0.\n# File: lib.py\nimport os\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tempDir, err := os.MkdirTemp("", "notice-test")
+		if err != nil {
+			t.Fatalf("Failed to create temp directory: %v", err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		files := map[string]string{".notice.txt": notice}
+		for name, content := range tc.files {
+			files[name] = content
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+
+		config := models.NewConfig()
+		config.LicenseFile = ".notice.txt"
+		config.AfterLicense = tc.afterLicense
+		if _, err := NewProcessor(tempDir, &Options{Config: config}).Process(); err != nil {
+			t.Fatalf("%s: Processor.Process failed: %v", tc.name, err)
+		}
+		for name, want := range tc.expected {
+			content, err := os.ReadFile(filepath.Join(tempDir, name))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", name, err)
+			}
+			if string(content) != want {
+				t.Errorf("%s: unexpected content for %s: %q, expected %q", tc.name, name, string(content), want)
+			}
+		}
+
+		// The notices stay put on the next run
+		stats, err := NewProcessor(tempDir, &Options{Config: config}).Process()
+		if err != nil || stats.Updated != 0 {
+			t.Errorf("%s: second run = %+v, %v, expected no updates", tc.name, stats, err)
+		}
+	}
+
+	// A notice that would not be recognized again is refused
+	tempDir, err := os.MkdirTemp("", "notice-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	if err := os.WriteFile(filepath.Join(tempDir, "notice.txt"), []byte("Written by Jane Doe\n"), 0644); err != nil {
+		t.Fatalf("Failed to write notice.txt: %v", err)
+	}
+	config := models.NewConfig()
+	config.LicenseFile = "notice.txt"
+	p := NewProcessor(tempDir, &Options{Config: config, Stderr: &strings.Builder{}})
+	if err := p.ConfigError(); err == nil || !strings.Contains(err.Error(), "mentions no license") {
+		t.Errorf("ConfigError() with a notice without a license = %v, expected it refused", err)
+	}
+}
+
+func TestFoundConfigLicenseFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-notice-trust-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	repo := filepath.Join(tempDir, "repo")
+	if err := os.Mkdir(repo, 0755); err != nil {
+		t.Fatalf("Failed to create repo: %v", err)
+	}
+	for _, path := range []string{filepath.Join(tempDir, "secret.txt"), filepath.Join(repo, "notice.txt")} {
+		if err := os.WriteFile(path, []byte("Copyright 2024 Acme\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	configPath := filepath.Join(repo, ".pathfix.json")
+
+	tests := []struct {
+		licenseFile string
+		expected    bool // Whether a found config may use it
+	}{
+		{"notice.txt", true},
+		{filepath.Join(repo, "notice.txt"), true},
+		{"../secret.txt", false},
+		{filepath.Join(tempDir, "secret.txt"), false},
+	}
+	for _, test := range tests {
+		data, _ := json.Marshal(map[string]string{"LicenseFile": test.licenseFile})
+		if err := os.WriteFile(configPath, data, 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+
+		p := NewProcessor(repo, &Options{Stderr: &strings.Builder{}})
+		if err := p.ConfigError(); (err == nil) != test.expected || (err != nil && !strings.Contains(err.Error(), "outside the root directory")) {
+			t.Errorf("found config with LicenseFile %q: error %v, expected it allowed %v", test.licenseFile, err, test.expected)
+		}
+
+		// A trusted config can name any file
+		for _, options := range []*Options{{AllowCommands: true}, {ConfigFile: configPath}} {
+			if p := NewProcessor(repo, options); p.ConfigError() != nil || p.notice == nil {
+				t.Errorf("trusted config with LicenseFile %q: error %v, expected the notice", test.licenseFile, p.ConfigError())
+			}
+		}
+	}
+}
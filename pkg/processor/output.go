@@ -0,0 +1,161 @@
+// File: pkg/processor/output.go
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// outputChunk is text for stdout or stderr
+type outputChunk struct {
+	stderr bool
+	text   []byte
+}
+
+// fileOutput buffers the verbose and diagnostic lines about the current
+// file, so that they are written together once its outcome is recorded
+type fileOutput struct {
+	chunks []outputChunk
+}
+
+// add appends text to the last chunk for the same stream, or starts a new one
+func (o *fileOutput) add(stderr bool, text string) {
+	if n := len(o.chunks); n > 0 && o.chunks[n-1].stderr == stderr {
+		o.chunks[n-1].text = append(o.chunks[n-1].text, text...)
+		return
+	}
+	o.chunks = append(o.chunks, outputChunk{stderr: stderr, text: []byte(text)})
+}
+
+// printf buffers a line for stdout
+func (p *Processor) printf(format string, args ...interface{}) {
+	p.output.add(false, fmt.Sprintf(format, args...))
+}
+
+// errorf buffers a line for stderr
+func (p *Processor) errorf(format string, args ...interface{}) {
+	p.output.add(true, fmt.Sprintf(format, args...))
+}
+
+// flushOutput writes the buffered lines, one write per stream switch
+func (p *Processor) flushOutput() {
+	for _, chunk := range p.output.chunks {
+		if chunk.stderr {
+			p.stderr().Write(chunk.text)
+		} else {
+			p.stdout().Write(chunk.text)
+		}
+	}
+	p.output.chunks = p.output.chunks[:0]
+}
+
+// stdout returns where verbose output goes
+func (p *Processor) stdout() io.Writer {
+	if p.outputMu != nil {
+		return syncWriter{mu: p.outputMu, w: p.options.stdout()}
+	}
+	return p.options.stdout()
+}
+
+// stderr returns where errors and warnings go
+func (p *Processor) stderr() io.Writer {
+	if p.outputMu != nil {
+		return syncWriter{mu: p.outputMu, w: p.options.stderr()}
+	}
+	return p.options.stderr()
+}
+
+// stdout returns Stdout, or os.Stdout if it is not set
+func (o *Options) stdout() io.Writer {
+	if o.Stdout != nil {
+		return o.Stdout
+	}
+	return os.Stdout
+}
+
+// stderr returns Stderr, or os.Stderr if it is not set
+func (o *Options) stderr() io.Writer {
+	if o.Stderr != nil {
+		return o.Stderr
+	}
+	return os.Stderr
+}
+
+// syncWriter serializes the writes of several goroutines, so that each
+// write appears whole
+type syncWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (s syncWriter) Write(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(data)
+}
+
+// rootOutput collects the output of one root of an ordered multi-root run
+type rootOutput struct {
+	mu     sync.Mutex
+	chunks []outputChunk
+	done   bool
+}
+
+// rootWriter is the stdout or stderr of a root in an ordered run
+type rootWriter struct {
+	output *rootOutput
+	stderr bool
+}
+
+func (w rootWriter) Write(data []byte) (int, error) {
+	w.output.mu.Lock()
+	defer w.output.mu.Unlock()
+	w.output.chunks = append(w.output.chunks, outputChunk{stderr: w.stderr, text: bytes.Clone(data)})
+	return len(data), nil
+}
+
+// orderedOutput writes the output of each root once it and all the roots
+// before it are done, so the roots appear in the order they were given
+type orderedOutput struct {
+	mu     sync.Mutex
+	stdout io.Writer
+	stderr io.Writer
+	roots  []*rootOutput
+	next   int // The first root not written yet
+}
+
+// newOrderedOutput returns the ordered output for n roots
+func newOrderedOutput(stdout, stderr io.Writer, n int) *orderedOutput {
+	o := &orderedOutput{stdout: stdout, stderr: stderr, roots: make([]*rootOutput, n)}
+	for i := range o.roots {
+		o.roots[i] = &rootOutput{}
+	}
+	return o
+}
+
+// writers returns the stdout and stderr of root i
+func (o *orderedOutput) writers(i int) (io.Writer, io.Writer) {
+	return rootWriter{output: o.roots[i]}, rootWriter{output: o.roots[i], stderr: true}
+}
+
+// finish marks root i as done and writes the output of every root that is
+// ready
+func (o *orderedOutput) finish(i int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.roots[i].done = true
+	for o.next < len(o.roots) && o.roots[o.next].done {
+		for _, chunk := range o.roots[o.next].chunks {
+			if chunk.stderr {
+				o.stderr.Write(chunk.text)
+			} else {
+				o.stdout.Write(chunk.text)
+			}
+		}
+		o.roots[o.next] = nil
+		o.next++
+	}
+}
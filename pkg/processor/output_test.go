@@ -0,0 +1,80 @@
+// File: pkg/processor/output_test.go
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOrderedOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	output := newOrderedOutput(&stdout, &stderr, 3)
+	for i := 0; i < 3; i++ {
+		out, errOut := output.writers(i)
+		fmt.Fprintf(out, "root %d\n", i)
+		fmt.Fprintf(errOut, "warning %d\n", i)
+	}
+
+	// Later roots wait for the earlier ones
+	tests := []struct {
+		finish   int
+		expected string
+	}{
+		{2, ""},
+		{0, "root 0\n"},
+		{1, "root 0\nroot 1\nroot 2\n"},
+	}
+	for _, test := range tests {
+		output.finish(test.finish)
+		if stdout.String() != test.expected {
+			t.Errorf("finish(%d) wrote %q, expected %q", test.finish, stdout.String(), test.expected)
+		}
+	}
+	if expected := "warning 0\nwarning 1\nwarning 2\n"; stderr.String() != expected {
+		t.Errorf("Ordered stderr = %q, expected %q", stderr.String(), expected)
+	}
+}
+
+func TestProcessRootsOutput(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "output-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var roots []string
+	for i := 0; i < 4; i++ {
+		root := filepath.Join(tempDir, fmt.Sprintf("root%d", i))
+		if err := os.MkdirAll(root, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", root, err)
+		}
+		for j := 0; j < 20; j++ {
+			name := filepath.Join(root, fmt.Sprintf("file%02d.go", j))
+			if err := os.WriteFile(name, []byte("package a\n"), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+		roots = append(roots, root)
+	}
+
+	var stdout, stderr bytes.Buffer
+	options := Options{DryRun: true, Verbose: true, OrderedOutput: true, Stdout: &stdout, Stderr: &stderr}
+	ProcessRoots(roots, options, 4)
+
+	// Every root's lines follow those of the roots before it
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 80 {
+		t.Fatalf("ProcessRoots printed %d lines, expected 80", len(lines))
+	}
+	for i, line := range lines {
+		if root := roots[i/20]; !strings.Contains(line, root+string(filepath.Separator)) {
+			t.Errorf("Line %d is %q, expected a file of %s", i, line, root)
+			break
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// File: pkg/processor/pathnames.go
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// errInvalidFileName is returned for file names that are not valid UTF-8
+// when the configured policy is "fail"
+var errInvalidFileName = errors.New("file name is not valid UTF-8")
+
+// errSkipFileName signals that a non-UTF-8 file name is skipped by policy
+var errSkipFileName = errors.New("file name is not valid UTF-8, skipped")
+
+// encodeHeaderPath applies the non-UTF-8 file name policy. Valid names are
+// returned unchanged; invalid ones are skipped, percent-encoded or rejected
+// so that mojibake is never written into a file.
+func (p *Processor) encodeHeaderPath(relPath string) (string, error) {
+	if utf8.ValidString(relPath) {
+		return relPath, nil
+	}
+
+	switch strings.ToLower(p.config.InvalidPathPolicy) {
+	case "", "skip":
+		return "", errSkipFileName
+	case "escape":
+		return percentEncodeInvalid(relPath), nil
+	case "fail":
+		return "", fmt.Errorf("%w: %q", errInvalidFileName, relPath)
+	default:
+		return "", fmt.Errorf("unknown invalid path policy: %s", p.config.InvalidPathPolicy)
+	}
+}
+
+// percentEncodeInvalid replaces bytes that are not part of valid UTF-8
+// sequences (and literal percent signs) with %XX escapes
+func percentEncodeInvalid(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			fmt.Fprintf(&b, "%%%02X", s[i])
+		case r == '%':
+			b.WriteString("%25")
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// normalizeHeaderPath applies the configured Unicode normalization form to a
+// header path, so that macOS (NFD) and Linux (NFC) checkouts agree
+func (p *Processor) normalizeHeaderPath(relPath string) (string, error) {
+	switch strings.ToLower(p.config.PathNormalization) {
+	case "", "nfc":
+		return norm.NFC.String(relPath), nil
+	case "nfd":
+		return norm.NFD.String(relPath), nil
+	case "none":
+		return relPath, nil
+	default:
+		return "", fmt.Errorf("unknown path normalization: %s", p.config.PathNormalization)
+	}
+}
+
+// sameHeader reports whether an existing header is equivalent to the
+// rendered one, ignoring Unicode normalization and, if configured, case
+func (p *Processor) sameHeader(existing, rendered string) bool {
+	if existing == rendered {
+		return true
+	}
+	if strings.ToLower(p.config.PathNormalization) != "none" {
+		existing = norm.NFC.String(existing)
+		rendered = norm.NFC.String(rendered)
+	}
+	if p.config.IgnoreCase {
+		return strings.EqualFold(existing, rendered)
+	}
+	return existing == rendered
+}
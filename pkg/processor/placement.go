@@ -0,0 +1,154 @@
+// File: pkg/processor/placement.go
+package processor
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// utf8BOM is the UTF-8 encoded byte order mark
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// findInsertionPoint returns the byte offset at which the header belongs
+func findInsertionPoint(content []byte, style models.CommentStyle) int {
+	// A byte order mark must stay at the very start of the file
+	offset := 0
+	if bytes.HasPrefix(content, utf8BOM) {
+		offset = len(utf8BOM)
+	}
+
+	// A shebang must stay on the first line for the kernel to honor it
+	if line, n := firstLine(content[offset:]); isShebang(line) {
+		offset += n
+	}
+
+	// VBA reads the attributes of an exported module only before any code
+	if style.Dialect == "vba" {
+		offset += vbaAttributesLen(content[offset:])
+	}
+
+	// Go build constraints stay first, as a block comment above them would
+	// disable them
+	if style.Dialect == "go" {
+		offset += goConstraintsLen(content[offset:])
+	}
+
+	// Text before <?php is output, so the header goes inside the PHP block
+	if style.Dialect == "php" {
+		offset += phpOpenTagLen(content[offset:])
+	}
+
+	switch style.Placement {
+	case "frontmatter":
+		return offset + skipFrontmatter(content[offset:])
+	case "after-first-line":
+		_, n := firstLine(content[offset:])
+		return offset + n
+	default:
+		return offset
+	}
+}
+
+// licenseKeywords mark a comment at the top of a file as a license banner
+var licenseKeywords = []string{"copyright", "licence", "license", "spdx-", "(c)", "©"}
+
+// licenseBannerLen returns the length of the license or copyright comment at
+// the start of content, through the end of its last line, or 0 if content
+// does not start with one. The banner is a block comment or a run of line
+// comments that mentions a license; a path header written with one of
+// prefixes ends the run.
+func licenseBannerLen(content []byte, style models.CommentStyle, prefixes ...string) int {
+	lineMarker := strings.TrimSpace(style.LineComment)
+	blockStart := strings.TrimSpace(style.BlockCommentStart)
+	blockEnd := strings.TrimSpace(style.BlockCommentEnd)
+
+	if line, _ := firstLine(content); isHeaderLine(line, style, prefixes...) {
+		return 0
+	}
+
+	n := 0
+	trimmed := bytes.TrimLeft(content, " \t")
+	switch {
+	case blockStart != "" && blockEnd != "" && bytes.HasPrefix(trimmed, []byte(blockStart)):
+		// The banner runs through the line holding the end marker
+		start := len(content) - len(trimmed) + len(blockStart)
+		end := bytes.Index(content[start:], []byte(blockEnd))
+		if end < 0 {
+			return 0
+		}
+		end += start + len(blockEnd)
+		_, rest := firstLine(content[end:])
+		n = end + rest
+	case lineMarker != "" && bytes.HasPrefix(trimmed, []byte(lineMarker)):
+		for n < len(content) {
+			line, lineLen := firstLine(content[n:])
+			if !strings.HasPrefix(strings.TrimSpace(line), lineMarker) || isHeaderLine(line, style, prefixes...) {
+				break
+			}
+			n += lineLen
+		}
+	}
+
+	if n == 0 || !containsLicense(content[:n]) {
+		return 0
+	}
+	return n
+}
+
+// containsLicense reports whether text mentions a license or copyright
+func containsLicense(text []byte) bool {
+	lower := strings.ToLower(string(text))
+	for _, keyword := range licenseKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isShebang checks for an interpreter line, excluding Rust inner attributes (#![...])
+func isShebang(line string) bool {
+	return strings.HasPrefix(line, "#!") && !strings.HasPrefix(line, "#![")
+}
+
+// skipFrontmatter returns the offset just past a leading YAML (---) or TOML (+++) frontmatter block
+func skipFrontmatter(content []byte) int {
+	line, n := firstLine(content)
+	delimiter := strings.TrimRight(line, "\r")
+	if delimiter != "---" && delimiter != "+++" {
+		return 0
+	}
+
+	offset := n
+	for offset < len(content) {
+		line, n = firstLine(content[offset:])
+		offset += n
+		if strings.TrimRight(line, "\r") == delimiter {
+			return offset
+		}
+	}
+
+	// Unterminated frontmatter is treated as ordinary content
+	return 0
+}
+
+// lineEnding returns the line ending content uses, \r\n if any line ends
+// with one and \n otherwise
+func lineEnding(content []byte) string {
+	if bytes.Contains(content, []byte("\r\n")) {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// firstLine returns the first line of content (without its newline) and
+// the number of bytes it occupies including the newline
+func firstLine(content []byte) (string, int) {
+	i := bytes.IndexByte(content, '\n')
+	if i < 0 {
+		return string(content), len(content)
+	}
+	return string(content[:i]), i + 1
+}
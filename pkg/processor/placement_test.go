@@ -0,0 +1,305 @@
+// File: pkg/processor/placement_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestSkipFrontmatter(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"# Title\n", 0},
+		{"---\ntitle: x\n---\n# Title\n", 17},
+		{"+++\ntitle = 'x'\n+++\nbody\n", 20},
+		{"---\r\ntitle: x\r\n---\r\nbody\r\n", 20},
+		{"---\nunterminated\n", 0},
+	}
+
+	for _, test := range tests {
+		result := skipFrontmatter([]byte(test.content))
+		if result != test.expected {
+			t.Errorf("skipFrontmatter(%q) = %d, expected %d", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestShebangPlacement(t *testing.T) {
+	shell := models.CommentStyle{LineComment: "#", Preferred: "line"}
+	rust := models.CommentStyle{LineComment: "//", Preferred: "line"}
+
+	tests := []struct {
+		content  string
+		style    models.CommentStyle
+		expected int
+	}{
+		{"#!/usr/bin/env zsh\necho hi\n", shell, 19},
+		{"#! /bin/sh\n", shell, 11},
+		{"echo hi\n", shell, 0},
+		{"#![allow(dead_code)]\nfn main() {}\n", rust, 0},
+		{"\xEF\xBB\xBF#!/bin/sh\n", shell, 13},
+		{"\xEF\xBB\xBFfn main() {}\n", rust, 3},
+	}
+
+	for _, test := range tests {
+		result := findInsertionPoint([]byte(test.content), test.style)
+		if result != test.expected {
+			t.Errorf("findInsertionPoint(%q) = %d, expected %d", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestDocFileProcessing(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "docs-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"guide.md":    "---\ntitle: Guide\n---\n# Guide\n",
+		"index.rst":   "Index\n=====\n",
+		"manual.adoc": "= Manual\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Docs are skipped unless explicitly included
+	p := NewProcessor(tempDir, &Options{})
+	if _, ok := p.lookupFileType("guide.md"); ok {
+		t.Errorf("Markdown should not be processed without IncludeDocs")
+	}
+
+	p = NewProcessor(tempDir, &Options{IncludeDocs: true})
+	if _, err := p.Process(); err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"guide.md":    "---\ntitle: Guide\n---\n<!-- File: guide.md -->\n# Guide\n",
+		"index.rst":   ".. File: index.rst\n\nIndex\n=====\n",
+		"manual.adoc": "// File: manual.adoc\n= Manual\n",
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+
+	// A second run must leave the frontmatter header in place
+	stats, err := NewProcessor(tempDir, &Options{IncludeDocs: true}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Updated != 0 {
+		t.Errorf("Expected no updates on second run, got: %d", stats.Updated)
+	}
+}
+
+func TestLicenseBannerLen(t *testing.T) {
+	cStyle := models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"}
+	shell := models.CommentStyle{LineComment: "#", Preferred: "line"}
+
+	tests := []struct {
+		content  string
+		style    models.CommentStyle
+		expected int
+	}{
+		{"// Copyright 2024 Acme\n// All rights reserved.\n\npackage a\n", cStyle, 47},
+		{"// SPDX-License-Identifier: MIT\npackage a\n", cStyle, 32},
+		{"/*\n * Licensed under the Apache License\n */\nint x;\n", cStyle, 44},
+		{"/* (c) Acme */ int x;\n", cStyle, 22},
+		{"# Copyright Acme\n# File: run.sh\n", shell, 17},
+		{"// Package a does things.\npackage a\n", cStyle, 0},
+		{"// File: license.go\npackage a\n", cStyle, 0},
+		{"/* Copyright, unterminated\n", cStyle, 0},
+		{"package a // Copyright\n", cStyle, 0},
+		{"/* Copyright */\n", shell, 0},
+	}
+
+	for _, test := range tests {
+		result := licenseBannerLen([]byte(test.content), test.style, "File: ")
+		if result != test.expected {
+			t.Errorf("licenseBannerLen(%q) = %d, expected %d", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestAfterLicense(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "license-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"banner.go": "// Copyright 2024 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n",
+		"above.go":  "// File: above.go\n// Copyright 2024 Acme\n\npackage a\n",
+		"plain.go":  "// Package a does things.\npackage a\n",
+		"run.sh":    "#!/bin/sh\n# Licensed under the MIT license\necho hi\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	stats, err := NewProcessor(tempDir, &Options{AfterLicense: true}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Stale != 1 {
+		t.Errorf("Expected the header above the banner to count as stale, got: %d", stats.Stale)
+	}
+
+	expected := map[string]string{
+		"banner.go": "// Copyright 2024 Acme\n// SPDX-License-Identifier: MIT\n// File: banner.go\n\npackage a\n",
+		"above.go":  "// Copyright 2024 Acme\n// File: above.go\n\npackage a\n",
+		"plain.go":  "// File: plain.go\n// Package a does things.\npackage a\n",
+		"run.sh":    "#!/bin/sh\n# Licensed under the MIT license\n# File: run.sh\necho hi\n",
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+
+	// A second run must leave the headers below the banners
+	stats, err = NewProcessor(tempDir, &Options{AfterLicense: true}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Updated != 0 {
+		t.Errorf("Expected no updates on second run, got: %d", stats.Updated)
+	}
+}
+
+func TestGoBuildConstraints(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "placement-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"linux.go",
+			"//go:build linux\n\npackage a\n",
+			"//go:build linux\n\n// File: linux.go\npackage a\n",
+		},
+		{
+			"legacy.go",
+			"//go:build linux && amd64\n// +build linux,amd64\n\n// Package a does things.\npackage a\n",
+			"//go:build linux && amd64\n// +build linux,amd64\n\n// File: legacy.go\n// Package a does things.\npackage a\n",
+		},
+		{
+			"plain.go",
+			"// Package a does things.\npackage a\n",
+			"// File: plain.go\n// Package a does things.\npackage a\n",
+		},
+		{
+			// A header above the constraints, from before they were handled, stays
+			"old.go",
+			"// File: old.go\n//go:build linux\n\npackage a\n",
+			"// File: old.go\n//go:build linux\n\npackage a\n",
+		},
+	}
+
+	for _, test := range tests {
+		if err := os.WriteFile(filepath.Join(tempDir, test.name), []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+	}
+
+	// Run twice to make sure the headers are recognized on the second pass
+	config := models.NewConfig()
+	for i := 0; i < 2; i++ {
+		if _, err := NewProcessor(tempDir, &Options{Config: config}).Process(); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(filepath.Join(tempDir, test.name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", test.name, string(content), test.expected)
+		}
+	}
+
+	block := models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "block", Dialect: "go"}
+	content := []byte("//go:build windows\n\npackage a\n")
+	if result := findInsertionPoint(content, block); result != 20 {
+		t.Errorf("insertion point for %q = %d, expected 20", content, result)
+	}
+}
+
+func TestNoTrailingNewline(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "noeol-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Files whose only line is the one the header goes below
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"noeol.sh", "#!/bin/sh", "#!/bin/sh\n# File: noeol.sh\n"},
+		{"free.cob", ">>SOURCE FORMAT FREE", ">>SOURCE FORMAT FREE\n*> File: free.cob\n"},
+		{"keys.reg", "Windows Registry Editor Version 5.00", "Windows Registry Editor Version 5.00\n; File: keys.reg\n"},
+		{"Dockerfile", "# syntax=docker/dockerfile:1", "# syntax=docker/dockerfile:1\n# File: Dockerfile\n"},
+		{"run.bat", "@echo off", "@echo off\nREM File: run.bat\n"},
+		{"tagged.go", "//go:build linux", "//go:build linux\n// File: tagged.go\n"},
+		{"crlf.go", "//go:build linux\r\n// +build linux", "//go:build linux\r\n// +build linux\r\n// File: crlf.go\n"},
+		{"index.php", "<?php", "<?php\n// File: index.php\n"},
+	}
+	for _, test := range tests {
+		if err := os.WriteFile(filepath.Join(tempDir, test.name), []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+	}
+
+	// Run twice to make sure the headers are recognized on the second pass
+	config := models.NewConfig()
+	for i := 0; i < 2; i++ {
+		if _, err := NewProcessor(tempDir, &Options{Config: config}).Process(); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(filepath.Join(tempDir, test.name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("Unexpected content for %s: %q, expected %q", test.name, string(content), test.expected)
+		}
+	}
+}
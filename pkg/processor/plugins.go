@@ -0,0 +1,122 @@
+// File: pkg/processor/plugins.go
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// pluginTimeout bounds how long a plugin may take to answer for one file
+const pluginTimeout = 30 * time.Second
+
+// placementTop is how the default placement is named in the plugin protocol
+const placementTop = "top"
+
+// PluginRequest is written to a plugin's stdin for each candidate file
+type PluginRequest struct {
+	Path      string `json:"path"`      // Path relative to the root directory, as written in the header
+	Type      string `json:"type"`      // Detected file type (the lowercased extension)
+	Header    string `json:"header"`    // Proposed header, including its trailing newline
+	Placement string `json:"placement"` // "top", "frontmatter" or "after-first-line"
+}
+
+// PluginResponse is read from a plugin's stdout. Empty output accepts the
+// proposal unchanged.
+type PluginResponse struct {
+	Skip      bool    `json:"skip"`      // Leave the file alone
+	Reason    string  `json:"reason"`    // Why the file was skipped
+	Header    *string `json:"header"`    // Replacement header (empty removes an existing one); should keep the comment prefix so later runs recognize it
+	Placement string  `json:"placement"` // Replacement placement; empty keeps the proposal
+}
+
+// runPlugins consults the configured plugins matching relPath in order,
+// each seeing the proposal as rewritten by the previous ones
+func (p *Processor) runPlugins(relPath string, style models.CommentStyle, header string) (models.CommentStyle, string, error) {
+	for _, plugin := range p.config.Plugins {
+		if len(plugin.Command) == 0 {
+			continue
+		}
+		if len(plugin.Patterns) > 0 && !p.matchesAny(relPath, plugin.Patterns) {
+			continue
+		}
+
+		placement := style.Placement
+		if placement == "" {
+			placement = placementTop
+		}
+		response, err := p.callPlugin(plugin, PluginRequest{
+			Path:      relPath,
+			Type:      strings.ToLower(path.Ext(relPath)),
+			Header:    header,
+			Placement: placement,
+		})
+		if err != nil {
+			return style, header, err
+		}
+
+		if response.Skip {
+			reason := "vetoed by plugin " + plugin.Command[0]
+			if response.Reason != "" {
+				reason += ": " + response.Reason
+			}
+			return style, header, skipReason(reason)
+		}
+		if response.Header != nil {
+			header = *response.Header
+			if header != "" && !strings.HasSuffix(header, "\n") {
+				header += "\n"
+			}
+		}
+		switch response.Placement {
+		case "":
+		case placementTop:
+			style.Placement = ""
+		case "frontmatter", "after-first-line":
+			style.Placement = response.Placement
+		default:
+			return style, header, fmt.Errorf("plugin %s returned unknown placement %q", plugin.Command[0], response.Placement)
+		}
+	}
+	return style, header, nil
+}
+
+// callPlugin runs a plugin for a single file
+func (p *Processor) callPlugin(plugin models.Plugin, request PluginRequest) (PluginResponse, error) {
+	var response PluginResponse
+	input, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, plugin.Command[0], plugin.Command[1:]...)
+	cmd.Dir = p.rootDir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return response, fmt.Errorf("plugin %s failed: %w: %s", plugin.Command[0], err, message)
+		}
+		return response, fmt.Errorf("plugin %s failed: %w", plugin.Command[0], err)
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return response, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return response, fmt.Errorf("plugin %s returned invalid JSON: %w", plugin.Command[0], err)
+	}
+	return response, nil
+}
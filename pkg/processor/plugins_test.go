@@ -0,0 +1,90 @@
+// File: pkg/processor/plugins_test.go
+package processor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// TestPluginHelperProcess is not a real test: it is run as a plugin by
+// TestPlugins, behaving as selected by PATHFIX_PLUGIN_MODE
+func TestPluginHelperProcess(t *testing.T) {
+	mode := os.Getenv("PATHFIX_PLUGIN_MODE")
+	if mode == "" {
+		return
+	}
+
+	var request PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintf(os.Stderr, "bad request: %v", err)
+		os.Exit(2)
+	}
+	switch mode {
+	case "veto":
+		fmt.Print(`{"skip": true, "reason": "generated code"}`)
+	case "rewrite":
+		header := strings.TrimSuffix(request.Header, "\n") + " (" + request.Type + ")"
+		json.NewEncoder(os.Stdout).Encode(PluginResponse{Header: &header, Placement: "after-first-line"})
+	case "fail":
+		fmt.Fprint(os.Stderr, "rule violated")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestPlugins(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-plugins-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	helper := []string{os.Args[0], "-test.run=^TestPluginHelperProcess$"}
+
+	tests := []struct {
+		mode     string
+		patterns []string
+		expected string
+		skip     bool
+		fail     bool
+	}{
+		{"accept", nil, "// File: main.go\npackage main\n", false, false},
+		{"veto", nil, "package main\n", true, false},
+		{"veto", []string{"*.py"}, "// File: main.go\npackage main\n", false, false},
+		{"rewrite", nil, "package main\n// File: main.go (.go)\n", false, false},
+		{"fail", nil, "package main\n", false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			t.Setenv("PATHFIX_PLUGIN_MODE", test.mode)
+			path := filepath.Join(tempDir, "main.go")
+			if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			processor := NewProcessor(tempDir, &Options{})
+			processor.config.Plugins = []models.Plugin{{Command: helper, Patterns: test.patterns}}
+			_, err := processor.processFile(path, "main.go")
+
+			var skip skipReason
+			if isSkip := err != nil && errors.As(err, &skip); isSkip != test.skip {
+				t.Errorf("processFile(main.go) with %s plugin = %v, expected skip %v", test.mode, err, test.skip)
+			}
+			if isFail := err != nil && !test.skip; isFail != test.fail {
+				t.Errorf("processFile(main.go) with %s plugin = %v, expected failure %v", test.mode, err, test.fail)
+			}
+			content, _ := os.ReadFile(path)
+			if string(content) != test.expected {
+				t.Errorf("processFile(main.go) with %s plugin wrote %q, expected %q", test.mode, content, test.expected)
+			}
+		})
+	}
+}
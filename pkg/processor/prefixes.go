@@ -0,0 +1,46 @@
+// File: pkg/processor/prefixes.go
+package processor
+
+import "sort"
+
+// commentPrefix returns the text written before relPath in its header: the
+// PathPrefixes entry of the longest pattern relPath matches, or CommentPrefix
+// if it matches none. Patterns of equal length are tried in sorted order.
+func (p *Processor) commentPrefix(relPath string) string {
+	prefix, longest := p.config.CommentPrefix, ""
+	for _, pattern := range p.prefixPatterns() {
+		if len(pattern) > len(longest) && p.matchesAny(relPath, []string{pattern}) {
+			prefix, longest = p.config.PathPrefixes[pattern], pattern
+		}
+	}
+	return prefix
+}
+
+// headerPrefixes returns every prefix a header may have been written with,
+// starting with prefix, so that a header written for another pattern is
+// recognized and replaced rather than taken for a missing one
+func (p *Processor) headerPrefixes(prefix string) []string {
+	prefixes := []string{prefix}
+	seen := map[string]bool{prefix: true}
+	candidates := []string{p.config.CommentPrefix}
+	for _, pattern := range p.prefixPatterns() {
+		candidates = append(candidates, p.config.PathPrefixes[pattern])
+	}
+	for _, candidate := range candidates {
+		if !seen[candidate] {
+			seen[candidate] = true
+			prefixes = append(prefixes, candidate)
+		}
+	}
+	return prefixes
+}
+
+// prefixPatterns returns the PathPrefixes patterns in sorted order
+func (p *Processor) prefixPatterns() []string {
+	patterns := make([]string, 0, len(p.config.PathPrefixes))
+	for pattern := range p.config.PathPrefixes {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
@@ -0,0 +1,81 @@
+// File: pkg/processor/prefixes_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestCommentPrefix(t *testing.T) {
+	config := models.NewConfig().
+		WithPathPrefix("*_test.go", "Test file: ").
+		WithPathPrefix("gen/*", "Generated: ").
+		WithPathPrefix("gen/*_test.go", "Generated test: ")
+	processor := &Processor{config: config}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"main.go", "File: "},
+		{"pkg/a_test.go", "Test file: "},
+		{"gen/stub.go", "Generated: "},
+		{"gen/stub_test.go", "Generated test: "},
+		{"gen/sub/stub.go", "File: "},
+	}
+
+	for _, test := range tests {
+		if result := processor.commentPrefix(test.path); result != test.expected {
+			t.Errorf("commentPrefix(%s) = %q, expected %q", test.path, result, test.expected)
+		}
+	}
+}
+
+func TestPathPrefixProcessing(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "prefixes-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"a_test.go":  "package a\n",
+		"moved.go":   "// Test file: moved_test.go\npackage a\n",
+		"b_test.go":  "// File: b_test.go\npackage a\n",
+		"ok_test.go": "// Test file: ok_test.go\npackage a\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := models.NewConfig().WithPathPrefix("*_test.go", "Test file: ")
+	stats, err := NewProcessor(tempDir, &Options{Config: config}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Missing != 1 || stats.Stale != 2 {
+		t.Errorf("Expected 1 missing and 2 stale headers, got: %d missing, %d stale", stats.Missing, stats.Stale)
+	}
+
+	expected := map[string]string{
+		"a_test.go":  "// Test file: a_test.go\npackage a\n",
+		"moved.go":   "// File: moved.go\npackage a\n",
+		"b_test.go":  "// Test file: b_test.go\npackage a\n",
+		"ok_test.go": files["ok_test.go"],
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+}
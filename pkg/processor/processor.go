@@ -2,24 +2,131 @@
 package processor
 
 import (
-	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/state"
 )
 
 // Options represents processor options
 type Options struct {
-	DryRun        bool
-	ConfigFile    string
-	Verbose       bool
-	IncludeHidden bool
+	DryRun             bool
+	ConfigFile         string         // The config file; when empty, the root's own is found with FindConfig
+	Config             *models.Config // Used instead of the config files when set; it is copied, not modified
+	UserConfig         bool           // Load personal defaults from UserConfigPath beneath ConfigFile
+	AllowCommands      bool           // Run the Hooks, Plugins and Validators of a config found with FindConfig, which are otherwise ignored, and allow its LicenseFile outside the root
+	Verbose            bool
+	IncludeHidden      bool
+	IncludeDocs        bool
+	IncludeExecutables bool
+	AutoDetect         bool
+	MatchCommentStyle  bool
+	Style              string // Overrides the configured Style: "line" or "block"
+	AfterLicense       bool
+	FollowSymlinks     bool
+	GitIgnoreOnly      bool // Honor only .gitignore, not git's other exclude files
+	IgnoreCase         bool
+	SampleSize         int // Overrides the configured binary sniffing window when positive
+	DetectContentType  bool
+	FailFast           bool     // Stop at the first file that fails instead of carrying on
+	ListOnly           bool     // Only detect eligible files; they are recorded as listed without being read in full or written
+	StateDir           string   // Overrides the configured state directory
+	CreatedAfter       string   // Overrides the configured CreatedAfter cutoff
+	History            bool     // Record each run in the state directory's history
+	Backup             bool     // Back up each file in the state directory before modifying it
+	Diffs              bool     // Record a unified diff of each change in the file's result
+	PrintDiffs         bool     // Print a unified diff of each change to Stdout, as a preview in dry runs
+	Remove             bool     // Remove existing headers instead of adding or updating them
+	MovedOnly          bool     // Only update headers that name another path, as after a rename; files without one are skipped
+	Languages          []string // Overrides the configured Languages when not nil
+	ExcludeLanguages   []string // Overrides the configured ExcludeLanguages when not nil
+
+	// Workers is how many files are processed at once, or 0 for the
+	// config's Workers; below 2, they are processed one at a time. The walk and the recording of outcomes stay
+	// on one goroutine, so with several workers only the order in which
+	// files are recorded changes, but per-file hooks and validators may run
+	// concurrently.
+	Workers int
+
+	// FS, if set, holds the files to process instead of the root directory
+	// on disk: the root directory is its ".", and only its own .gitignore
+	// applies. Files are written through WriteFileFS, so a run that is not
+	// a dry run needs one. Config files, hooks, validators, git history,
+	// the state directory and backups are not part of it; hooks and
+	// validators still run on the root directory on disk.
+	FS fs.FS
+
+	// Files, if not nil, are processed instead of walking the root
+	// directory. They are absolute or relative to the root; listed
+	// directories are walked.
+	Files []string
+
+	// Staged and Tracked take the files to process from git instead of
+	// walking the root directory: the files staged for commit (added,
+	// copied, modified or renamed), or every file git tracks. Only files
+	// beneath the root are processed, and the usual filters still apply.
+	// Neither can be combined with the other, Files or FS.
+	Staged  bool
+	Tracked bool
+
+	// Stdout and Stderr receive the verbose output and the errors and
+	// warnings (default os.Stdout and os.Stderr). The lines about a file are
+	// written together once its outcome is recorded.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// OrderedOutput makes ProcessRoots write the output of each root once
+	// the roots before it are done, rather than as each file finishes
+	OrderedOutput bool
+
+	// Change limits override the config's MaxChangedFiles and
+	// MaxChangedPercent. A run that would exceed them calls Confirm with the
+	// number of files that would change and the number visited, and is
+	// refused with ErrTooManyChanges unless it returns true.
+	MaxChangedFiles   int
+	MaxChangedPercent float64
+	Confirm           func(changed, total int) bool
+
+	// OnResult, if set, is called with the outcome of each file as the walk progresses
+	OnResult func(models.FileResult)
+
+	// Callbacks for progress displays and reports of the embedding program,
+	// each optional. Paths are relative to the root, with forward slashes.
+	// OnFileStart is called before a file that passed the walk's filters, or
+	// an archive entry, is checked. Once a file's outcome is recorded, after
+	// OnResult, one of the others is called, or none for a file that was up
+	// to date or only listed: OnFileUpdated for a file that was updated (or
+	// would be, in a dry run), OnFileSkipped with the reason a file was left
+	// alone, and OnError with why it failed. Within a run, they are never
+	// called at the same time.
+	OnFileStart   func(path string)
+	OnFileUpdated func(result models.FileResult)
+	OnFileSkipped func(path, reason string)
+	OnError       func(path string, err error)
+
+	// Callbacks of Watch, each optional, for monitoring: OnWatchQueue is
+	// called with the number of new files waiting to be processed whenever
+	// it changes, OnWatchRun before each run over them and OnWatchRunDone
+	// after it, with the error the run returned
+	OnWatchQueue   func(depth int)
+	OnWatchRun     func()
+	OnWatchRunDone func(err error)
+
+	// Log, if set, receives a JSON line for the start and end of each run and
+	// for every file visited, regardless of Verbose
+	Log io.Writer
 }
 
 // Processor handles the file processing logic
@@ -28,7 +135,34 @@ type Processor struct {
 	options    *Options
 	config     *models.Config
 	fileTypes  map[string]models.CommentStyle
+	fileNames  map[string]models.CommentStyle // Styles of files known by name, keyed by glob
+	nameGlobs  []string                       // The keys of fileNames, sorted
+	header     *template.Template             // The parsed HeaderTemplate
+	notice     *template.Template             // The parsed LicenseFile, or nil without one
+	project    string                         // The project name headers can include
+	languages  map[string]bool                // Extensions of the languages to process, or nil for all
+	excluded   map[string]bool                // Extensions of the languages not to process
 	statistics models.Stats
+	results    []models.FileResult
+	stopped    bool // A FailFast run stopped at a failed file
+	failures   FileErrors
+	configErr  error         // Why the config file could not be loaded
+	confined   bool          // The LicenseFile comes from an untrusted config, so it must be inside the root
+	planning   bool          // A dry run checking change limits, which skips hooks
+	counting   bool          // A walk counting the files to check, which reads none
+	backup     *state.Backup // Where files are saved before they are modified, when enabled
+
+	bufferIgnore     *GitIgnore // The ignore rules FixBuffer applies, loaded on first use
+	bufferIgnoreTime time.Time  // The modification time of the .gitignore they were read from
+
+	mu sync.Mutex // Serializes runs, resets and config reloads
+
+	output   fileOutput  // Lines about the current file, written once its outcome is recorded
+	outputMu *sync.Mutex // Keeps the writes of workers apart while they are running
+
+	scriptOnce sync.Once
+	script     *script
+	scriptErr  error
 }
 
 // NewProcessor creates a new processor
@@ -38,40 +172,192 @@ func NewProcessor(rootDir string, options *Options) *Processor {
 		options: options,
 	}
 
-	// Initialize default file types
-	p.initializeFileTypes()
-
 	// Load config file if specified
-	var config *models.Config
-	var err error
+	config, err := p.loadConfig()
+	if err == nil {
+		err = p.applyConfig(config)
+	}
+	if err != nil {
+		fmt.Fprintf(p.stderr(), "Warning: Error loading config file: %v\n", err)
+		p.configErr = err
+		p.applyConfig(models.NewConfig())
+	}
+	return p
+}
 
-	if options.ConfigFile != "" {
-		config, err = LoadConfig(options.ConfigFile)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Error loading config file: %v\n", err)
-			config = &models.Config{
-				CommentPrefix: "File: ",
-				DryRun:        options.DryRun,
-				IncludeHidden: options.IncludeHidden,
-			}
+// loadConfig reads the user config and the project config named by the
+// options or found for the root, or returns the default configuration when
+// there are none
+func (p *Processor) loadConfig() (*models.Config, error) {
+	if p.options.Config != nil {
+		// Merging fills in FileTypes, so the caller's map is left alone
+		config := *p.options.Config
+		config.FileTypes = make(map[string]models.CommentStyle, len(p.options.Config.FileTypes))
+		for ext, style := range p.options.Config.FileTypes {
+			config.FileTypes[ext] = style
 		}
-	} else {
-		// Default configuration
-		config = &models.Config{
-			CommentPrefix: "File: ",
-			DryRun:        options.DryRun,
-			IncludeHidden: options.IncludeHidden,
+		return &config, nil
+	}
+
+	userConfig := ""
+	if p.options.UserConfig {
+		userConfig = UserConfigPath()
+	}
+	configFile := p.options.ConfigFile
+	trusted := true
+	if configFile == "" {
+		// A config that comes with the files, such as in a cloned
+		// repository, must not run commands unless the user allows it
+		configFile = FindConfig(p.rootDir)
+		trusted = p.options.AllowCommands
+	}
+	if configFile == "" && userConfig == "" {
+		return models.NewConfig(), nil
+	}
+	config, ignored, confined, err := loadConfigFiles(userConfig, configFile, trusted)
+	p.confined = confined
+	if len(ignored) > 0 {
+		fmt.Fprintf(p.stderr(), "Warning: Ignoring %s in %s; pass --allow-commands or list its directory in TrustedConfigs of the user config to run them\n", strings.Join(ignored, ", "), configFile)
+	}
+	return config, err
+}
+
+// applyConfig combines a loaded config with the built-in file types and the
+// options, and makes it the processor's configuration. A config that is
+// invalid once combined is rejected, leaving the processor unchanged.
+func (p *Processor) applyConfig(config *models.Config) error {
+	options := p.options
+
+	// Documentation formats are opt-in
+	fileTypes, docTypes := DefaultFileTypes()
+	if options.IncludeDocs {
+		config.IncludeDocs = true
+	}
+	if config.IncludeDocs {
+		for ext, style := range docTypes {
+			fileTypes[ext] = style
 		}
 	}
 
 	// Merge with default file types
-	p.config = MergeConfig(config, p.fileTypes)
-	
+	config = MergeConfig(config, fileTypes)
+	config.FileNames = mergeFileNames(config.FileNames, DefaultFileNames())
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if options.Languages != nil {
+		config.Languages = options.Languages
+	}
+	if options.ExcludeLanguages != nil {
+		config.ExcludeLanguages = options.ExcludeLanguages
+	}
+	languages, err := languageExtensions("Languages", config.Languages)
+	if err != nil {
+		return err
+	}
+	excluded, err := languageExtensions("ExcludeLanguages", config.ExcludeLanguages)
+	if err != nil {
+		return err
+	}
+	header, err := models.ParseHeaderTemplate(config.HeaderTemplate)
+	if err != nil {
+		return err
+	}
+	notice, err := p.loadNotice(config)
+	if err != nil {
+		return err
+	}
+	p.config = config
+	p.fileTypes = config.FileTypes
+	p.fileNames, p.nameGlobs = config.FileNames, sortedPatterns(config.FileNames)
+	p.header, p.notice, p.project = header, notice, projectName(p.rootDir)
+	p.languages, p.excluded = languages, excluded
+
+	// Verbose output can be a personal default
+	if config.Verbose {
+		options.Verbose = true
+	}
+
 	// Override with command line options
 	p.config.DryRun = options.DryRun
 	p.config.IncludeHidden = options.IncludeHidden
+	if options.IgnoreCase || gitIgnoreCase(p.rootDir) {
+		p.config.IgnoreCase = true
+	}
+	if options.FollowSymlinks {
+		p.config.FollowSymlinks = true
+	}
+	if options.GitIgnoreOnly {
+		p.config.GitIgnoreOnly = true
+	}
+	if options.Backup {
+		p.config.Backup = true
+	}
+	if options.IncludeExecutables {
+		p.config.IncludeExecutables = true
+	}
+	if options.AutoDetect {
+		p.config.AutoDetect = true
+	}
+	if options.MatchCommentStyle {
+		p.config.MatchCommentStyle = true
+	}
+	if options.Style != "" {
+		p.config.Style = options.Style
+	}
+	if options.AfterLicense {
+		p.config.AfterLicense = true
+	}
+	if options.DetectContentType {
+		p.config.DetectContentType = true
+	}
+	if options.SampleSize > 0 {
+		p.config.BinarySampleSize = options.SampleSize
+	}
 
-	return p
+	// The script is loaded again on first use, since the config may name another
+	p.scriptOnce = sync.Once{}
+	p.script = nil
+	p.scriptErr = nil
+
+	// The config decides how ignore rules match
+	p.bufferIgnore = nil
+	return nil
+}
+
+// ReloadConfig reads the config files again, for processors that are reused
+// across runs. If they cannot be loaded or fail validation, the processor
+// keeps its current configuration and the error is returned. Ignore files
+// need no reload, as each run reads them when it starts.
+func (p *Processor) ReloadConfig() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	config, err := p.loadConfig()
+	if err != nil {
+		return err
+	}
+	if err := p.applyConfig(config); err != nil {
+		return err
+	}
+	p.configErr = nil
+	return nil
+}
+
+// ResetStats clears the statistics and results of the previous run. Runs
+// start with it, so it is only needed to clear a processor's report early.
+func (p *Processor) ResetStats() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetStats()
+}
+
+// resetStats implements ResetStats
+func (p *Processor) resetStats() {
+	p.statistics = models.Stats{}
+	p.results = nil
+	p.stopped = false
+	p.failures = nil
 }
 
 // initializeFileTypes sets up supported file types and their comment styles
@@ -79,7 +365,7 @@ func (p *Processor) initializeFileTypes() {
 	p.fileTypes = map[string]models.CommentStyle{
 		// C-style languages
 		".cs":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
-		".go":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".go":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line", Dialect: "go"},
 		".c":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
 		".cpp":  {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
 		".h":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
@@ -90,17 +376,69 @@ func (p *Processor) initializeFileTypes() {
 		".jsx":  {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
 		".tsx":  {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
 
+		// TypeScript declarations, kept apart from .ts so they can be
+		// configured or excluded on their own
+		".d.ts": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+
+		// Other .NET languages
+		".vb":  {LineComment: "'", Preferred: "line"},
+		".fs":  {LineComment: "//", BlockCommentStart: "(*", BlockCommentEnd: "*)", Preferred: "line", Dialect: "fsharp"},
+		".fsi": {LineComment: "//", BlockCommentStart: "(*", BlockCommentEnd: "*)", Preferred: "line", Dialect: "fsharp"},
+		".fsx": {LineComment: "//", BlockCommentStart: "(*", BlockCommentEnd: "*)", Preferred: "line", Dialect: "fsharp"},
+
 		// Shell/script languages
-		".sh":  {LineComment: "#", Preferred: "line"},
-		".ps1": {LineComment: "#", Preferred: "line"},
-		".py":  {LineComment: "#", BlockCommentStart: "'''", BlockCommentEnd: "'''", Preferred: "line"},
-		".rb":  {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+		".sh":   {LineComment: "#", Preferred: "line"},
+		".bash": {LineComment: "#", Preferred: "line"},
+		".zsh":  {LineComment: "#", Preferred: "line"},
+		".ksh":  {LineComment: "#", Preferred: "line"},
+		".csh":  {LineComment: "#", Preferred: "line"},
+		".fish": {LineComment: "#", Preferred: "line"},
+		".py":   {LineComment: "#", BlockCommentStart: "'''", BlockCommentEnd: "'''", Preferred: "line"},
+		".rb":   {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+
+		// PowerShell scripts, modules and manifests. The header goes above any
+		// #Requires statements and comment-based help, which both allow that.
+		".ps1":  {LineComment: "#", BlockCommentStart: "<#", BlockCommentEnd: "#>", Preferred: "line", Dialect: "powershell"},
+		".psm1": {LineComment: "#", BlockCommentStart: "<#", BlockCommentEnd: "#>", Preferred: "line", Dialect: "powershell"},
+		".psd1": {LineComment: "#", BlockCommentStart: "<#", BlockCommentEnd: "#>", Preferred: "line", Dialect: "powershell"},
+
+		// Windows batch files, with REM, or :: where the file uses those
+		".bat": {LineComment: "REM", Preferred: "line", Dialect: "batch"},
+		".cmd": {LineComment: "REM", Preferred: "line", Dialect: "batch"},
+
+		// VBScript and exported VBA modules, classes and forms. The header goes
+		// below the attributes VBA reads at the top of an exported file.
+		".vbs": {LineComment: "'", Preferred: "line"},
+		".bas": {LineComment: "'", Preferred: "line", Dialect: "vba"},
+		".cls": {LineComment: "'", Preferred: "line", Dialect: "vba"},
+		".frm": {LineComment: "'", Preferred: "line", Dialect: "vba"},
 
 		// Web languages
 		".html": {LineComment: "", BlockCommentStart: "<!--", BlockCommentEnd: "-->", Preferred: "block"},
 		".xml":  {LineComment: "", BlockCommentStart: "<!--", BlockCommentEnd: "-->", Preferred: "block"},
 		".css":  {LineComment: "", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "block"},
 
+		// CSS preprocessors. Silent // comments are dropped from the compiled CSS;
+		// indented Sass gets no block style since /* */ is emitted to the output.
+		".scss": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".sass": {LineComment: "//", Preferred: "line"},
+		".less": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".styl": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+
+		// Template languages. Template comments never reach the rendered output;
+		// where the engine supports it the closing tag also trims the newline.
+		".j2":     {LineComment: "", BlockCommentStart: "{#", BlockCommentEnd: "-#}", Preferred: "block"},
+		".jinja":  {LineComment: "", BlockCommentStart: "{#", BlockCommentEnd: "-#}", Preferred: "block"},
+		".jinja2": {LineComment: "", BlockCommentStart: "{#", BlockCommentEnd: "-#}", Preferred: "block"},
+		".twig":   {LineComment: "", BlockCommentStart: "{#", BlockCommentEnd: "#}", Preferred: "block"},
+		".erb":    {LineComment: "", BlockCommentStart: "<%#", BlockCommentEnd: "-%>", Preferred: "block"},
+		".hbs":    {LineComment: "", BlockCommentStart: "{{!", BlockCommentEnd: "}}", Preferred: "block"},
+		".gotmpl": {LineComment: "", BlockCommentStart: "{{/*", BlockCommentEnd: "*/ -}}", Preferred: "block"},
+		".tmpl":   {LineComment: "", BlockCommentStart: "{{/*", BlockCommentEnd: "*/ -}}", Preferred: "block"},
+
+		// Blade templates end in .php, but a // comment would be rendered
+		".blade.php": {LineComment: "", BlockCommentStart: "{{--", BlockCommentEnd: "--}}", Preferred: "block"},
+
 		// Config files
 		".yaml": {LineComment: "#", Preferred: "line"},
 		".yml":  {LineComment: "#", Preferred: "line"},
@@ -108,50 +446,445 @@ func (p *Processor) initializeFileTypes() {
 		".ini":  {LineComment: ";", Preferred: "line"},
 		".conf": {LineComment: "#", Preferred: "line"},
 
+		// Infrastructure as code and schemas. nginx and Apache httpd configs are
+		// .conf files; .htaccess is a hidden file like .gitconfig.
+		".tf":       {LineComment: "#", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".tfvars":   {LineComment: "#", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".hcl":      {LineComment: "#", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".proto":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".graphql":  {LineComment: "#", Preferred: "line"},
+		".gql":      {LineComment: "#", Preferred: "line"},
+		".htaccess": {LineComment: "#", Preferred: "line"},
+
+		// systemd units, desktop entries and git config files
+		".service":    {LineComment: "#", Preferred: "line"},
+		".socket":     {LineComment: "#", Preferred: "line"},
+		".timer":      {LineComment: "#", Preferred: "line"},
+		".target":     {LineComment: "#", Preferred: "line"},
+		".mount":      {LineComment: "#", Preferred: "line"},
+		".path":       {LineComment: "#", Preferred: "line"},
+		".desktop":    {LineComment: "#", Preferred: "line"},
+		".gitconfig":  {LineComment: "#", Preferred: "line"},
+		".gitmodules": {LineComment: "#", Preferred: "line"},
+
+		// Comment-tolerant JSON dialects
+		".jsonc": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".json5": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+
 		// Other languages
-		".rs":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".rs":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
 		".swift": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
 		".kt":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
 		".lua":   {LineComment: "--", BlockCommentStart: "--[[", BlockCommentEnd: "--]]", Preferred: "line"},
 		".pl":    {LineComment: "#", Preferred: "line"},
-		".php":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".php":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line", Dialect: "php"},
+
+		// JVM ecosystem and Dart. Comments may precede Kotlin @file: annotations
+		// and package declarations, so the header stays at the top of the file.
+		// ".kts" also covers Gradle Kotlin DSL scripts (.gradle.kts).
+		".kts":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".scala":  {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".sbt":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".groovy": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".gradle": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".dart":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+
+		// Functional languages. OCaml has only block comments; Clojure has
+		// none, and ;; is the convention for top-level comments in Lisps.
+		".hs":   {LineComment: "--", BlockCommentStart: "{-", BlockCommentEnd: "-}", Preferred: "line"},
+		".ml":   {LineComment: "", BlockCommentStart: "(*", BlockCommentEnd: "*)", Preferred: "block"},
+		".mli":  {LineComment: "", BlockCommentStart: "(*", BlockCommentEnd: "*)", Preferred: "block"},
+		".ex":   {LineComment: "#", Preferred: "line"},
+		".exs":  {LineComment: "#", Preferred: "line"},
+		".erl":  {LineComment: "%", Preferred: "line"},
+		".hrl":  {LineComment: "%", Preferred: "line"},
+		".clj":  {LineComment: ";;", Preferred: "line"},
+		".cljs": {LineComment: ";;", Preferred: "line"},
+		".cljc": {LineComment: ";;", Preferred: "line"},
+		".edn":  {LineComment: ";;", Preferred: "line"},
+		".lisp": {LineComment: ";;", BlockCommentStart: "#|", BlockCommentEnd: "|#", Preferred: "line"},
+		".lsp":  {LineComment: ";;", BlockCommentStart: "#|", BlockCommentEnd: "|#", Preferred: "line"},
+		".scm":  {LineComment: ";;", BlockCommentStart: "#|", BlockCommentEnd: "|#", Preferred: "line"},
+
+		// Scientific computing. ".m" is shared with Objective-C, and the %{ %}
+		// block markers of MATLAB must stand on lines of their own.
+		".r":   {LineComment: "#", Preferred: "line"},
+		".jl":  {LineComment: "#", BlockCommentStart: "#=", BlockCommentEnd: "=#", Preferred: "line"},
+		".m":   {LineComment: "%", Preferred: "line", Dialect: "matlab"},
+		".tex": {LineComment: "%", Preferred: "line"},
+		".sty": {LineComment: "%", Preferred: "line"},
+		".ltx": {LineComment: "%", Preferred: "line"},
+
+		// Fortran. A ! in column 1 also starts a comment in fixed-form sources.
+		".f":   {LineComment: "!", Preferred: "line"},
+		".for": {LineComment: "!", Preferred: "line"},
+		".f77": {LineComment: "!", Preferred: "line"},
+		".f90": {LineComment: "!", Preferred: "line"},
+		".f95": {LineComment: "!", Preferred: "line"},
+		".f03": {LineComment: "!", Preferred: "line"},
+		".f08": {LineComment: "!", Preferred: "line"},
+
+		// Assembly. NASM and MASM use ;, which separates statements in the GNU
+		// assembler, whose .s files get the /* */ comments it takes on every
+		// target.
+		".asm":  {LineComment: ";", Preferred: "line"},
+		".nasm": {LineComment: ";", Preferred: "line"},
+		".s":    {LineComment: "", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "block"},
+
+		// Pascal/Delphi and Ada. Pascal headers in (* *) are recognized too,
+		// and can be written by setting those markers.
+		".pas": {LineComment: "//", BlockCommentStart: "{", BlockCommentEnd: "}", Preferred: "line", Dialect: "pascal"},
+		".pp":  {LineComment: "//", BlockCommentStart: "{", BlockCommentEnd: "}", Preferred: "line", Dialect: "pascal"},
+		".dpr": {LineComment: "//", BlockCommentStart: "{", BlockCommentEnd: "}", Preferred: "line", Dialect: "pascal"},
+		".ads": {LineComment: "--", Preferred: "line"},
+		".adb": {LineComment: "--", Preferred: "line"},
+
+		// Windows resources and installer scripts
+		".rc":  {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".rc2": {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".reg": {LineComment: ";", Preferred: "line", Dialect: "reg"},
+		".iss": {LineComment: ";", Preferred: "line"},
+		".nsi": {LineComment: ";", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".nsh": {LineComment: ";", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+
+		// Hardware description languages. ".v" is shared with V and Coq
+		".v":    {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line", Dialect: "v"},
+		".vh":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".sv":   {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".svh":  {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+		".vhd":  {LineComment: "--", Preferred: "line"},
+		".vhdl": {LineComment: "--", Preferred: "line"},
+
+		// Fixed-format COBOL and RPG (comment indicator in column 7)
+		".cbl":      {LineComment: fixedFormatComment, Preferred: "line", Dialect: "cobol"},
+		".cob":      {LineComment: fixedFormatComment, Preferred: "line", Dialect: "cobol"},
+		".cpy":      {LineComment: fixedFormatComment, Preferred: "line", Dialect: "cobol"},
+		".rpg":      {LineComment: fixedFormatComment, Preferred: "line", Dialect: "rpg"},
+		".rpgle":    {LineComment: fixedFormatComment, Preferred: "line", Dialect: "rpg"},
+		".sqlrpgle": {LineComment: fixedFormatComment, Preferred: "line", Dialect: "rpg"},
+	}
+
+	// Files known by name rather than extension. Globs without a slash
+	// match the file name; see fileNameType.
+	p.fileNames = map[string]models.CommentStyle{
+		// Container and build definitions
+		"Dockerfile":      {LineComment: "#", Preferred: "line", Dialect: "dockerfile"},
+		"Dockerfile.*":    {LineComment: "#", Preferred: "line", Dialect: "dockerfile"},
+		"*.Dockerfile":    {LineComment: "#", Preferred: "line", Dialect: "dockerfile"},
+		"Containerfile":   {LineComment: "#", Preferred: "line", Dialect: "dockerfile"},
+		"Containerfile.*": {LineComment: "#", Preferred: "line", Dialect: "dockerfile"},
+		"Makefile":        {LineComment: "#", Preferred: "line"},
+		"makefile":        {LineComment: "#", Preferred: "line"},
+		"GNUmakefile":     {LineComment: "#", Preferred: "line"},
+		"CMakeLists.txt":  {LineComment: "#", Preferred: "line"},
+		"Justfile":        {LineComment: "#", Preferred: "line"},
+		"justfile":        {LineComment: "#", Preferred: "line"},
+		"Earthfile":       {LineComment: "#", Preferred: "line"},
+		"Caddyfile":       {LineComment: "#", Preferred: "line"},
+		"Jenkinsfile":     {LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"},
+
+		// Ruby DSLs
+		"Vagrantfile": {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+		"Gemfile":     {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+		"Rakefile":    {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+		"Podfile":     {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+		"Brewfile":    {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+		"Fastfile":    {LineComment: "#", BlockCommentStart: "=begin", BlockCommentEnd: "=end", Preferred: "line"},
+
+		// Starlark and Python build files
+		"BUILD":           {LineComment: "#", Preferred: "line"},
+		"BUILD.bazel":     {LineComment: "#", Preferred: "line"},
+		"WORKSPACE":       {LineComment: "#", Preferred: "line"},
+		"WORKSPACE.bazel": {LineComment: "#", Preferred: "line"},
+		"MODULE.bazel":    {LineComment: "#", Preferred: "line"},
+		"Tiltfile":        {LineComment: "#", Preferred: "line"},
+		"Snakefile":       {LineComment: "#", Preferred: "line"},
+		"Pipfile":         {LineComment: "#", Preferred: "line"},
+	}
+}
+
+// docFileTypes returns the documentation formats enabled by IncludeDocs.
+// Each uses a comment syntax that is invisible in rendered output.
+func docFileTypes() map[string]models.CommentStyle {
+	return map[string]models.CommentStyle{
+		".md":       {LineComment: "", BlockCommentStart: "<!--", BlockCommentEnd: "-->", Preferred: "block", Placement: "frontmatter"},
+		".markdown": {LineComment: "", BlockCommentStart: "<!--", BlockCommentEnd: "-->", Preferred: "block", Placement: "frontmatter"},
+		".mdx":      {LineComment: "", BlockCommentStart: "{/*", BlockCommentEnd: "*/}", Preferred: "block", Placement: "frontmatter"},
+		".rst":      {LineComment: "..", Preferred: "line", HeaderSuffix: "\n"},
+		".adoc":     {LineComment: "//", Preferred: "line"},
+	}
+}
+
+// DefaultFileTypes returns the built-in comment styles by extension, and
+// the documentation formats added by IncludeDocs
+func DefaultFileTypes() (code, docs map[string]models.CommentStyle) {
+	p := &Processor{}
+	p.initializeFileTypes()
+	return p.fileTypes, docFileTypes()
+}
+
+// DefaultFileNames returns the built-in comment styles of files known by
+// name, keyed by glob
+func DefaultFileNames() map[string]models.CommentStyle {
+	p := &Processor{}
+	p.initializeFileTypes()
+	return p.fileNames
+}
+
+// State returns the state directory for the root directory
+func (p *Processor) State() *state.Dir {
+	dir := p.config.StateDir
+	if p.options.StateDir != "" {
+		dir = p.options.StateDir
+	}
+	return state.Open(p.rootDir, dir)
+}
+
+// gitIgnore loads the .gitignore at the top of the root directory and,
+// unless GitIgnoreOnly is set, git's other exclude files
+func (p *Processor) gitIgnore() (*GitIgnore, error) {
+	// Options.FS is not a git work tree, so only its .gitignore applies
+	if p.options.FS != nil {
+		patterns, err := readGitIgnoreFS(p.options.FS)
+		if err != nil {
+			return nil, err
+		}
+		return &GitIgnore{patterns: patterns, rootDir: p.rootDir, ignoreCase: p.config.IgnoreCase}, nil
 	}
+
+	gitignore, err := NewGitIgnore(p.rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("error loading .gitignore: %w", err)
+	}
+	if !p.config.GitIgnoreOnly {
+		if err := gitignore.LoadGitExcludes(); err != nil {
+			return nil, fmt.Errorf("error loading git excludes: %w", err)
+		}
+	}
+	gitignore.SetIgnoreCase(p.config.IgnoreCase)
+	return gitignore, nil
 }
 
-// Process walks through the directory and processes files
+// bufferGitIgnore returns the ignore rules for FixBuffer. Editors fix a
+// buffer on every save, so the rules are kept and only read again when the
+// root .gitignore changes.
+func (p *Processor) bufferGitIgnore() (*GitIgnore, error) {
+	var info fs.FileInfo
+	var err error
+	if p.options.FS != nil {
+		info, err = fs.Stat(p.options.FS, ".gitignore")
+	} else {
+		info, err = os.Stat(filepath.Join(p.rootDir, ".gitignore"))
+	}
+	var modTime time.Time
+	if err == nil {
+		modTime = info.ModTime()
+	}
+	if p.bufferIgnore != nil && modTime.Equal(p.bufferIgnoreTime) {
+		return p.bufferIgnore, nil
+	}
+
+	gitignore, err := p.gitIgnore()
+	if err != nil {
+		return nil, err
+	}
+	p.bufferIgnore, p.bufferIgnoreTime = gitignore, modTime
+	return gitignore, nil
+}
+
+// configIgnores matches the config's AdditionalIgnores, which use
+// .gitignore syntax relative to the root directory
+func (p *Processor) configIgnores() *GitIgnore {
+	return &GitIgnore{
+		patterns:   parseGitIgnore(p.config.AdditionalIgnores),
+		rootDir:    p.rootDir,
+		ignoreCase: p.config.IgnoreCase,
+	}
+}
+
+// ConfigError returns the error that prevented the config file from
+// loading, or nil. The processor then runs with the default configuration.
+func (p *Processor) ConfigError() error {
+	return p.configErr
+}
+
+// Process walks through the directory and processes files. If the walk
+// completes but some files could not be processed, the returned error is a
+// FileErrors listing them; any other error means the run itself failed.
 func (p *Processor) Process() (models.Stats, error) {
+	return p.ProcessContext(context.Background())
+}
+
+// ProcessContext is Process with a context that can cancel the run. The
+// walk stops before the next file once ctx is done, but files already being
+// processed, by the workers too, are finished, so none is left half-written.
+// The statistics cover the files processed until then, and the error wraps
+// ctx.Err(); the post-run hook does not run.
+func (p *Processor) ProcessContext(ctx context.Context) (models.Stats, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetStats()
+	finish := p.logRun()
+	stats, err := p.process(ctx)
+	finish(err)
+	p.flushOutput()
+	return stats, err
+}
+
+// process implements ProcessContext
+func (p *Processor) process(ctx context.Context) (models.Stats, error) {
+	if err := canceled(ctx); err != nil {
+		return p.statistics, err
+	}
+
 	// Load gitignore if it exists
-	gitignore, err := NewGitIgnore(p.rootDir)
+	gitignore, err := p.gitIgnore()
+	if err != nil {
+		return p.statistics, err
+	}
+	ignores := p.configIgnores()
+
+	// A broken script would fail every file, so stop before walking
+	if _, err := p.loadScript(); err != nil {
+		return p.statistics, err
+	}
+	before, err := p.filesBefore()
 	if err != nil {
-		return p.statistics, fmt.Errorf("error loading .gitignore: %w", err)
+		return p.statistics, err
+	}
+	// Listing runs are read-only previews, so hooks only run for real runs
+	if !p.options.ListOnly && !p.planning {
+		if err := p.runPreRunHook(); err != nil {
+			return p.statistics, err
+		}
+	}
+	files, err := p.filesToProcess()
+	if err != nil {
+		return p.statistics, err
+	}
+	if err := p.checkChangeLimits(ctx); err != nil {
+		return p.statistics, err
 	}
 
-	err = filepath.WalkDir(p.rootDir, func(path string, d fs.DirEntry, err error) error {
+	// Files are backed up before they are modified, so the run can be undone;
+	// backups are kept on disk, so files on Options.FS are not
+	p.backup = nil
+	if p.config.Backup && p.options.FS == nil && !p.options.DryRun && !p.options.ListOnly && !p.planning {
+		p.backup = p.State().NewBackup(time.Now())
+	}
+
+	// Eligible files go to the workers, if there are several
+	var pool *workerPool
+	workers := p.options.Workers
+	if workers == 0 {
+		workers = p.config.Workers
+	}
+	if workers > 1 {
+		pool = p.startWorkers(workers)
+	}
+
+	// Real paths of directories already walked, to break symlink cycles
+	visited := make(map[string]bool)
+
+	var walkFn fs.WalkDirFunc
+	walkFn = func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := canceled(ctx); err != nil {
+			return err
+		}
+
+		// Symbolic links and junctions are skipped unless following is enabled
+		if kind := linkKind(path, d); kind != "" && path != p.rootDir {
+			if !p.config.FollowSymlinks {
+				if p.options.Verbose {
+					p.printf("Skipping %s: %s\n", kind, path)
+				}
+				p.statistics.Skipped++
+				p.record(path, models.ActionSkipped, kind, nil)
+				return nil
+			}
+
+			target, err := p.stat(path)
+			if err != nil {
+				if p.options.Verbose {
+					p.errorf("Error resolving %s %s: %v\n", kind, path, err)
+				}
+				p.statistics.Errors++
+				p.record(path, models.ActionError, "", err)
+				return p.stopOnError(path, err)
+			}
+			if target.IsDir() {
+				// The trailing separator makes WalkDir resolve the link itself;
+				// the visited set below stops cycles
+				return p.walkDir(path+string(filepath.Separator), walkFn)
+			}
+		}
 
 		// Skip directories
 		if d.IsDir() {
+			// pathfix's own state is never processed
+			if path == p.State().Path() {
+				return filepath.SkipDir
+			}
+
 			// Skip hidden directories unless explicitly included
-			if !p.options.IncludeHidden && isHidden(d.Name()) {
+			if !p.options.IncludeHidden && path != p.rootDir && isHiddenPath(path, d.Name()) {
 				return filepath.SkipDir
 			}
+
+			// Don't walk the same directory twice when links are followed
+			if p.config.FollowSymlinks {
+				realPath, err := filepath.EvalSymlinks(path)
+				if err != nil || p.options.FS != nil {
+					realPath = path
+				}
+				if visited[realPath] {
+					if p.options.Verbose {
+						p.printf("Skipping already visited directory: %s\n", path)
+					}
+					return filepath.SkipDir
+				}
+				visited[realPath] = true
+			}
+			return nil
+		}
+
+		// Protected files are never touched, whatever else is configured
+		if rel, err := filepath.Rel(p.rootDir, path); err == nil && p.isProtected(rel) {
+			if p.options.Verbose {
+				p.printf("Skipping protected file: %s\n", path)
+			}
+			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, reasonProtected, nil)
 			return nil
 		}
 
 		// Skip hidden files unless explicitly included
-		if !p.options.IncludeHidden && isHidden(d.Name()) {
+		if !p.options.IncludeHidden && isHiddenPath(path, d.Name()) {
 			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, "hidden file", nil)
 			return nil
 		}
 
 		// Skip files ignored by gitignore unless explicitly included
 		if !p.config.IncludeGitIgnored && gitignore.ShouldIgnore(path) {
 			if p.options.Verbose {
-				fmt.Printf("Skipping gitignored file: %s\n", path)
+				p.printf("Skipping gitignored file: %s\n", path)
+			}
+			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, "gitignored file", nil)
+			return nil
+		}
+
+		// Skip files matching the config's AdditionalIgnores
+		if ignores.ShouldIgnore(path) {
+			if p.options.Verbose {
+				p.printf("Skipping ignored file: %s\n", path)
 			}
 			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, "ignored by config", nil)
 			return nil
 		}
 
@@ -159,152 +892,740 @@ func (p *Processor) Process() (models.Stats, error) {
 		relPath, err := filepath.Rel(p.rootDir, path)
 		if err != nil {
 			if p.options.Verbose {
-				fmt.Fprintf(os.Stderr, "Error getting relative path for %s: %v\n", path, err)
+				p.errorf("Error getting relative path for %s: %v\n", path, err)
 			}
 			p.statistics.Errors++
+			p.record(path, models.ActionError, "", err)
+			return p.stopOnError(path, err)
+		}
+
+		// Skip known binary formats without opening them
+		if p.hasBinaryExtension(d.Name()) {
+			if p.options.Verbose {
+				p.printf("Skipping binary file: %s (known binary extension)\n", path)
+			}
+			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, "known binary extension", nil)
 			return nil
 		}
 
-		// Skip files based on extension
-		ext := strings.ToLower(filepath.Ext(path))
-		if _, ok := p.fileTypes[ext]; !ok {
+		// Skip files based on extension, or the interpreter of executable
+		// scripts, or with AutoDetect their content
+		_, known := p.lookupFileType(relPath)
+		if !known && !p.isExecutableEntry(path, d) && !p.isDetectedEntry(path, relPath) {
+			reason := "unsupported file type"
+			if isDocFile(relPath) {
+				reason = reasonDocs
+			}
+			if p.options.Verbose && reason == reasonDocs {
+				p.printf("Skipping documentation file: %s (use --include-docs to process it)\n", path)
+			} else if p.options.Verbose {
+				p.printf("Skipping unsupported file type: %s\n", path)
+			}
+			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, reason, nil)
+			return nil
+		}
+
+		// Skip files outside the selected languages. Those of scripts and
+		// detected files were checked along with their content.
+		if keys := p.languageKeys(relPath); known && len(keys) > 0 && !p.languageSelected(keys...) {
 			if p.options.Verbose {
-				fmt.Printf("Skipping unsupported file type: %s\n", path)
+				p.printf("Skipping excluded language: %s\n", path)
 			}
 			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, reasonExcludedLanguage, nil)
 			return nil
 		}
 
-		// Process the file
-		p.statistics.Processed++
-		updated, err := p.processFile(path, relPath)
-		if err != nil {
+		// Files that predate the CreatedAfter cutoff are left as they are
+		if before != nil && before[p.pathKey(filepath.ToSlash(relPath))] {
 			if p.options.Verbose {
-				fmt.Fprintf(os.Stderr, "Error processing file %s: %v\n", path, err)
+				p.printf("Skipping file created before %s: %s\n", p.createdAfter(), path)
 			}
-			p.statistics.Errors++
-		} else if updated {
-			p.statistics.Updated++
-		} else {
 			p.statistics.Skipped++
+			p.record(path, models.ActionSkipped, reasonPredates, nil)
+			return nil
 		}
 
-		return nil
-	})
+		// Process the file
+		if p.options.OnFileStart != nil {
+			p.options.OnFileStart(filepath.ToSlash(relPath))
+		}
+		if p.counting {
+			return nil
+		}
+		if pool != nil {
+			return pool.submit(p, path, relPath)
+		}
+		return p.recordFile(p.runFile(path, relPath))
+	}
 
+	if files != nil {
+		err = p.walkFiles(files, walkFn)
+	} else {
+		err = p.walkDir(p.rootDir, walkFn)
+	}
+	if pool != nil {
+		// Files already handed to a worker are recorded even if the walk failed
+		if poolErr := pool.finish(p); err == nil {
+			err = poolErr
+		}
+	}
+	if err == nil && !p.options.ListOnly && !p.planning {
+		err = p.runPostRunHook()
+	}
+	if err == nil && len(p.failures) > 0 {
+		err = p.failures
+	}
 	return p.statistics, err
 }
 
-// processFile adds or updates the file header comment
-func (p *Processor) processFile(filePath, relPath string) (bool, error) {
-	// Check if file is binary
-	if isBinaryFile(filePath) {
+// fileOutcome is what processing an eligible file produced
+type fileOutcome struct {
+	path      string
+	relPath   string
+	change    string // As returned by updateFile
+	movedFrom string
+	diff      string
+	err       error
+	stats     models.Stats  // Counted by a worker while it processed the file
+	output    []outputChunk // Lines a worker printed about the file
+}
+
+// runFile checks an eligible file, or updates it unless ListOnly is set
+func (p *Processor) runFile(path, relPath string) fileOutcome {
+	outcome := fileOutcome{path: path, relPath: relPath}
+	if p.options.ListOnly {
+		_, _, outcome.err = p.checkFile(path, relPath)
+	} else {
+		outcome.change, outcome.movedFrom, outcome.diff, outcome.err = p.updateFile(path, relPath)
+	}
+	return outcome
+}
+
+// recordFile counts and records the outcome of an eligible file, returning
+// the error that ends the run, if any
+func (p *Processor) recordFile(outcome fileOutcome) error {
+	p.statistics.Add(outcome.stats)
+	p.output.chunks = append(p.output.chunks, outcome.output...)
+	path, err := outcome.path, outcome.err
+
+	p.statistics.Processed++
+	var skip skipReason
+	if errors.Is(err, errInvalidFileName) {
+		// The "fail" policy aborts the run
+		p.statistics.Errors++
+		p.record(path, models.ActionError, "", err)
+		return err
+	} else if errors.As(err, &skip) {
+		p.statistics.Skipped++
+		p.record(path, models.ActionSkipped, string(skip), nil)
+	} else if err != nil {
 		if p.options.Verbose {
-			fmt.Printf("Skipping binary file: %s\n", filePath)
+			p.errorf("Error processing file %s: %v\n", path, err)
+		}
+		p.statistics.Errors++
+		if errors.Is(err, errValidationFailed) {
+			p.statistics.Reverted++
 		}
-		return false, nil
+		p.record(path, models.ActionError, "", err)
+		return p.stopOnError(outcome.relPath, err)
+	} else if p.options.ListOnly {
+		p.record(path, models.ActionListed, "", nil)
+	} else if outcome.change != "" {
+		p.statistics.Updated++
+		countChange(&p.statistics, outcome.change)
+		result := p.newResult(path, models.ActionUpdated, outcome.change, nil)
+		result.OldPath = outcome.movedFrom
+		result.Diff = outcome.diff
+		p.addResult(result, nil)
+	} else {
+		p.statistics.Skipped++
+		p.record(path, models.ActionUnchanged, "", nil)
 	}
+	return nil
+}
 
-	// Normalize path separators for comments
-	relPath = filepath.ToSlash(relPath)
+// processFile adds or updates the file header comment
+func (p *Processor) processFile(filePath, relPath string) (bool, error) {
+	change, _, _, err := p.updateFile(filePath, relPath)
+	return change != "", err
+}
 
-	// Get file extension and comment style
-	ext := strings.ToLower(filepath.Ext(filePath))
-	commentStyle, ok := p.fileTypes[ext]
-	if !ok {
-		return false, fmt.Errorf("unsupported file type: %s", ext)
+// updateFile implements processFile, returning models.ReasonMissingHeader or
+// models.ReasonStaleHeader for a file that was updated, or "" if it was not,
+// the path its stale header named, if another one, and the diff of the
+// change when Diffs is set
+func (p *Processor) updateFile(filePath, relPath string) (string, string, string, error) {
+	// The per-file hook gets the path as it exists on disk
+	diskPath := relPath
+	relPath, encoding, err := p.checkFile(filePath, relPath)
+	if err != nil {
+		return "", "", "", err
 	}
 
 	// Read file
-	content, err := os.ReadFile(filePath)
+	content, err := p.readFile(filePath)
 	if err != nil {
-		return false, err
+		return "", "", "", err
 	}
 
-	var newContent []byte
-	var updated bool
-
-	// Format the comment
-	var commentText string
-	commentPrefix := p.config.CommentPrefix
-	filePathComment := fmt.Sprintf("%s%s", commentPrefix, relPath)
-
-	if commentStyle.Preferred == "line" && commentStyle.LineComment != "" {
-		commentText = fmt.Sprintf("%s %s\n", commentStyle.LineComment, filePathComment)
-	} else if commentStyle.BlockCommentStart != "" && commentStyle.BlockCommentEnd != "" {
-		commentText = fmt.Sprintf("%s %s %s\n", commentStyle.BlockCommentStart, filePathComment, commentStyle.BlockCommentEnd)
-	} else {
-		return false, fmt.Errorf("no valid comment style for file type: %s", ext)
-	}
-
-	// Check if file already has a comment header and update it if necessary
-	scanner := bufio.NewScanner(bytes.NewReader(content))
-	if scanner.Scan() {
-		firstLine := scanner.Text()
-		
-		// Check if there's already a file path comment
-		if strings.Contains(firstLine, commentStyle.LineComment) && strings.Contains(firstLine, commentPrefix) ||
-			(strings.Contains(firstLine, commentStyle.BlockCommentStart) && strings.Contains(firstLine, commentPrefix)) {
-			
-			// Replace the existing comment
-			newContent = bytes.Replace(content, []byte(firstLine+"\n"), []byte(commentText), 1)
-			updated = true
-		} else {
-			// Add comment to the beginning
-			newContent = append([]byte(commentText), content...)
-			updated = true
+	// Wide encodings are edited as UTF-8 and written back as they were
+	text, err := p.decodeContent(filePath, content, encoding)
+	if err != nil {
+		return "", "", "", err
+	}
+	newText, prior, err := p.fixContent(relPath, text)
+	var skip skipReason
+	if errors.As(err, &skip) {
+		if p.options.Verbose {
+			p.printf("Skipping %s (%s)\n", filePath, skip)
 		}
-	} else {
-		// Empty file, just add the comment
-		newContent = []byte(commentText)
-		updated = true
+		return "", "", "", err
+	} else if err != nil {
+		return "", "", "", err
+	}
+	newContent := newText
+	if encoding != "" {
+		newContent = encodeText(newText, encoding)
 	}
+	updated := !bytes.Equal(newContent, content)
 
 	// Write back if updated
 	if updated && !p.options.DryRun {
-		err = os.WriteFile(filePath, newContent, 0644)
+		if err := p.backupFile(filePath, diskPath, content, newContent); err != nil {
+			return "", "", "", err
+		}
+		err = p.writeFile(filePath, newContent)
 		if err != nil {
-			return false, err
+			return "", "", "", err
+		}
+
+		// Restore the original if the validator rejects the change
+		if err := p.validateFile(diskPath); err != nil {
+			if restoreErr := p.writeFile(filePath, content); restoreErr != nil {
+				return "", "", "", fmt.Errorf("%v; restoring the original failed: %w", err, restoreErr)
+			}
+			return "", "", "", err
+		}
+		if err := p.runPerFileHook(diskPath); err != nil {
+			return "", "", "", err
 		}
 	}
 
 	if p.options.Verbose {
 		if updated {
 			if p.options.DryRun {
-				fmt.Printf("Would update: %s\n", filePath)
+				p.printf("Would update: %s\n", filePath)
 			} else {
-				fmt.Printf("Updated: %s\n", filePath)
+				p.printf("Updated: %s\n", filePath)
 			}
 		} else {
-			fmt.Printf("No changes needed: %s\n", filePath)
+			p.printf("No changes needed: %s\n", filePath)
+		}
+	}
+
+	var diff string
+	if updated {
+		countDiffSize(&p.statistics, text, newText)
+		diff = p.changeDiff(relPath, text, newText)
+	}
+	return p.headerChange(updated, prior.found), prior.movedFrom, diff, nil
+}
+
+// backupFile saves a file's original content in the run's backup, if there
+// is one, before it is replaced
+func (p *Processor) backupFile(filePath, relPath string, content, newContent []byte) error {
+	if p.backup == nil {
+		return nil
+	}
+	info, err := p.stat(filePath)
+	if err != nil {
+		return err
+	}
+	return p.backup.Save(relPath, info.Mode(), content, newContent)
+}
+
+// reasonNoHeader is why MovedOnly skips files without a header
+const reasonNoHeader = "no header"
+
+// priorHeader describes the header a file had before it was fixed
+type priorHeader struct {
+	found     bool
+	movedFrom string // The path it named, when that is not the file's path
+}
+
+// headerChange returns the reason recorded for a file whose content was
+// updated, depending on whether it had a header already
+func (p *Processor) headerChange(updated, stale bool) string {
+	switch {
+	case !updated:
+		return ""
+	case p.options.Remove:
+		return models.ReasonRemovedHeader
+	case stale:
+		return models.ReasonStaleHeader
+	}
+	return models.ReasonMissingHeader
+}
+
+// countChange counts an updated file as missing, stale or removed in stats
+func countChange(stats *models.Stats, change string) {
+	switch change {
+	case models.ReasonStaleHeader:
+		stats.Stale++
+	case models.ReasonRemovedHeader:
+		stats.Removed++
+	default:
+		stats.Missing++
+	}
+}
+
+// checkFile applies the checks that need only a sample of the file's
+// content and returns the path to put in its header and the file's wide
+// text encoding, if it has one. Ineligible files return a skipReason.
+func (p *Processor) checkFile(filePath, relPath string) (string, string, error) {
+	// Check if file is binary
+	verdict := p.classifyFile(filePath)
+	if !verdict.Text {
+		if p.options.Verbose {
+			p.printf("Skipping binary file: %s (%s)\n", filePath, verdict.Reason)
+		}
+		return "", "", skipReason("binary file: " + verdict.Reason)
+	}
+
+	// Apply the policy for file names that are not valid UTF-8
+	headerPath, err := p.encodeHeaderPath(relPath)
+	if errors.Is(err, errSkipFileName) {
+		if p.options.Verbose {
+			p.printf("Skipping file with non-UTF-8 name: %q\n", filePath)
+		}
+		return "", "", skipReason(reasonInvalidName)
+	}
+	return headerPath, verdict.Encoding, err
+}
+
+// decodeContent converts content in a wide encoding to UTF-8, returning a
+// skipReason if it would not convert back unchanged. Content without an
+// encoding is returned as it is.
+func (p *Processor) decodeContent(name string, content []byte, encoding string) ([]byte, error) {
+	if encoding == "" {
+		return content, nil
+	}
+	text, err := decodeText(content, encoding)
+	if err != nil {
+		if p.options.Verbose {
+			p.printf("Skipping %s text file: %s (%v)\n", encoding, name, err)
+		}
+		return nil, skipReason(reasonEncoding + encoding)
+	}
+	return text, nil
+}
+
+// fixContent returns content with the header for relPath added or updated,
+// or removed with the Remove option, and the header content already had,
+// which is stale if it changed
+func (p *Processor) fixContent(relPath string, content []byte) ([]byte, priorHeader, error) {
+	// Normalize path separators and Unicode form for comments
+	relPath, err := p.normalizeHeaderPath(filepath.ToSlash(relPath))
+	if err != nil {
+		return nil, priorHeader{}, err
+	}
+
+	// Get file extension and comment style
+	ext := strings.ToLower(filepath.Ext(relPath))
+	commentStyle, ok := p.lookupFileType(relPath)
+	if !ok {
+		commentStyle, ok = p.shebangFileType(relPath, content)
+	}
+	if !ok {
+		commentStyle, ok = p.detectedFileType(relPath, content)
+	}
+	if !ok {
+		return nil, priorHeader{}, fmt.Errorf("unsupported file type: %s", ext)
+	}
+	commentStyle = applyDialect(content, commentStyle)
+
+	var newContent []byte
+	var prior priorHeader
+	switch {
+	case p.usesFrontmatter(content, commentStyle):
+		newContent, prior, err = p.frontmatterHeader(relPath, content, commentStyle)
+		if err != nil {
+			return nil, priorHeader{}, err
+		}
+	case p.options.Remove:
+		newContent, prior.found = p.removeHeader(relPath, content, commentStyle)
+	default:
+		newContent, prior, err = p.addHeader(relPath, content, commentStyle)
+		if err != nil {
+			return nil, priorHeader{}, err
+		}
+	}
+
+	// Never leave a Go file broken by the header
+	if ext == ".go" && !bytes.Equal(newContent, content) {
+		if err := checkGoEdit(relPath, content, newContent, p.config.GoFormatCheck); err != nil {
+			return nil, priorHeader{}, err
+		}
+	}
+	return newContent, prior, nil
+}
+
+// addHeader returns content with the header for relPath added or updated,
+// and the header it had already. With MovedOnly, only a header naming
+// another path is updated, and a file without one is skipped.
+func (p *Processor) addHeader(relPath string, content []byte, commentStyle models.CommentStyle) ([]byte, priorHeader, error) {
+	ext := strings.ToLower(filepath.Ext(relPath))
+	commentStyle = p.chooseCommentKind(content, commentStyle)
+
+	// Format the comment, recognizing headers written with any prefix
+	commentPrefix := p.commentPrefix(relPath)
+	prefixes := p.headerPrefixes(commentPrefix)
+	text, dated, err := p.headerText(commentPrefix, relPath)
+	if err != nil {
+		return nil, priorHeader{}, err
+	}
+	commentText, err := renderHeader(commentStyle, text)
+	if err != nil {
+		return nil, priorHeader{}, fmt.Errorf("%w: %s", err, ext)
+	}
+
+	// Let the script and plugins veto or rewrite the proposal
+	commentText, err = p.runScript(relPath, commentText)
+	if err != nil {
+		return nil, priorHeader{}, err
+	}
+	commentStyle, commentText, err = p.runPlugins(relPath, commentStyle, commentText)
+	if err != nil {
+		return nil, priorHeader{}, err
+	}
+
+	// Find where the header belongs and check for an existing one there
+	offset := findInsertionPoint(content, commentStyle)
+	before, rest := content[:offset:offset], content[offset:]
+
+	// A header above that point, written before the placement of the file
+	// type was handled, such as one above <?php, is moved down
+	var moved string
+	if start := findInsertionPoint(content, models.CommentStyle{}); start < offset {
+		if above := existingHeaderLen(content[start:offset], commentStyle, prefixes...); above > 0 {
+			before = append(content[:start:start], content[start+above:offset]...)
+			moved = string(content[start : start+above])
 		}
 	}
 
-	return updated, nil
+	// With AfterLicense the header goes below a license banner; one above
+	// it, written before the option was set, is moved down. The SPDX tag
+	// pathfix writes below the header is not a banner.
+	bannerAt := len(before)
+	if p.config.AfterLicense {
+		above := existingHeaderLen(rest, commentStyle, prefixes...)
+		banner := licenseBannerLen(rest[above:], commentStyle, prefixes...)
+		if p.config.SPDXLicense != "" && banner == spdxLineLen(rest[above:], commentStyle) {
+			banner = 0
+		}
+		if banner > 0 {
+			before = append(before, rest[above:above+banner]...)
+			if above > 0 {
+				moved = string(rest[:above])
+			}
+			rest = rest[above+banner:]
+		}
+	}
+
+	// Replace the existing comment, if any, updating only the path field of
+	// a header block with other lines such as the author
+	existing := existingHeaderLen(rest, commentStyle, prefixes...)
+	if block := headerBlockLen(rest, commentStyle, prefixes...); block > 0 {
+		existing = block
+		commentText = mergeHeader(string(rest[:block]), commentText, commentStyle, prefixes...)
+	}
+	if existing > 0 && (p.sameHeader(string(rest[:existing]), commentText) ||
+		p.sameButDates(string(rest[:existing]), commentText, dated, commentStyle, prefixes...)) {
+		// Headers differing only in Unicode normalization (or case, on
+		// case-insensitive file systems) or in their dates are current
+		commentText = string(rest[:existing])
+	}
+	prior := priorHeader{found: existing > 0 || moved != ""}
+	oldPath := headerField(string(rest[:existing]), commentStyle, prefixes...)
+	if existing == 0 {
+		oldPath = headerField(moved, commentStyle, prefixes...)
+	}
+	newPath := headerField(commentText, commentStyle, prefixes...)
+	if oldPath != "" && newPath != "" && oldPath != newPath {
+		prior.movedFrom = oldPath
+	}
+	if p.options.MovedOnly && !prior.found {
+		return nil, priorHeader{}, skipReason(reasonNoHeader)
+	}
+	if p.options.MovedOnly && prior.movedFrom == "" {
+		return content, prior, nil
+	}
+	rest = rest[existing:]
+	if p.notice != nil {
+		before, commentText, rest, err = p.setNotice(relPath, commentPrefix, before, bannerAt, commentText, rest, commentStyle, prefixes...)
+		if err != nil {
+			return nil, priorHeader{}, err
+		}
+	}
+	if p.config.SPDXLicense != "" {
+		before, commentText, rest = setLicenseID(before, commentText, rest, commentStyle, p.config.SPDXLicense, prefixes...)
+	}
+
+	// A shebang or directive on the last line of the file needs its line
+	// ended before the header follows it
+	if len(before) > 0 && !bytes.HasSuffix(before, []byte("\n")) && !bytes.Equal(before, utf8BOM) {
+		before = append(before[:len(before):len(before)], lineEnding(content)...)
+	}
+
+	newContent := make([]byte, 0, len(content)+len(commentText))
+	newContent = append(newContent, before...)
+	newContent = append(newContent, commentText...)
+	newContent = append(newContent, dialectSeparator(rest, commentStyle)...)
+	newContent = append(newContent, rest...)
+	return newContent, prior, nil
 }
 
-// isBinaryFile checks if a file is likely to be binary
-func isBinaryFile(path string) bool {
-	// Open file
-	file, err := os.Open(path)
+// FixBuffer returns content, the unsaved contents of the file at relPath,
+// with its header added or updated. Nothing is read from or written to the
+// file except .gitignore; the result reports whether the buffer changed or
+// why it was left alone, applying the same rules as Process.
+func (p *Processor) FixBuffer(relPath string, content []byte) ([]byte, models.FileResult) {
+	fixed, result := p.fixBuffer(relPath, content, true)
+	// No outcome is recorded for a buffer, so its verbose lines are never written
+	p.output.chunks = nil
+	return fixed, result
+}
+
+// fixBuffer implements FixBuffer. Content that does not come from the root
+// directory, such as archive entries, is not subject to its .gitignore.
+func (p *Processor) fixBuffer(relPath string, content []byte, useGitIgnore bool) ([]byte, models.FileResult) {
+	relPath = filepath.ToSlash(filepath.Clean(relPath))
+	result := models.FileResult{Path: relPath, Action: models.ActionSkipped}
+	if relPath == ".." || strings.HasPrefix(relPath, "../") || filepath.IsAbs(relPath) {
+		result.Action = models.ActionError
+		result.Error = "path is outside the root directory"
+		return content, result
+	}
+
+	// Path-based rules, as applied while walking
+	if p.isProtected(relPath) {
+		result.Reason = reasonProtected
+		return content, result
+	}
+	if !p.options.IncludeHidden {
+		for _, name := range strings.Split(relPath, "/") {
+			if isHidden(name) {
+				result.Reason = "hidden file"
+				return content, result
+			}
+		}
+	}
+	if useGitIgnore && !p.config.IncludeGitIgnored {
+		gitignore, err := p.bufferGitIgnore()
+		if err != nil {
+			result.Action = models.ActionError
+			result.Error = err.Error()
+			return content, result
+		}
+		if gitignore.ShouldIgnore(filepath.Join(p.rootDir, filepath.FromSlash(relPath))) {
+			result.Reason = "gitignored file"
+			return content, result
+		}
+	}
+	if p.configIgnores().ShouldIgnore(filepath.Join(p.rootDir, filepath.FromSlash(relPath))) {
+		result.Reason = "ignored by config"
+		return content, result
+	}
+	if p.hasBinaryExtension(path.Base(relPath)) {
+		result.Reason = "known binary extension"
+		return content, result
+	}
+	_, known := p.lookupFileType(relPath)
+	if _, detected := p.detectedFileType(relPath, content); !known && !detected && !(useGitIgnore && p.isExecutableBuffer(relPath, content)) {
+		result.Reason = "unsupported file type"
+		if isDocFile(relPath) {
+			result.Reason = reasonDocs
+		}
+		return content, result
+	}
+	if keys := p.languageKeys(relPath); known && len(keys) > 0 && !p.languageSelected(keys...) {
+		result.Reason = reasonExcludedLanguage
+		return content, result
+	}
+
+	// Content-based rules
+	verdict := p.classifyContent(relPath, content)
+	if !verdict.Text {
+		result.Reason = "binary file: " + verdict.Reason
+		return content, result
+	}
+	text, err := p.decodeContent(relPath, content, verdict.Encoding)
 	if err != nil {
-		return false
+		result.Reason = reasonEncoding + verdict.Encoding
+		return content, result
 	}
-	defer file.Close()
 
-	// Read first 512 bytes
-	buf := make([]byte, 512)
-	n, err := file.Read(buf)
-	if err != nil && err != io.EOF {
-		return false
+	headerPath, err := p.encodeHeaderPath(relPath)
+	if errors.Is(err, errSkipFileName) {
+		result.Reason = reasonInvalidName
+		return content, result
 	}
-	buf = buf[:n]
+	if err == nil {
+		var newContent []byte
+		var prior priorHeader
+		if newContent, prior, err = p.fixContent(headerPath, text); err == nil {
+			if verdict.Encoding != "" {
+				newContent = encodeText(newContent, verdict.Encoding)
+			}
+			result.Action = models.ActionUnchanged
+			if change := p.headerChange(!bytes.Equal(newContent, content), prior.found); change != "" {
+				result.Action = models.ActionUpdated
+				result.Reason = change
+				result.OldPath = prior.movedFrom
+			}
+			return newContent, result
+		}
+	}
+	var skip skipReason
+	if errors.As(err, &skip) {
+		result.Reason = string(skip)
+		return content, result
+	}
+	result.Action = models.ActionError
+	result.Error = err.Error()
+	return content, result
+}
+
+// Results returns the outcome of every file visited by the latest run
+func (p *Processor) Results() []models.FileResult {
+	return p.results
+}
+
+// FileError is a file that could not be processed
+type FileError struct {
+	Path string // Path relative to the root directory, with forward slashes
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// FileErrors lists the files that could not be processed during a run that
+// otherwise completed
+type FileErrors []*FileError
+
+func (e FileErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d files could not be processed; first: %v", len(e), e[0])
+}
+
+// Unwrap lets errors.Is and errors.As match the individual failures
+func (e FileErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ErrStopped is wrapped by the error returned when FailFast stops a run at
+// the first file that failed
+var ErrStopped = errors.New("stopped at first error")
+
+// canceled returns the error that ends a run once ctx is done, or nil
+func canceled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("run canceled: %w", err)
+	}
+	return nil
+}
 
-	// Check for null bytes which would indicate binary
-	return bytes.IndexByte(buf, 0) != -1
+// stopOnError returns the error that ends a FailFast run after a file
+// failed, or nil to carry on
+func (p *Processor) stopOnError(path string, err error) error {
+	if !p.options.FailFast {
+		return nil
+	}
+	p.stopped = true
+	return fmt.Errorf("%w: %s: %v", ErrStopped, filepath.ToSlash(path), err)
+}
+
+// skipReason is returned by processFile for files it deliberately leaves alone
+type skipReason string
+
+func (r skipReason) Error() string {
+	return string(r)
+}
+
+// record stores the outcome for a file and reports it to the callbacks
+func (p *Processor) record(path, action, reason string, err error) {
+	result := p.newResult(path, action, reason, err)
+	if action == models.ActionError {
+		p.failures = append(p.failures, &FileError{Path: result.Path, Err: err})
+	}
+	p.addResult(result, err)
+}
+
+// newResult returns the outcome for the file at path
+func (p *Processor) newResult(path, action, reason string, err error) models.FileResult {
+	relPath, relErr := filepath.Rel(p.rootDir, path)
+	if relErr != nil {
+		relPath = path
+	}
+	result := models.FileResult{
+		Path:   filepath.ToSlash(relPath),
+		Action: action,
+		Reason: reason,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// addResult stores a file's outcome, classifying warnings, and reports it
+// to the callbacks. err is the failure of a file whose action is an error.
+func (p *Processor) addResult(result models.FileResult, err error) {
+	if result.Warning = p.warning(result); result.Warning != "" {
+		p.statistics.Warnings++
+		if p.options.Verbose {
+			p.errorf("Warning: %s: %s\n", result.Path, result.Warning)
+		}
+	}
+	p.results = append(p.results, result)
+	p.flushOutput()
+	p.logEvent(models.LogEntry{Event: models.LogFile, File: &result})
+	if p.options.OnResult != nil {
+		p.options.OnResult(result)
+	}
+
+	switch {
+	case result.Action == models.ActionUpdated && p.options.OnFileUpdated != nil:
+		p.options.OnFileUpdated(result)
+	case result.Action == models.ActionSkipped && p.options.OnFileSkipped != nil:
+		p.options.OnFileSkipped(result.Path, result.Reason)
+	case result.Action == models.ActionError && p.options.OnError != nil:
+		p.options.OnError(result.Path, err)
+	}
 }
 
 // isHidden checks if a file or directory is hidden
 func isHidden(name string) bool {
 	return strings.HasPrefix(name, ".")
-}
\ No newline at end of file
+}
+
+// isHiddenPath checks the leading-dot convention and, where the platform
+// has one, the file system's hidden attribute
+func isHiddenPath(path, name string) bool {
+	return isHidden(name) || hasHiddenAttribute(path)
+}
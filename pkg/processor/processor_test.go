@@ -2,9 +2,16 @@
 package processor
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+
+	"github.com/yourusername/pathfix/pkg/models"
 )
 
 func TestCommentStyles(t *testing.T) {
@@ -20,9 +27,61 @@ func TestCommentStyles(t *testing.T) {
 		{".go", "line", "//", true},
 		{".cs", "line", "//", true},
 		{".js", "line", "//", true},
+		{".vb", "line", "'", true},
+		{".vbs", "line", "'", true},
+		{".bas", "line", "'", true},
+		{".bat", "line", "REM", true},
+		{".cmd", "line", "REM", true},
+		{".psm1", "line", "#", true},
+		{".psd1", "line", "#", true},
+		{".fs", "line", "//", true},
+		{".fsx", "line", "//", true},
+		{".sv", "line", "//", true},
+		{".vhd", "line", "--", true},
 		{".py", "line", "#", true},
+		{".zsh", "line", "#", true},
+		{".fish", "line", "#", true},
+		{".service", "line", "#", true},
+		{".desktop", "line", "#", true},
+		{".gitconfig", "line", "#", true},
+		{".tf", "line", "#", true},
+		{".tfvars", "line", "#", true},
+		{".hcl", "line", "#", true},
+		{".proto", "line", "//", true},
+		{".graphql", "line", "#", true},
+		{".gql", "line", "#", true},
+		{".htaccess", "line", "#", true},
 		{".html", "block", "", true},
+		{".css", "block", "", true},
+		{".scss", "line", "//", true},
+		{".sass", "line", "//", true},
+		{".less", "line", "//", true},
+		{".styl", "line", "//", true},
 		{".yml", "line", "#", true},
+		{".pas", "line", "//", true},
+		{".dpr", "line", "//", true},
+		{".adb", "line", "--", true},
+		{".scala", "line", "//", true},
+		{".gradle", "line", "//", true},
+		{".kts", "line", "//", true},
+		{".dart", "line", "//", true},
+		{".j2", "block", "", true},
+		{".erb", "block", "", true},
+		{".hbs", "block", "", true},
+		{".gotmpl", "block", "", true},
+		{".hs", "line", "--", true},
+		{".ml", "block", "", true},
+		{".exs", "line", "#", true},
+		{".hrl", "line", "%", true},
+		{".cljs", "line", ";;", true},
+		{".scm", "line", ";;", true},
+		{".r", "line", "#", true},
+		{".jl", "line", "#", true},
+		{".m", "line", "%", true},
+		{".sty", "line", "%", true},
+		{".f08", "line", "!", true},
+		{".nasm", "line", ";", true},
+		{".s", "block", "", true},
 		{".unknown", "", "", false},
 	}
 
@@ -192,4 +251,498 @@ func TestHiddenFileDetection(t *testing.T) {
 			t.Errorf("isHidden(%s) = %v, expected %v", test.filename, result, test.expected)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestFileTypeOverrideMerge(t *testing.T) {
+	defaults := map[string]models.CommentStyle{
+		".pas": {LineComment: "//", BlockCommentStart: "{", BlockCommentEnd: "}", Preferred: "line"},
+	}
+	config := &models.Config{
+		FileTypes: map[string]models.CommentStyle{
+			".pas": {Preferred: "block"},
+		},
+	}
+
+	merged := MergeConfig(config, defaults)
+	style := merged.FileTypes[".pas"]
+	if style.Preferred != "block" {
+		t.Errorf("Expected overridden Preferred: block, got: %s", style.Preferred)
+	}
+	if style.BlockCommentStart != "{" || style.BlockCommentEnd != "}" || style.LineComment != "//" {
+		t.Errorf("Expected comment markers inherited from defaults, got: %+v", style)
+	}
+}
+
+func TestJSONCommentPaths(t *testing.T) {
+	p := NewProcessor(".", &Options{})
+	p.config.JSONCommentPaths = []string{"tsconfig.json", ".vscode/*.json"}
+
+	tests := []struct {
+		relPath  string
+		expected bool
+	}{
+		{"settings.jsonc", true},
+		{"config.json5", true},
+		{"package.json", false},
+		{"tsconfig.json", true},
+		{"web/tsconfig.json", true},
+		{".vscode/settings.json", true},
+		{"other/.vscode/settings.json", false},
+	}
+
+	for _, test := range tests {
+		_, ok := p.lookupFileType(test.relPath)
+		if ok != test.expected {
+			t.Errorf("lookupFileType(%s) = %v, expected %v", test.relPath, ok, test.expected)
+		}
+	}
+}
+
+func TestKotlinScriptAnnotations(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "kotlin-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	scriptPath := filepath.Join(tempDir, "build.gradle.kts")
+	scriptContent := "@file:Suppress(\"UnstableApiUsage\")\n\nplugins {\n    java\n}\n"
+	if err := os.WriteFile(scriptPath, []byte(scriptContent), 0644); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	if _, err := processor.processFile(scriptPath, "build.gradle.kts"); err != nil {
+		t.Fatalf("processFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(scriptPath)
+	if err != nil {
+		t.Fatalf("Failed to read updated file: %v", err)
+	}
+	expected := "// File: build.gradle.kts\n" + scriptContent
+	if string(content) != expected {
+		t.Errorf("Unexpected content: %q, expected %q", string(content), expected)
+	}
+}
+
+func TestTemplateHeaders(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "template-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		name           string
+		expectedHeader string
+	}{
+		{"nginx.conf.j2", "{# File: nginx.conf.j2 -#}\n"},
+		{"page.twig", "{# File: page.twig #}\n"},
+		{"show.html.erb", "<%# File: show.html.erb -%>\n"},
+		{"card.hbs", "{{! File: card.hbs }}\n"},
+		{"values.tmpl", "{{/* File: values.tmpl */ -}}\n"},
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	for _, test := range tests {
+		path := filepath.Join(tempDir, test.name)
+		if err := os.WriteFile(path, []byte("body\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+		if _, err := processor.processFile(path, test.name); err != nil {
+			t.Fatalf("processFile(%s) failed: %v", test.name, err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expectedHeader+"body\n" {
+			t.Errorf("Unexpected content for %s: %q", test.name, string(content))
+		}
+	}
+}
+
+func TestIgnoreCaseHeaders(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "ignorecase-header-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	goFilePath := filepath.Join(tempDir, "Main.GO")
+	goContent := "// File: SRC/main.go\npackage main\n"
+	if err := os.WriteFile(goFilePath, []byte(goContent), 0644); err != nil {
+		t.Fatalf("Failed to write Go file: %v", err)
+	}
+
+	// Uppercase extensions are recognized either way
+	processor := NewProcessor(tempDir, &Options{})
+	if _, ok := processor.lookupFileType("Main.GO"); !ok {
+		t.Errorf("Expected Main.GO to be a supported file type")
+	}
+
+	// A header differing only in case is left alone
+	processor = NewProcessor(tempDir, &Options{IgnoreCase: true})
+	updated, err := processor.processFile(goFilePath, "src/Main.GO")
+	if err != nil {
+		t.Fatalf("processFile failed: %v", err)
+	}
+	if updated {
+		t.Errorf("Expected no update for a header differing only in case")
+	}
+
+	// Without IgnoreCase the header is rewritten
+	processor = NewProcessor(tempDir, &Options{})
+	updated, err = processor.processFile(goFilePath, "src/Main.GO")
+	if err != nil {
+		t.Fatalf("processFile failed: %v", err)
+	}
+	if !updated {
+		t.Errorf("Expected the header to be rewritten when case matters")
+	}
+}
+
+func TestUnicodeHeaderNormalization(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "unicode-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	nfc := "caf\u00e9.go"  // é as a single code point
+	nfd := "cafe\u0301.go" // e followed by a combining acute accent
+
+	goFilePath := filepath.Join(tempDir, "cafe.go")
+	if err := os.WriteFile(goFilePath, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write Go file: %v", err)
+	}
+
+	// A path reported in NFD (as on macOS) produces an NFC header
+	processor := NewProcessor(tempDir, &Options{})
+	if _, err := processor.processFile(goFilePath, nfd); err != nil {
+		t.Fatalf("processFile failed: %v", err)
+	}
+	content, err := os.ReadFile(goFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read updated file: %v", err)
+	}
+	if string(content) != "// File: "+nfc+"\npackage main\n" {
+		t.Errorf("Expected an NFC header, got: %q", string(content))
+	}
+
+	// An existing NFD header is equivalent and left alone
+	nfdContent := "// File: " + nfd + "\npackage main\n"
+	if err := os.WriteFile(goFilePath, []byte(nfdContent), 0644); err != nil {
+		t.Fatalf("Failed to write Go file: %v", err)
+	}
+	updated, err := processor.processFile(goFilePath, nfc)
+	if err != nil {
+		t.Fatalf("processFile failed: %v", err)
+	}
+	if updated {
+		t.Errorf("Expected an equivalent NFD header not to be rewritten")
+	}
+}
+
+func TestInvalidFileNamePolicy(t *testing.T) {
+	// Create a temporary directory
+	tempDir, err := os.MkdirTemp("", "invalid-name-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Latin-1 encoded "café%.go", as left behind by old archives
+	name := "caf\xe9%.go"
+	goFilePath := filepath.Join(tempDir, name)
+	if err := os.WriteFile(goFilePath, []byte("package main\n"), 0644); err != nil {
+		t.Skipf("File system does not accept non-UTF-8 names: %v", err)
+	}
+
+	// Skipped by default
+	stats, err := NewProcessor(tempDir, &Options{}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Updated != 0 {
+		t.Errorf("Expected the file to be skipped, got %d updates", stats.Updated)
+	}
+
+	// Rejected with "fail"
+	processor := NewProcessor(tempDir, &Options{})
+	processor.config.InvalidPathPolicy = "fail"
+	if _, err := processor.Process(); err == nil {
+		t.Errorf("Expected an error with the fail policy")
+	}
+
+	// Percent-encoded with "escape"
+	processor = NewProcessor(tempDir, &Options{})
+	processor.config.InvalidPathPolicy = "escape"
+	if _, err := processor.Process(); err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	content, err := os.ReadFile(goFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read updated file: %v", err)
+	}
+	if string(content) != "// File: caf%E9%25.go\npackage main\n" {
+		t.Errorf("Unexpected content: %q", string(content))
+	}
+}
+
+func TestListOnly(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-list-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"main.go":   "package main\n",
+		"data.bin":  "\x00\x01\x02",
+		"notes.txt": "notes\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	processor := NewProcessor(tempDir, &Options{ListOnly: true})
+	if _, err := processor.Process(); err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+
+	var listed []string
+	for _, result := range processor.Results() {
+		if result.Action == models.ActionListed {
+			listed = append(listed, result.Path)
+		}
+	}
+	if len(listed) != 1 || listed[0] != "main.go" {
+		t.Errorf("Process() listed %v, expected [main.go]", listed)
+	}
+
+	// Nothing is written
+	content, _ := os.ReadFile(filepath.Join(tempDir, "main.go"))
+	if string(content) != "package main\n" {
+		t.Errorf("Process() with ListOnly modified main.go: %q", content)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-failfast-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	t.Setenv("PATHFIX_VALIDATOR_HELPER", "1")
+
+	// Both files are rejected by the validator
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("package main // reject\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	validators := map[string][]string{
+		"go": {os.Args[0], "-test.run=^TestValidatorHelperProcess$", "--", "{file}"},
+	}
+
+	for _, failFast := range []bool{false, true} {
+		processor := NewProcessor(tempDir, &Options{FailFast: failFast})
+		processor.config.Validators = validators
+		stats, err := processor.Process()
+
+		expectedErrors := 2
+		if failFast {
+			expectedErrors = 1
+		}
+		if stats.Errors != expectedErrors {
+			t.Errorf("Process() with FailFast=%v reported %d errors, expected %d", failFast, stats.Errors, expectedErrors)
+		}
+		if errors.Is(err, ErrStopped) != failFast || processor.Report().Stopped != failFast {
+			t.Errorf("Process() with FailFast=%v returned %v, stopped = %v", failFast, err, processor.Report().Stopped)
+		}
+
+		// Without FailFast every failure is returned
+		var fileErrors FileErrors
+		if !failFast && (!errors.As(err, &fileErrors) || len(fileErrors) != 2) {
+			t.Errorf("Process() returned %v, expected both files to be listed", err)
+		}
+	}
+}
+
+func TestReuseProcessor(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-reuse-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	mainPath := filepath.Join(tempDir, "main.go")
+	if err := os.WriteFile(mainPath, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+	configPath := filepath.Join(tempDir, "pathfix.json")
+	if err := os.WriteFile(configPath, []byte(`{"AdditionalIgnores": ["pathfix.json"]}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	processor := NewProcessor(tempDir, &Options{ConfigFile: configPath})
+	if stats, err := processor.Process(); err != nil || stats.Updated != 1 {
+		t.Fatalf("first Process() = %+v, %v, expected 1 update", stats, err)
+	}
+
+	// The second run starts from fresh statistics
+	stats, err := processor.Process()
+	if err != nil || stats.Updated != 0 || len(processor.Results()) != 2 {
+		t.Errorf("second Process() = %+v with %d results, %v, expected no updates and 2 results", stats, len(processor.Results()), err)
+	}
+
+	processor.ResetStats()
+	if stats := processor.Report().Stats; stats.Processed != 0 || len(processor.Results()) != 0 {
+		t.Errorf("ResetStats() left %+v and %d results", stats, len(processor.Results()))
+	}
+
+	// A changed prefix applies after a reload
+	if err := os.WriteFile(configPath, []byte(`{"CommentPrefix": "Path: ", "AdditionalIgnores": ["pathfix.json"]}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := processor.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	if _, err := processor.Process(); err != nil {
+		t.Fatalf("Process after reload failed: %v", err)
+	}
+	content, _ := os.ReadFile(mainPath)
+	if !strings.HasPrefix(string(content), "// Path: main.go\n") {
+		t.Errorf("Process after reload wrote %q, expected the new prefix", content)
+	}
+
+	// A broken config is reported and the previous one kept
+	if err := os.WriteFile(configPath, []byte(`{`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := processor.ReloadConfig(); err == nil {
+		t.Errorf("ReloadConfig with a broken config succeeded, expected an error")
+	}
+	if stats, err := processor.Process(); err != nil || stats.Updated != 0 {
+		t.Errorf("Process after failed reload = %+v, %v, expected the previous config", stats, err)
+	}
+}
+
+func TestProcessContext(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-context-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for i := 0; i < 20; i++ {
+		name := filepath.Join(tempDir, fmt.Sprintf("file%02d.go", i))
+		if err := os.WriteFile(name, []byte("package a\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	// A canceled context stops the run before anything happens
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stats, err := NewProcessor(tempDir, &Options{}).ProcessContext(ctx)
+	if !errors.Is(err, context.Canceled) || stats.Processed != 0 {
+		t.Errorf("ProcessContext(canceled) = %+v, %v, expected no files and context.Canceled", stats, err)
+	}
+
+	// Canceled during the run, every file is either done or untouched
+	for _, workers := range []int{1, 4} {
+		ctx, cancel := context.WithCancel(context.Background())
+		options := &Options{
+			Workers:  workers,
+			OnResult: func(models.FileResult) { cancel() },
+		}
+		stats, err := NewProcessor(tempDir, options).ProcessContext(ctx)
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ProcessContext with %d workers returned %v, expected context.Canceled", workers, err)
+		}
+		if stats.Processed == 0 || stats.Processed >= 20 {
+			t.Errorf("ProcessContext with %d workers processed %d files, expected some of them", workers, stats.Processed)
+		}
+
+		updated := 0
+		for i := 0; i < 20; i++ {
+			name := fmt.Sprintf("file%02d.go", i)
+			content, err := os.ReadFile(filepath.Join(tempDir, name))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", name, err)
+			}
+			switch string(content) {
+			case "// File: " + name + "\npackage a\n":
+				updated++
+			case "package a\n":
+			default:
+				t.Errorf("%s after cancellation = %q, expected it unchanged or fully updated", name, content)
+			}
+		}
+		if updated < stats.Updated {
+			t.Errorf("%d files updated on disk, expected at least the %d counted", updated, stats.Updated)
+		}
+	}
+}
+
+func TestCallbacks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":  {Data: []byte("package main\n")},
+		"lib/a.py": {Data: []byte("# File: lib/a.py\nx = 1\n")},
+		"data.go":  {Data: []byte("\x00\x01\x02")},
+		"logo.png": {Data: []byte("\x89PNG\r\n")},
+	}
+	root := filepath.FromSlash("/virtual")
+
+	var started, updated, skipped, failed []string
+	options := &Options{
+		DryRun:        true,
+		FS:            fsys,
+		OnFileStart:   func(path string) { started = append(started, path) },
+		OnFileUpdated: func(result models.FileResult) { updated = append(updated, result.Path) },
+		OnFileSkipped: func(path, reason string) { skipped = append(skipped, path+": "+reason) },
+		OnError: func(path string, err error) {
+			failed = append(failed, fmt.Sprintf("%s: %v", path, errors.Is(err, errReadOnlyFS)))
+		},
+	}
+	if _, err := NewProcessor(root, options).Process(); err != nil {
+		t.Fatalf("Process() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      []string
+		expected string
+	}{
+		{"OnFileStart", started, "data.go, lib/a.py, main.go"},
+		{"OnFileUpdated", updated, "main.go"},
+		{"OnFileSkipped", skipped, "data.go: binary file: contains NUL bytes, logo.png: known binary extension"},
+		{"OnError", failed, ""},
+	}
+	for _, test := range tests {
+		if got := strings.Join(test.got, ", "); got != test.expected {
+			t.Errorf("%s was called with %q, expected %q", test.name, got, test.expected)
+		}
+	}
+
+	// Writes to a read-only FS fail, and the error is passed on as it is
+	options.DryRun = false
+	updated = nil
+	if _, err := NewProcessor(root, options).Process(); err == nil {
+		t.Errorf("Process() writing to a read-only FS succeeded")
+	}
+	if got := strings.Join(failed, ", "); len(updated) != 0 || got != "main.go: true" {
+		t.Errorf("OnError was called with %q and OnFileUpdated with %q, expected only main.go's read-only error", got, updated)
+	}
+}
@@ -0,0 +1,67 @@
+// File: pkg/processor/protected.go
+package processor
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// reasonProtected is why files matching Protected are skipped
+const reasonProtected = "protected path"
+
+// isProtected reports whether the file at relPath matches one of the
+// config's Protected patterns, which pathfix never writes to
+func (p *Processor) isProtected(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	if p.config.IgnoreCase {
+		relPath = strings.ToLower(relPath)
+	}
+	for _, pattern := range p.config.Protected {
+		if p.config.IgnoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		if matchProtected(relPath, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchProtected matches a slash-separated relative path against a
+// Protected pattern. A pattern with a slash is anchored at the root, others
+// match at any depth; "**" matches any number of directories, and a pattern
+// naming a directory covers everything beneath it.
+func matchProtected(relPath, pattern string) bool {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	patterns := append(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), "**")
+	return matchSegments(strings.Split(relPath, "/"), patterns)
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(segments, patterns []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+	if patterns[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(segments[i:], patterns[1:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(patterns[0], segments[0]); !matched {
+		return false
+	}
+	return matchSegments(segments[1:], patterns[1:])
+}
@@ -0,0 +1,85 @@
+// File: pkg/processor/protected_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestMatchProtected(t *testing.T) {
+	tests := []struct {
+		path     string
+		pattern  string
+		expected bool
+	}{
+		{"third_party/lib/a.go", "third_party/**", true},
+		{"third_party/a.go", "third_party/**", true},
+		{"src/third_party/a.go", "third_party/**", false},
+		{"src/third_party/a.go", "**/third_party/**", true},
+		{"third_party/a.go", "third_party", true},
+		{"src/third_party/a.go", "third_party/", true},
+		{"LICENSES/MIT.txt", "/LICENSES", true},
+		{"docs/LICENSES/MIT.txt", "/LICENSES", false},
+		{"gen/a.pb.go", "*.pb.go", true},
+		{"gen/a.go", "*.pb.go", false},
+		{"vendor/x/y/z.go", "vendor/*/y", true},
+		{"vendor/x/z.go", "vendor/*/y", false},
+		{"a/b/c/d.go", "a/**/d.go", true},
+		{"a/d.go", "a/**/d.go", true},
+		{"main.go", "", false},
+	}
+
+	for _, test := range tests {
+		if result := matchProtected(test.path, test.pattern); result != test.expected {
+			t.Errorf("matchProtected(%s, %q) = %v, expected %v", test.path, test.pattern, result, test.expected)
+		}
+	}
+}
+
+func TestProtectedProcessing(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "protected-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := []string{"main.go", "third_party/lib/lib.go", "Legal/notice.go"}
+	for _, name := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	config := models.NewConfig().WithProtected("third_party/**", "legal/")
+	config.IgnoreCase = true
+	config.IncludeGitIgnored = true
+	p := NewProcessor(tempDir, &Options{Config: config})
+	stats, err := p.Process()
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if stats.Updated != 1 || stats.Skipped != 2 {
+		t.Errorf("Process() = %+v, expected 1 file updated and 2 skipped", stats)
+	}
+	for _, name := range files[1:] {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != "package x\n" {
+			t.Errorf("Process() modified protected file %s: %q", name, content)
+		}
+	}
+
+	// Buffers are refused too
+	if _, result := p.FixBuffer("third_party/lib/new.go", []byte("package lib\n")); result.Reason != reasonProtected {
+		t.Errorf("FixBuffer(third_party/lib/new.go) = %s %q, expected %q", result.Action, result.Reason, reasonProtected)
+	}
+}
@@ -0,0 +1,96 @@
+// File: pkg/processor/remove.go
+package processor
+
+import (
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// removeHeader returns content without its path header, for the Remove
+// option, and whether it had one. The header is looked for where pathfix
+// writes it, and below a license banner there, after any blank lines. The
+// path line of a header
+// block is removed, and the whole block if nothing else is left in it.
+func (p *Processor) removeHeader(relPath string, content []byte, style models.CommentStyle) ([]byte, bool) {
+	prefixes := p.headerPrefixes(p.commentPrefix(relPath))
+	offset := findInsertionPoint(content, style)
+	replacement, n := headerRemoval(content[offset:], style, prefixes)
+	if n == 0 {
+		if banner := licenseBannerLen(content[offset:], style, prefixes...); banner > 0 {
+			offset += banner
+			for {
+				line, lineLen := firstLine(content[offset:])
+				if lineLen == 0 || strings.TrimSpace(line) != "" {
+					break
+				}
+				offset += lineLen
+			}
+			replacement, n = headerRemoval(content[offset:], style, prefixes)
+		}
+	}
+	if n == 0 {
+		return content, false
+	}
+
+	rest := content[offset+n:]
+	if replacement == "" && style.Dialect == "powershell" {
+		// Drop the blank line kept between a header and comment-based help
+		if blank, lineLen := firstLine(rest); strings.TrimSpace(blank) == "" && lineLen > 0 {
+			if next, _ := firstLine(rest[lineLen:]); isPowerShellLineHelp(next) {
+				rest = rest[lineLen:]
+			}
+		}
+	}
+
+	newContent := make([]byte, 0, len(content))
+	newContent = append(newContent, content[:offset]...)
+	newContent = append(newContent, replacement...)
+	newContent = append(newContent, rest...)
+	return newContent, true
+}
+
+// headerRemoval returns the length of the header at the start of rest and
+// the text that replaces it, or 0 if rest does not start with a header
+func headerRemoval(rest []byte, style models.CommentStyle, prefixes []string) (string, int) {
+	if block := headerBlockLen(rest, style, prefixes...); block > 0 {
+		return removeField(string(rest[:block]), style, prefixes), block
+	}
+	return "", existingHeaderLen(rest, style, prefixes...)
+}
+
+// removeField returns a header block without its path field. The field's
+// line goes with it unless comment markers remain on that line, and the
+// block goes too if only markers and decoration are left.
+func removeField(block string, style models.CommentStyle, prefixes []string) string {
+	span, ok := fieldSpan(block, style, prefixes...)
+	if !ok {
+		return block
+	}
+	lineStart := strings.LastIndex(block[:span[0]], "\n") + 1
+	lineEnd := len(block)
+	if i := strings.Index(block[span[1]:], "\n"); i >= 0 {
+		lineEnd = span[1] + i
+	}
+	line := strings.TrimRight(block[lineStart:span[0]]+block[span[1]:lineEnd], " \t\r")
+	if strings.Trim(line, " \t*") == "" {
+		// Remove the line and its line break
+		block = block[:lineStart] + block[min(lineEnd+1, len(block)):]
+	} else {
+		if strings.HasSuffix(block[:lineEnd], "\r") {
+			line += "\r"
+		}
+		block = block[:lineStart] + line + block[lineEnd:]
+	}
+
+	body := strings.Replace(block, strings.TrimSpace(style.BlockCommentStart), "", 1)
+	if end := strings.TrimSpace(style.BlockCommentEnd); end != "" {
+		if i := strings.LastIndex(body, end); i >= 0 {
+			body = body[:i] + body[i+len(end):]
+		}
+	}
+	if strings.Trim(body, " \t\r\n*") == "" {
+		return ""
+	}
+	return block
+}
@@ -0,0 +1,73 @@
+// File: pkg/processor/remove_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestRemoveHeader(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "remove-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"line.go", "// File: line.go\npackage a\n", "package a\n"},
+		{"stale.go", "// File: old/stale.go\npackage a\n", "package a\n"},
+		{"block.c", "/* File: block.c */\nint x;\n", "int x;\n"},
+		{"html.html", "<!-- File: html.html -->\n<p>\n", "<p>\n"},
+		{"script.sh", "#!/bin/sh\n# File: script.sh\necho\n", "#!/bin/sh\necho\n"},
+		{"bom.py", "\ufeff# File: bom.py\nx = 1\n", "\ufeffx = 1\n"},
+		{"crlf.c", "// File: crlf.c\r\nint x;\r\n", "int x;\r\n"},
+		{"author.c", "/*\n * File: author.c\n * Author: Jane\n */\nint x;\n", "/*\n * Author: Jane\n */\nint x;\n"},
+		{"first.c", "/* File: first.c\n * Author: Jane\n */\nint x;\n", "/*\n * Author: Jane\n */\nint x;\n"},
+		{"only.c", "/*\n * File: only.c\n */\nint x;\n", "int x;\n"},
+		{"license.go", "// Copyright 2024 Acme\n// SPDX-License-Identifier: MIT\n\n// File: license.go\npackage a\n", "// Copyright 2024 Acme\n// SPDX-License-Identifier: MIT\n\npackage a\n"},
+		{"none.go", "// Package a does things.\npackage a\n", "// Package a does things.\npackage a\n"},
+		{"help.ps1", "# File: help.ps1\n\n# .SYNOPSIS\n# Does things\n", "# .SYNOPSIS\n# Does things\n"},
+	}
+
+	for _, test := range tests {
+		if err := os.WriteFile(filepath.Join(tempDir, test.name), []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", test.name, err)
+		}
+	}
+
+	stats, err := NewProcessor(tempDir, &Options{Remove: true}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Removed != len(tests)-1 || stats.Updated != stats.Removed || stats.Missing+stats.Stale != 0 {
+		t.Errorf("Process() = %+v, expected %d headers removed", stats, len(tests)-1)
+	}
+
+	for _, test := range tests {
+		content, err := os.ReadFile(filepath.Join(tempDir, test.name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("removeHeader(%s) = %q, expected %q", test.name, content, test.expected)
+		}
+	}
+
+	// Removing is the inverse of adding
+	p := NewProcessor(tempDir, &Options{})
+	fixed, result := p.FixBuffer("round.go", []byte("package a\n"))
+	if result.Action != models.ActionUpdated {
+		t.Fatalf("FixBuffer(round.go) = %s, expected %s", result.Action, models.ActionUpdated)
+	}
+	p = NewProcessor(tempDir, &Options{Remove: true})
+	if removed, result := p.FixBuffer("round.go", fixed); string(removed) != "package a\n" || result.Reason != models.ReasonRemovedHeader {
+		t.Errorf("FixBuffer(round.go) with Remove = %q (%s), expected %q (%s)", removed, result.Reason, "package a\n", models.ReasonRemovedHeader)
+	}
+}
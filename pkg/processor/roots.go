@@ -0,0 +1,137 @@
+// File: pkg/processor/roots.go
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// RootConfigFile is the config file a root can carry for multi-root runs
+const RootConfigFile = ".pathfix.json"
+
+// RootConfigFiles are the names a root's config file can have, in order of
+// preference
+var RootConfigFiles = []string{RootConfigFile, ".pathfix.yaml", ".pathfix.yml"}
+
+// DiscoverConfig returns the config file found at the top of rootDir, or ""
+// if there is none
+func DiscoverConfig(rootDir string) string {
+	for _, name := range RootConfigFiles {
+		configPath := filepath.Join(rootDir, name)
+		if info, err := os.Stat(configPath); err == nil && !info.IsDir() {
+			return configPath
+		}
+	}
+	return ""
+}
+
+// FindConfig returns the config file found at the top of rootDir or, when
+// rootDir is in a git work tree, of the nearest directory above it up to
+// the top of the work tree. It returns "" if there is none.
+func FindConfig(rootDir string) string {
+	if configPath := DiscoverConfig(rootDir); configPath != "" {
+		return configPath
+	}
+	workTree, _ := findGitDir(rootDir)
+	if workTree == "" {
+		return ""
+	}
+	dir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return ""
+	}
+	for dir != workTree {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+		if configPath := DiscoverConfig(dir); configPath != "" {
+			return configPath
+		}
+	}
+	return ""
+}
+
+// ProcessRoots runs Process over several independent roots, up to parallel
+// of them at a time. Each root gets its own processor with a copy of options;
+// unless options name a config file, the root's own is found with FindConfig.
+// The callbacks and Log, if set, are called from several goroutines at once
+// when parallel is above 1. The lines printed about a file are written
+// together; with OrderedOutput, those of a root follow the roots before it.
+func ProcessRoots(roots []string, options Options, parallel int) models.MultiReport {
+	return ProcessRootsContext(context.Background(), roots, options, parallel)
+}
+
+// ProcessRootsContext is ProcessRoots with a context that cancels the runs
+// in progress, as ProcessContext does, and fails the roots not started yet
+func ProcessRootsContext(ctx context.Context, roots []string, options Options, parallel int) models.MultiReport {
+	if parallel < 1 {
+		parallel = 1
+	}
+	report := models.MultiReport{
+		DryRun: options.DryRun,
+		Roots:  make([]models.RootReport, len(roots)),
+	}
+
+	// Keep the roots' writes to the same stream apart
+	var mu sync.Mutex
+	stdout := syncWriter{mu: &mu, w: options.stdout()}
+	stderr := syncWriter{mu: &mu, w: options.stderr()}
+	var ordered *orderedOutput
+	if options.OrderedOutput {
+		ordered = newOrderedOutput(stdout, stderr, len(roots))
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallel)
+	for i, root := range roots {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, root string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			rootOptions := options
+			rootOptions.Stdout, rootOptions.Stderr = stdout, stderr
+			if ordered != nil {
+				rootOptions.Stdout, rootOptions.Stderr = ordered.writers(i)
+				defer ordered.finish(i)
+			}
+			report.Roots[i] = processRoot(ctx, root, rootOptions)
+		}(i, root)
+	}
+	wg.Wait()
+
+	for _, root := range report.Roots {
+		report.Stats.Add(root.Stats)
+	}
+	return report
+}
+
+// processRoot runs one root of ProcessRootsContext
+func processRoot(ctx context.Context, root string, options Options) models.RootReport {
+	report := models.RootReport{Root: root, ConfigFile: options.ConfigFile}
+	if report.ConfigFile == "" {
+		report.ConfigFile = FindConfig(root)
+	}
+
+	p := NewProcessor(root, &options)
+	if err := p.ConfigError(); err != nil {
+		report.Failed = true
+		report.Error = err.Error()
+		return report
+	}
+	stats, err := p.ProcessContext(ctx)
+	report.Stats = stats
+	if err != nil {
+		var fileErrors FileErrors
+		report.Failed = !errors.As(err, &fileErrors)
+		report.Error = err.Error()
+	}
+	return report
+}
@@ -0,0 +1,126 @@
+// File: pkg/processor/roots_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessRoots(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-roots-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Root b carries its own config; root c's config is invalid
+	files := map[string]string{
+		"a/main.go":           "package main\n",
+		"a/lib/lib.go":        "// File: lib/lib.go\npackage lib\n",
+		"b/main.go":           "package main\n",
+		"b/" + RootConfigFile: `{"CommentPrefix": "Path: "}`,
+		"c/main.go":           "package main\n",
+		"c/" + RootConfigFile: `{"PathNormalization": "nfkc"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	roots := []string{filepath.Join(tempDir, "a"), filepath.Join(tempDir, "b"), filepath.Join(tempDir, "c"), filepath.Join(tempDir, "missing")}
+	report := ProcessRoots(roots, Options{}, 2)
+	if len(report.Roots) != len(roots) {
+		t.Fatalf("ProcessRoots() reported %d roots, expected %d", len(report.Roots), len(roots))
+	}
+
+	testCases := []struct {
+		updated, processed int
+		failed             bool
+		configFile         string
+	}{
+		{1, 2, false, ""},
+		{1, 1, false, filepath.Join(roots[1], RootConfigFile)},
+		{0, 0, true, filepath.Join(roots[2], RootConfigFile)},
+		{0, 0, true, ""},
+	}
+	for i, tc := range testCases {
+		root := report.Roots[i]
+		if root.Root != roots[i] || root.Stats.Updated != tc.updated || root.Stats.Processed != tc.processed || root.Failed != tc.failed || root.ConfigFile != tc.configFile {
+			t.Errorf("ProcessRoots()[%d] = %+v, expected %d of %d files updated, failed %v, config %q", i, root, tc.updated, tc.processed, tc.failed, tc.configFile)
+		}
+	}
+	if report.Stats.Updated != 2 || report.Stats.Processed != 3 {
+		t.Errorf("ProcessRoots() totals = %+v, expected 2 of 3 files updated", report.Stats)
+	}
+
+	content, err := os.ReadFile(filepath.Join(roots[1], "main.go"))
+	if err != nil || string(content) != "// Path: main.go\npackage main\n" {
+		t.Errorf("ProcessRoots() wrote %q to b/main.go, expected the header from its config", content)
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-findconfig-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A repository with a YAML config at its top, inside a directory with
+	// a config of its own that must not be used
+	files := map[string]string{
+		RootConfigFile:                 `{"CommentPrefix": "Outside: "}`,
+		"repo/.git/HEAD":               "ref: refs/heads/main\n",
+		"repo/.pathfix.yaml":           "CommentPrefix: 'Path: '\nAdditionalIgnores:\n  - gen/\n",
+		"repo/service/main.go":         "package main\n",
+		"repo/service/gen/gen.go":      "package gen\n",
+		"repo/tools/" + RootConfigFile: `{"CommentPrefix": "Tool: "}`,
+		"repo/tools/.pathfix.yaml":     "CommentPrefix: 'Ignored: '\n",
+		"plain/main.go":                "package main\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		root     string
+		expected string
+	}{
+		{"repo/service", "repo/.pathfix.yaml"},
+		{"repo/service/gen", "repo/.pathfix.yaml"},
+		{"repo/tools", "repo/tools/" + RootConfigFile}, // JSON is preferred
+		{"plain", ""}, // Not in a work tree, so the parent's config is not used
+		{".", RootConfigFile},
+	}
+	for _, test := range tests {
+		expected := ""
+		if test.expected != "" {
+			expected = filepath.Join(tempDir, filepath.FromSlash(test.expected))
+		}
+		if result := FindConfig(filepath.Join(tempDir, filepath.FromSlash(test.root))); result != expected {
+			t.Errorf("FindConfig(%s) = %q, expected %q", test.root, result, expected)
+		}
+	}
+
+	// NewProcessor loads the config it finds
+	p := NewProcessor(filepath.Join(tempDir, "repo", "service"), &Options{DryRun: true})
+	if err := p.ConfigError(); err != nil {
+		t.Fatalf("NewProcessor() failed to load the config: %v", err)
+	}
+	stats, _ := p.Process()
+	if p.config.CommentPrefix != "Path: " || stats.Updated != 1 {
+		t.Errorf("NewProcessor() used prefix %q and updated %d files, expected %q and 1", p.config.CommentPrefix, stats.Updated, "Path: ")
+	}
+}
@@ -0,0 +1,143 @@
+// File: pkg/processor/script.go
+package processor
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// scriptMaxSteps bounds the work a script may do in a single call, so a
+// runaway rule cannot stall a run
+const scriptMaxSteps = 1000000
+
+// script holds the rules defined by the configured Starlark file
+type script struct {
+	include starlark.Callable // include(file) -> bool; false skips the file
+	header  starlark.Callable // header(file) -> string or None; replaces the proposed header
+}
+
+// loadScript compiles the configured script once. Scripts are sandboxed:
+// the only access they have to the file system is through read_file and
+// exists, confined to the root directory.
+func (p *Processor) loadScript() (*script, error) {
+	p.scriptOnce.Do(func() {
+		if p.config.Script == "" {
+			return
+		}
+		scriptPath := p.config.Script
+		if !filepath.IsAbs(scriptPath) {
+			scriptPath = filepath.Join(p.rootDir, scriptPath)
+		}
+
+		thread := &starlark.Thread{Name: "load " + p.config.Script}
+		thread.SetMaxExecutionSteps(scriptMaxSteps)
+		globals, err := starlark.ExecFile(thread, scriptPath, nil, p.scriptBuiltins())
+		if err != nil {
+			p.scriptErr = fmt.Errorf("error loading script: %w", err)
+			return
+		}
+
+		s := &script{}
+		s.include, _ = globals["include"].(starlark.Callable)
+		s.header, _ = globals["header"].(starlark.Callable)
+		if s.include == nil && s.header == nil {
+			p.scriptErr = fmt.Errorf("error loading script: %s defines neither include nor header", p.config.Script)
+			return
+		}
+		p.script = s
+	})
+	return p.script, p.scriptErr
+}
+
+// runScript applies the script's rules to a candidate file and returns the header to use
+func (p *Processor) runScript(relPath, header string) (string, error) {
+	s, err := p.loadScript()
+	if s == nil || err != nil {
+		return header, err
+	}
+
+	file := starlarkstruct.FromStringDict(starlark.String("file"), starlark.StringDict{
+		"path":   starlark.String(relPath),
+		"type":   starlark.String(strings.ToLower(path.Ext(relPath))),
+		"header": starlark.String(header),
+	})
+	thread := &starlark.Thread{Name: relPath}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+
+	if s.include != nil {
+		result, err := starlark.Call(thread, s.include, starlark.Tuple{file}, nil)
+		if err != nil {
+			return header, fmt.Errorf("script include failed: %w", err)
+		}
+		if !result.Truth() {
+			return header, skipReason("excluded by script")
+		}
+	}
+
+	if s.header != nil {
+		thread.SetMaxExecutionSteps(scriptMaxSteps)
+		result, err := starlark.Call(thread, s.header, starlark.Tuple{file}, nil)
+		if err != nil {
+			return header, fmt.Errorf("script header failed: %w", err)
+		}
+		switch result := result.(type) {
+		case starlark.NoneType:
+		case starlark.String:
+			header = string(result)
+			if header != "" && !strings.HasSuffix(header, "\n") {
+				header += "\n"
+			}
+		default:
+			return header, fmt.Errorf("script header returned %s, expected a string or None", result.Type())
+		}
+	}
+	return header, nil
+}
+
+// scriptBuiltins are the functions available to scripts besides the Starlark core
+func (p *Processor) scriptBuiltins() starlark.StringDict {
+	return starlark.StringDict{
+		"read_file": starlark.NewBuiltin("read_file", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name string
+			if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+				return nil, err
+			}
+			filePath, err := p.scriptPath(name)
+			if err != nil {
+				return nil, err
+			}
+			data, err := p.readFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("read_file: %w", err)
+			}
+			return starlark.String(data), nil
+		}),
+		"exists": starlark.NewBuiltin("exists", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name string
+			if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+				return nil, err
+			}
+			filePath, err := p.scriptPath(name)
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.stat(filePath)
+			return starlark.Bool(err == nil), nil
+		}),
+	}
+}
+
+// scriptPath resolves a slash-separated path given by a script, refusing
+// paths outside the root directory
+func (p *Processor) scriptPath(name string) (string, error) {
+	cleaned := path.Clean(name)
+	if path.IsAbs(cleaned) || filepath.IsAbs(name) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("path %q is outside the root directory", name)
+	}
+	return filepath.Join(p.rootDir, filepath.FromSlash(cleaned)), nil
+}
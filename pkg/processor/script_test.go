@@ -0,0 +1,100 @@
+// File: pkg/processor/script_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testScript = `
+def include(file):
+    return not file.path.startswith("vendor/")
+
+def header(file):
+    if file.path.startswith("gen/"):
+        proto = file.path[len("gen/"):].replace(".pb.go", ".proto")
+        if exists("proto/" + proto):
+            return "// File: %s (generated from proto/%s)" % (file.path, proto)
+    return None
+`
+
+func TestScriptRules(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-script-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"rules.star":        testScript,
+		"proto/user.proto":  "syntax = \"proto3\";\n",
+		"gen/user.pb.go":    "package gen\n",
+		"gen/other.pb.go":   "package gen\n",
+		"vendor/lib/lib.go": "package lib\n",
+		"main.go":           "package main\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	processor.config.Script = "rules.star"
+	if _, err := processor.Process(); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"gen/user.pb.go", "// File: gen/user.pb.go (generated from proto/user.proto)\npackage gen\n"},
+		{"gen/other.pb.go", "// File: gen/other.pb.go\npackage gen\n"},
+		{"vendor/lib/lib.go", "package lib\n"},
+		{"main.go", "// File: main.go\npackage main\n"},
+	}
+	for _, test := range tests {
+		content, _ := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(test.name)))
+		if string(content) != test.expected {
+			t.Errorf("Process() wrote %q to %s, expected %q", content, test.name, test.expected)
+		}
+	}
+}
+
+func TestScriptErrors(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-script-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tests := []struct {
+		script   string
+		expected string
+	}{
+		{"def helper():\n    pass\n", "defines neither include nor header"},
+		{"def include(file)\n", "error loading script"},
+		{"def include(file):\n    return read_file('../secret') != ''\n", "outside the root directory"},
+		{"def include(file):\n    for i in range(100000000):\n        pass\n", "too many steps"},
+		{"def header(file):\n    return 42\n", "expected a string or None"},
+	}
+
+	for i, test := range tests {
+		name := filepath.Join(tempDir, "rules.star")
+		if err := os.WriteFile(name, []byte(test.script), 0644); err != nil {
+			t.Fatalf("Failed to write script: %v", err)
+		}
+
+		processor := NewProcessor(tempDir, &Options{})
+		processor.config.Script = "rules.star"
+		_, err := processor.runScript("main.go", "// File: main.go\n")
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("runScript() with script %d = %v, expected an error containing %q", i, err, test.expected)
+		}
+	}
+}
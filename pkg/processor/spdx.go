@@ -0,0 +1,57 @@
+// File: pkg/processor/spdx.go
+package processor
+
+import (
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// spdxTag starts the comment line that names a file's license for SPDX tools
+const spdxTag = "SPDX-License-Identifier:"
+
+// setLicenseID keeps the SPDX tag next to a file's header in step with id,
+// returning the text before the header, the header and the text after it.
+// A tag in a header block, or in a license comment above or below the
+// header, is updated where it is; otherwise the tag goes on the line below
+// the header, replacing one already there.
+func setLicenseID(before []byte, commentText string, rest []byte, style models.CommentStyle, id string, prefixes ...string) ([]byte, string, []byte) {
+	tag := spdxTag + " " + id
+	if text, ok := replaceLicenseID(commentText, tag, style); ok {
+		return before, text, rest
+	}
+	if text, ok := replaceLicenseID(string(before), tag, style); ok {
+		return []byte(text), commentText, rest
+	}
+	if banner := licenseBannerLen(rest, style, prefixes...); banner > 0 {
+		if text, ok := replaceLicenseID(string(rest[:banner]), tag, style); ok {
+			return before, commentText, append([]byte(text), rest[banner:]...)
+		}
+	}
+
+	// The tag line has the header's comment markers but not its wrappers
+	style.HeaderPrefix, style.HeaderSuffix = "", ""
+	line, err := renderHeader(style, tag)
+	if err != nil {
+		return before, commentText, rest
+	}
+	return before, commentText + line, rest[spdxLineLen(rest, style):]
+}
+
+// replaceLicenseID returns text with the SPDX tag it holds replaced by tag,
+// or false if it holds none
+func replaceLicenseID(text, tag string, style models.CommentStyle) (string, bool) {
+	span, ok := fieldSpan(text, style, spdxTag)
+	if !ok {
+		return text, false
+	}
+	return text[:span[0]] + tag + text[span[1]:], true
+}
+
+// spdxLineLen returns the length of the comment line holding only an SPDX
+// tag at the start of rest, or 0 if rest does not start with one
+func spdxLineLen(rest []byte, style models.CommentStyle) int {
+	line, n := firstLine(rest)
+	if _, ok := fieldSpan(line, style, spdxTag); !ok || !isHeaderLine(line, style, spdxTag) {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,90 @@
+// File: pkg/processor/spdx_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestSPDXLicense(t *testing.T) {
+	testCases := []struct {
+		name         string
+		afterLicense bool
+		files        map[string]string
+		expected     map[string]string
+	}{
+		{
+			name: "below header",
+			files: map[string]string{
+				"new.go":     "package a\n",
+				"current.go": "// File: current.go\n// SPDX-License-Identifier: MIT\npackage a\n",
+				"other.go":   "// File: other.go\n// SPDX-License-Identifier: GPL-2.0-only\npackage a\n",
+				"tagged.go":  "// SPDX-License-Identifier: MIT\npackage a\n",
+				"banner.go":  "// Copyright 2020 Jane Doe\n// SPDX-License-Identifier: BSD-3-Clause\n\npackage a\n",
+				"block.c":    "/*\n * File: block.c\n * SPDX-License-Identifier: Apache-2.0\n */\nint x;\n",
+				"script.py":  "#!/usr/bin/env python3\nx = 1\n",
+			},
+			expected: map[string]string{
+				"new.go":     "// File: new.go\n// SPDX-License-Identifier: MIT\npackage a\n",
+				"current.go": "// File: current.go\n// SPDX-License-Identifier: MIT\npackage a\n",
+				"other.go":   "// File: other.go\n// SPDX-License-Identifier: MIT\npackage a\n",
+				"tagged.go":  "// File: tagged.go\n// SPDX-License-Identifier: MIT\npackage a\n",
+				"banner.go":  "// File: banner.go\n// Copyright 2020 Jane Doe\n// SPDX-License-Identifier: MIT\n\npackage a\n",
+				"block.c":    "/*\n * File: block.c\n * SPDX-License-Identifier: MIT\n */\nint x;\n",
+				"script.py":  "#!/usr/bin/env python3\n# File: script.py\n# SPDX-License-Identifier: MIT\nx = 1\n",
+			},
+		},
+		{
+			name:         "after license",
+			afterLicense: true,
+			files: map[string]string{
+				"new.go":    "package a\n",
+				"banner.go": "// Copyright 2020 Jane Doe\n// SPDX-License-Identifier: BSD-3-Clause\n\npackage a\n",
+				"block.go":  "/* Copyright 2020 Jane Doe */\npackage a\n",
+			},
+			expected: map[string]string{
+				"new.go":    "// File: new.go\n// SPDX-License-Identifier: MIT\npackage a\n",
+				"banner.go": "// Copyright 2020 Jane Doe\n// SPDX-License-Identifier: MIT\n// File: banner.go\n\npackage a\n",
+				"block.go":  "/* Copyright 2020 Jane Doe */\n// File: block.go\n// SPDX-License-Identifier: MIT\npackage a\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tempDir, err := os.MkdirTemp("", "spdx-test")
+		if err != nil {
+			t.Fatalf("Failed to create temp directory: %v", err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		for name, content := range tc.files {
+			if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+
+		config := models.NewConfig().WithSPDXLicense("MIT")
+		config.AfterLicense = tc.afterLicense
+		if _, err := NewProcessor(tempDir, &Options{Config: config}).Process(); err != nil {
+			t.Fatalf("%s: Processor.Process failed: %v", tc.name, err)
+		}
+		for name, want := range tc.expected {
+			content, err := os.ReadFile(filepath.Join(tempDir, name))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", name, err)
+			}
+			if string(content) != want {
+				t.Errorf("%s: unexpected content for %s: %q, expected %q", tc.name, name, string(content), want)
+			}
+		}
+
+		// The tags stay put on the next run
+		stats, err := NewProcessor(tempDir, &Options{Config: config}).Process()
+		if err != nil || stats.Updated != 0 {
+			t.Errorf("%s: second run = %+v, %v, expected no updates", tc.name, stats, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// File: pkg/processor/stylematch.go
+package processor
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// chooseCommentKind returns style with the kind of comment the header should
+// use: the one the Style setting forces, or with MatchCommentStyle the one
+// the file already uses, or else the style's own preference. Only styles
+// with both kinds are affected, and not those whose block markers are the
+// same, like Python's ”', as those blocks are really string literals and
+// one at the top of a file is its docstring.
+func (p *Processor) chooseCommentKind(content []byte, style models.CommentStyle) models.CommentStyle {
+	if style.LineComment == "" || style.BlockCommentStart == "" || style.BlockCommentEnd == "" || style.BlockCommentStart == style.BlockCommentEnd {
+		return style
+	}
+	if p.config.Style != "" {
+		style.Preferred = p.config.Style
+		return style
+	}
+	if p.config.MatchCommentStyle {
+		return matchCommentStyle(content, style)
+	}
+	return style
+}
+
+// matchCommentStyle returns style preferring the kind of comment the file
+// already uses: that of its current header or of the comment where the
+// header goes, or else the kind most of its comment lines use. Files without
+// comments keep the configured preference.
+func matchCommentStyle(content []byte, style models.CommentStyle) models.CommentStyle {
+	// The header's own line, or the banner the header would sit above
+	rest := content[findInsertionPoint(content, style):]
+	for len(rest) > 0 {
+		line, n := firstLine(rest)
+		if line = strings.TrimSpace(line); line != "" {
+			if kind := commentKind(line, style); kind != "" {
+				style.Preferred = kind
+				return style
+			}
+			break
+		}
+		rest = rest[n:]
+	}
+
+	// Otherwise the kind used by most comment lines
+	var lines, blocks int
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		switch commentKind(string(bytes.TrimSpace(line)), style) {
+		case models.PreferLine:
+			lines++
+		case models.PreferBlock:
+			blocks++
+		}
+	}
+	switch {
+	case lines > blocks:
+		style.Preferred = models.PreferLine
+	case blocks > lines:
+		style.Preferred = models.PreferBlock
+	}
+	return style
+}
+
+// commentKind returns whether a trimmed line starts a line or block comment
+// in style, or "" if it is not a comment. Block markers are checked first,
+// since they may begin with the line marker (as Lua's --[[ does).
+func commentKind(line string, style models.CommentStyle) string {
+	switch {
+	case strings.HasPrefix(line, strings.TrimSpace(style.BlockCommentStart)):
+		return models.PreferBlock
+	case strings.HasPrefix(line, strings.TrimSpace(style.LineComment)):
+		return models.PreferLine
+	}
+	return ""
+}
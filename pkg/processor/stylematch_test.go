@@ -0,0 +1,72 @@
+// File: pkg/processor/stylematch_test.go
+package processor
+
+import (
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestChooseCommentKind(t *testing.T) {
+	processor := &Processor{config: &models.Config{MatchCommentStyle: true}}
+	cStyle := models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"}
+	luaStyle := models.CommentStyle{LineComment: "--", BlockCommentStart: "--[[", BlockCommentEnd: "--]]", Preferred: "block"}
+	pyStyle := models.CommentStyle{LineComment: "#", BlockCommentStart: "'''", BlockCommentEnd: "'''", Preferred: "line"}
+
+	testCases := []struct {
+		name     string
+		content  string
+		style    models.CommentStyle
+		expected string
+	}{
+		{"existing block header", "/* File: a.c */\n// one\n// two\nint x;\n", cStyle, "block"},
+		{"block banner", "\n/*\n * Copyright\n */\n// one\n// two\n", cStyle, "block"},
+		{"mostly block comments", "int x;\n/* a */\nint y; // b\n/* c */\n", cStyle, "block"},
+		{"mostly line comments", "int x;\n// a\n/* b */\n// c\n", cStyle, "line"},
+		{"no comments", "int x;\n", cStyle, "line"},
+		{"tie", "int x;\n// a\n/* b */\n", cStyle, "line"},
+		{"lua line comments", "local x\n-- a\n-- b\n--[[ c ]]\n", luaStyle, "line"},
+		{"python docstring", "'''Module docs.'''\nimport os\n", pyStyle, "line"},
+		{"line only style", "/* a */\n", models.CommentStyle{LineComment: "#", Preferred: "line"}, "line"},
+	}
+
+	for _, tc := range testCases {
+		if got := processor.chooseCommentKind([]byte(tc.content), tc.style).Preferred; got != tc.expected {
+			t.Errorf("chooseCommentKind(%s) = %q, expected %q", tc.name, got, tc.expected)
+		}
+	}
+
+	// The option is off by default
+	processor.config.MatchCommentStyle = false
+	if got := processor.chooseCommentKind([]byte("/* a */\n"), cStyle).Preferred; got != "line" {
+		t.Errorf("chooseCommentKind(disabled) = %q, expected %q", got, "line")
+	}
+}
+
+func TestStyleOverride(t *testing.T) {
+	cStyle := models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "line"}
+	pyStyle := models.CommentStyle{LineComment: "#", BlockCommentStart: "'''", BlockCommentEnd: "'''", Preferred: "line"}
+	shStyle := models.CommentStyle{LineComment: "#", Preferred: "line"}
+	content := []byte("// a\n// b\nint x;\n")
+
+	testCases := []struct {
+		override string
+		match    bool
+		style    models.CommentStyle
+		expected string
+	}{
+		{"block", false, cStyle, "block"},
+		{"block", true, cStyle, "block"},
+		{"line", false, models.CommentStyle{LineComment: "//", BlockCommentStart: "/*", BlockCommentEnd: "*/", Preferred: "block"}, "line"},
+		{"block", false, pyStyle, "line"},
+		{"block", false, shStyle, "line"},
+		{"", false, cStyle, "line"},
+	}
+
+	for _, tc := range testCases {
+		processor := &Processor{config: &models.Config{Style: tc.override, MatchCommentStyle: tc.match}}
+		if got := processor.chooseCommentKind(content, tc.style).Preferred; got != tc.expected {
+			t.Errorf("chooseCommentKind(%q, style %q) = %q, expected %q", content, tc.override, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// File: pkg/processor/template.go
+package processor
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// Stand-ins for the date and year, to find where the template puts them
+const (
+	dateMarker = "\x00date\x00"
+	yearMarker = "\x00year\x00"
+)
+
+// headerText returns the text of relPath's header, written by the
+// HeaderTemplate, along with a pattern matching that text with any date and
+// year, or nil if the template uses neither
+func (p *Processor) headerText(prefix, relPath string) (string, *regexp.Regexp, error) {
+	text, marked, err := expand(p.header, p.headerData(prefix, relPath))
+	if err != nil {
+		return "", nil, err
+	}
+	return text, datePattern(strings.TrimRight(marked, " \t")), nil
+}
+
+// headerData returns the values templates can use for relPath
+func (p *Processor) headerData(prefix, relPath string) models.HeaderData {
+	now := time.Now()
+	return models.HeaderData{
+		Prefix:   prefix,
+		Path:     relPath,
+		Filename: path.Base(relPath),
+		Dir:      path.Dir(relPath),
+		Project:  p.project,
+		Date:     now.Format("2006-01-02"),
+		Year:     now.Format("2006"),
+	}
+}
+
+// expand executes tmpl with data, and again with markers in place of the
+// date and year
+func expand(tmpl *template.Template, data models.HeaderData) (string, string, error) {
+	var text strings.Builder
+	if err := tmpl.Execute(&text, data); err != nil {
+		return "", "", err
+	}
+	data.Date, data.Year = dateMarker, yearMarker
+	var marked strings.Builder
+	if err := tmpl.Execute(&marked, data); err != nil {
+		return "", "", err
+	}
+	return text.String(), marked.String(), nil
+}
+
+// datePattern returns a pattern matching marked, text expanded with the
+// date and year markers, with any date and year in their place, or nil if
+// it has no markers
+func datePattern(marked string) *regexp.Regexp {
+	if !strings.Contains(marked, dateMarker) && !strings.Contains(marked, yearMarker) {
+		return nil
+	}
+	pattern := regexp.QuoteMeta(marked)
+	pattern = strings.ReplaceAll(pattern, dateMarker, `\d{4}-\d{2}-\d{2}`)
+	pattern = strings.ReplaceAll(pattern, yearMarker, `\d{4}`)
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+// sameButDates reports whether the existing header differs from rendered
+// only in the dates its path field holds, which match dated. Such a header
+// is current: it is not rewritten every day.
+func (p *Processor) sameButDates(existing, rendered string, dated *regexp.Regexp, style models.CommentStyle, prefixes ...string) bool {
+	if dated == nil {
+		return false
+	}
+	from, ok := fieldSpan(existing, style, prefixes...)
+	if !ok || !dated.MatchString(existing[from[0]:from[1]]) {
+		return false
+	}
+	to, ok := fieldSpan(rendered, style, prefixes...)
+	if !ok {
+		return false
+	}
+	return p.sameHeader(existing[:from[0]]+rendered[to[0]:to[1]]+existing[from[1]:], rendered)
+}
+
+// projectName returns the name of the project rootDir is part of: the top of
+// its git work tree, or rootDir itself outside one
+func projectName(rootDir string) string {
+	if workTree, _ := findGitDir(rootDir); workTree != "" {
+		return filepath.Base(workTree)
+	}
+	return filepath.Base(rootDir)
+}
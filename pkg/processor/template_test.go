@@ -0,0 +1,99 @@
+// File: pkg/processor/template_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestHeaderTemplate(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "template-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	project := filepath.Base(tempDir)
+	year := time.Now().Format("2006")
+	files := map[string]string{
+		"main.go":    "package main\n",
+		"pkg/cur.go": "// File: pkg/cur.go (cur.go in pkg of " + project + ", 2001)\npackage pkg\n",
+		"pkg/old.go": "// File: old.go (old.go in . of " + project + ", 2001)\npackage pkg\n",
+		"pkg/raw.go": "// File: pkg/raw.go\npackage pkg\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config := models.NewConfig().WithHeaderTemplate("{{.Prefix}}{{.Path}} ({{.Filename}} in {{.Dir}} of {{.Project}}, {{.Year}})")
+	stats, err := NewProcessor(tempDir, &Options{Config: config}).Process()
+	if err != nil {
+		t.Fatalf("Processor.Process failed: %v", err)
+	}
+	if stats.Missing != 1 || stats.Stale != 2 {
+		t.Errorf("Expected 1 missing and 2 stale headers, got: %d missing, %d stale", stats.Missing, stats.Stale)
+	}
+
+	// A header from another year is current as long as the rest matches
+	expected := map[string]string{
+		"main.go":    "// File: main.go (main.go in . of " + project + ", " + year + ")\npackage main\n",
+		"pkg/cur.go": files["pkg/cur.go"],
+		"pkg/old.go": "// File: pkg/old.go (old.go in pkg of " + project + ", " + year + ")\npackage pkg\n",
+		"pkg/raw.go": "// File: pkg/raw.go (raw.go in pkg of " + project + ", " + year + ")\npackage pkg\n",
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("Unexpected content for %s: %q, expected %q", name, string(content), want)
+		}
+	}
+}
+
+func TestHeaderTextDates(t *testing.T) {
+	header, err := models.ParseHeaderTemplate("{{.Prefix}}{{.Path}}, created {{.Date}}")
+	if err != nil {
+		t.Fatalf("ParseHeaderTemplate failed: %v", err)
+	}
+	p := &Processor{header: header}
+	text, dated, err := p.headerText("File: ", "a/b.go")
+	if err != nil {
+		t.Fatalf("headerText failed: %v", err)
+	}
+	if expected := "File: a/b.go, created " + time.Now().Format("2006-01-02"); text != expected {
+		t.Errorf("headerText(a/b.go) = %q, expected %q", text, expected)
+	}
+
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{"File: a/b.go, created 1999-12-31", true},
+		{"File: a/b.go, created yesterday", false},
+		{"File: a/c.go, created 1999-12-31", false},
+		{"File: a/b.go, created 1999-12-31 by Jane", false},
+	}
+	for _, test := range tests {
+		if result := dated.MatchString(test.header); result != test.expected {
+			t.Errorf("dated.MatchString(%s) = %v, expected %v", test.header, result, test.expected)
+		}
+	}
+
+	// Without dates, headers are compared as they are
+	p.header, _ = models.ParseHeaderTemplate("")
+	if text, dated, _ := p.headerText("File: ", "a/b.go"); text != "File: a/b.go" || dated != nil {
+		t.Errorf("headerText(a/b.go) with the default template = %q, %v, expected %q and no pattern", text, dated, "File: a/b.go")
+	}
+}
@@ -0,0 +1,59 @@
+// File: pkg/processor/validate.go
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errValidationFailed marks files whose change was reverted because the
+// configured validator rejected it
+var errValidationFailed = errors.New("validation failed")
+
+// validatorFor returns the validator configured for a file's extension, or
+// for the longest of its compound extensions, such as ".d.ts"
+func (p *Processor) validatorFor(relPath string) []string {
+	for _, ext := range fileExtensions(relPath) {
+		for key, command := range p.config.Validators {
+			if containsExtension([]string{key}, ext) {
+				return command
+			}
+		}
+	}
+	return nil
+}
+
+// validateFile runs the configured validator on a modified file
+func (p *Processor) validateFile(relPath string) error {
+	validator := p.validatorFor(relPath)
+	if len(validator) == 0 {
+		return nil
+	}
+
+	// The path replaces {file} placeholders, or is appended when there are none
+	args := make([]string, 0, len(validator)+1)
+	placed := false
+	for _, arg := range validator {
+		if strings.Contains(arg, "{file}") {
+			arg = strings.ReplaceAll(arg, "{file}", relPath)
+			placed = true
+		}
+		args = append(args, arg)
+	}
+	if !placed {
+		args = append(args, relPath)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = p.rootDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%w: %s: %s", errValidationFailed, args[0], message)
+		}
+		return fmt.Errorf("%w: %s: %v", errValidationFailed, args[0], err)
+	}
+	return nil
+}
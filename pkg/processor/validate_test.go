@@ -0,0 +1,86 @@
+// File: pkg/processor/validate_test.go
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidatorHelperProcess is not a real test: it is run as a validator
+// by TestValidation and rejects files containing "reject"
+func TestValidatorHelperProcess(t *testing.T) {
+	if os.Getenv("PATHFIX_VALIDATOR_HELPER") == "" {
+		return
+	}
+	path := os.Args[len(os.Args)-1]
+	content, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot read %s", path)
+		os.Exit(2)
+	}
+	if strings.Contains(string(content), "reject") {
+		fmt.Fprintf(os.Stderr, "%s: syntax error", path)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestValidation(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-validate-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	t.Setenv("PATHFIX_VALIDATOR_HELPER", "1")
+
+	files := map[string]string{
+		"good.go": "package main\n",
+		"bad.go":  "package main // reject\n",
+		"app.py":  "print('reject')\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	processor := NewProcessor(tempDir, &Options{})
+	processor.config.Validators = map[string][]string{
+		"go": {os.Args[0], "-test.run=^TestValidatorHelperProcess$", "--", "{file}"},
+	}
+	stats, err := processor.Process()
+	var fileErrors FileErrors
+	if !errors.As(err, &fileErrors) || len(fileErrors) != 1 || fileErrors[0].Path != "bad.go" {
+		t.Fatalf("Process() returned %v, expected a failure for bad.go only", err)
+	}
+	if !errors.Is(err, errValidationFailed) {
+		t.Errorf("Process() returned %v, expected it to wrap errValidationFailed", err)
+	}
+
+	// Python files have no validator, so the change is kept
+	if stats.Updated != 2 || stats.Errors != 1 || stats.Reverted != 1 {
+		t.Errorf("Process() = %+v, expected 2 updated, 1 error and 1 reverted", stats)
+	}
+
+	expected := map[string]string{
+		"good.go": "// File: good.go\npackage main\n",
+		"bad.go":  "package main // reject\n",
+		"app.py":  "# File: app.py\nprint('reject')\n",
+	}
+	for name, want := range expected {
+		content, _ := os.ReadFile(filepath.Join(tempDir, name))
+		if string(content) != want {
+			t.Errorf("Process() left %q in %s, expected %q", content, name, want)
+		}
+	}
+
+	for _, result := range processor.Results() {
+		if result.Path == "bad.go" && !strings.Contains(result.Error, "validation failed") {
+			t.Errorf("result for bad.go = %+v, expected a validation error", result)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// File: pkg/processor/warnings.go
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// Skip reasons that are warnings: the file would normally get a header but
+// a policy setting left it alone
+const (
+	reasonInvalidName = "non-UTF-8 file name"
+	reasonEncoding    = "invalid text encoding: "
+)
+
+// warning classifies a file's outcome, returning what deserves attention or
+// "" if nothing does. Errors are not warnings.
+func (p *Processor) warning(result models.FileResult) string {
+	switch result.Action {
+	case models.ActionSkipped:
+		if result.Reason == reasonInvalidName || strings.HasPrefix(result.Reason, reasonEncoding) {
+			return "skipped by policy: " + result.Reason
+		}
+	case models.ActionUpdated, models.ActionUnchanged:
+		// Escaping or normalization made the header differ from the name on disk
+		headerPath, err := p.encodeHeaderPath(result.Path)
+		if err == nil {
+			headerPath, err = p.normalizeHeaderPath(headerPath)
+		}
+		if err == nil && headerPath != result.Path {
+			return fmt.Sprintf("header path rewritten as %q", headerPath)
+		}
+	}
+	return ""
+}
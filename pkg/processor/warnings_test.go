@@ -0,0 +1,64 @@
+// File: pkg/processor/warnings_test.go
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestWarning(t *testing.T) {
+	p := NewProcessor(".", &Options{})
+	p.config.InvalidPathPolicy = "escape"
+
+	tests := []struct {
+		result  models.FileResult
+		warning bool
+	}{
+		{models.FileResult{Path: "main.go", Action: models.ActionUpdated}, false},
+		// NFD name, normalized to NFC in the header
+		{models.FileResult{Path: "cafe\u0301.go", Action: models.ActionUpdated}, true},
+		{models.FileResult{Path: "caf\xe9.go", Action: models.ActionUnchanged}, true},
+		{models.FileResult{Path: "a.go", Action: models.ActionSkipped, Reason: "hidden file"}, false},
+		{models.FileResult{Path: "a.go", Action: models.ActionSkipped, Reason: reasonInvalidName}, true},
+		{models.FileResult{Path: "a.go", Action: models.ActionSkipped, Reason: reasonEncoding + "UTF-16LE"}, true},
+		{models.FileResult{Path: "a.go", Action: models.ActionError, Error: "permission denied"}, false},
+	}
+	for _, test := range tests {
+		if warning := p.warning(test.result); (warning != "") != test.warning {
+			t.Errorf("warning(%+v) = %q, expected a warning: %v", test.result, warning, test.warning)
+		}
+	}
+}
+
+func TestWarningStats(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-warnings-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"main.go": "package main\n",
+		// UTF-16 with an unpaired surrogate, which would not survive transcoding
+		"wide.py": "\xff\xfep\x00r\x00\x00\xd8n\x00t\x00\n\x00",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	processor := NewProcessor(tempDir, &Options{DryRun: true})
+	stats, _ := processor.Process()
+	if stats.Warnings != 1 {
+		t.Errorf("Process() reported %d warnings, expected 1 for wide.py", stats.Warnings)
+	}
+	for _, result := range processor.Results() {
+		if (result.Warning != "") != (result.Path == "wide.py") {
+			t.Errorf("result %+v has an unexpected warning", result)
+		}
+	}
+}
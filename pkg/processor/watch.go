@@ -0,0 +1,168 @@
+// File: pkg/processor/watch.go
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDelay is how long Watch waits after the last change before it
+// processes the new files, so that a checkout or a copied tree, which
+// create files in bursts, is handled in one run
+const watchDelay = 200 * time.Millisecond
+
+// Watch keeps the headers beneath the root directory up to date as files
+// are created, renamed or moved into place, until ctx is done. The files
+// already there are left to Process. Each burst of changes is processed as
+// a run over the new files alone, with the processor's options, reported
+// through its callbacks and output but not recorded in the run history.
+// Directories a walk would skip, such as hidden and gitignored ones, are
+// not watched. Watch returns nil once ctx is done, or the error that
+// stopped it; it needs the root directory on disk, not Options.FS.
+func (p *Processor) Watch(ctx context.Context) error {
+	if p.options.FS != nil {
+		return errors.New("watching needs the root directory on disk, not Options.FS")
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error watching %s: %w", p.rootDir, err)
+	}
+	defer watcher.Close()
+	if err := p.watchTree(watcher, p.rootDir); err != nil {
+		return fmt.Errorf("error watching %s: %w", p.rootDir, err)
+	}
+
+	var pending []string // Paths created since the last run, in order
+	queued := make(map[string]bool)
+	queue := func(path string) {
+		if !queued[path] {
+			queued[path] = true
+			pending = append(pending, path)
+			if p.options.OnWatchQueue != nil {
+				p.options.OnWatchQueue(len(pending))
+			}
+		}
+	}
+	timer := time.NewTimer(watchDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// A new file still being written waits for the writes to stop
+			if event.Has(fsnotify.Write) && queued[event.Name] {
+				timer.Reset(watchDelay)
+			}
+			if !event.Has(fsnotify.Create) {
+				continue
+			}
+			// New directories are watched too, and the files already in
+			// them, as in a moved or copied tree, processed
+			if info, err := os.Lstat(event.Name); err == nil && info.IsDir() {
+				if err := p.watchTree(watcher, event.Name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("error watching %s: %w", event.Name, err)
+				}
+			}
+			queue(event.Name)
+			timer.Reset(watchDelay)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			// Changes were lost, so look at every file again
+			if !errors.Is(err, fsnotify.ErrEventOverflow) {
+				return fmt.Errorf("error watching %s: %w", p.rootDir, err)
+			}
+			queue(p.rootDir)
+			timer.Reset(watchDelay)
+
+		case <-timer.C:
+			// Files may be gone again, such as an editor's temporary files
+			var paths []string
+			for _, path := range pending {
+				if _, err := os.Lstat(path); err == nil {
+					paths = append(paths, path)
+				}
+			}
+			pending, queued = nil, make(map[string]bool)
+			if p.options.OnWatchQueue != nil {
+				p.options.OnWatchQueue(0)
+			}
+			if err := p.processCreated(ctx, paths); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// processCreated runs the processor over the files Watch found, returning
+// the error that stops watching, if any
+func (p *Processor) processCreated(ctx context.Context, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// The runs share the loaded script, like the workers
+	if _, err := p.loadScript(); err != nil {
+		return err
+	}
+	options := *p.options
+	options.Files = paths
+	options.History = false
+	if options.OnWatchRun != nil {
+		options.OnWatchRun()
+	}
+	_, err := p.fork(&options).ProcessContext(ctx)
+	if options.OnWatchRunDone != nil {
+		options.OnWatchRunDone(err)
+	}
+	var fileErrors FileErrors
+	if err != nil && !errors.As(err, &fileErrors) && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// watchTree adds watches for dir and the directories beneath it that a walk
+// would enter
+func (p *Processor) watchTree(watcher *fsnotify.Watcher, dir string) error {
+	gitignore, err := p.gitIgnore()
+	if err != nil {
+		return err
+	}
+	ignores := p.configIgnores()
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			// Directories may vanish while they are walked
+			if path != dir {
+				return nil
+			}
+			return err
+		}
+		if path != p.rootDir {
+			if path == p.State().Path() || !p.options.IncludeHidden && isHiddenPath(path, d.Name()) {
+				return filepath.SkipDir
+			}
+			if !p.config.IncludeGitIgnored && gitignore.ignoresDir(path) || ignores.ignoresDir(path) {
+				return filepath.SkipDir
+			}
+		}
+		return watcher.Add(path)
+	})
+}
@@ -0,0 +1,119 @@
+// File: pkg/processor/watch_test.go
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestWatch(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-watch-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		".gitignore": "build/\n",
+		"old.go":     "package old\n",
+		"build/a.go": "package build\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	updated := make(chan string, 10)
+	runs := make(chan error, 10)
+	var queued atomic.Int32
+	p := NewProcessor(tempDir, &Options{
+		OnFileUpdated:  func(result models.FileResult) { updated <- result.Path },
+		OnWatchQueue:   func(depth int) { queued.Store(int32(depth)) },
+		OnWatchRunDone: func(err error) { runs <- err },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.Watch(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Watch() = %v, expected nil once canceled", err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// New files and moved-in directories get headers; existing files and
+	// gitignored directories are left alone
+	moved, err := os.MkdirTemp("", "pathfix-watch-moved")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(moved)
+	if err := os.WriteFile(filepath.Join(moved, "util.py"), []byte("x = 1\n"), 0644); err != nil {
+		t.Fatalf("Failed to create util.py: %v", err)
+	}
+	if err := os.Rename(moved, filepath.Join(tempDir, "lib")); err != nil {
+		t.Fatalf("Failed to move lib: %v", err)
+	}
+	tests := []struct {
+		name     string
+		content  string // Written once watching, or "" for the files already in place
+		expected string
+	}{
+		{"new.go", "package new\n", "// File: new.go\npackage new\n"},
+		{"build/b.go", "package build\n", "package build\n"},
+		{"lib/util.py", "", "# File: lib/util.py\nx = 1\n"},
+		{"old.go", "", "package old\n"},
+	}
+	for _, test := range tests {
+		if test.content == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, test.name), []byte(test.content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", test.name, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for len(seen) < 2 {
+		select {
+		case path := <-updated:
+			seen[path] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Watch() updated %v, expected new.go and lib/util.py", seen)
+		}
+	}
+
+	// The queue is emptied for each run, which is reported when done
+	select {
+	case err := <-runs:
+		if err != nil {
+			t.Errorf("OnWatchRunDone(%v), expected nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("OnWatchRunDone was not called")
+	}
+	if depth := queued.Load(); depth != 0 {
+		t.Errorf("OnWatchQueue(%d) after the run, expected 0", depth)
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(filepath.Join(tempDir, test.name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", test.name, err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("%s = %q, expected %q", test.name, content, test.expected)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// File: pkg/processor/wildmatch.go
+package processor
+
+import (
+	"strings"
+)
+
+// Results of matchWild, as in git's wildmatch.c. The aborts stop callers
+// from retrying with a longer match for an earlier asterisk when that can
+// no longer help.
+const (
+	wildNoMatch = iota
+	wildMatch
+	wildAbortAll
+	wildAbortToDoubleStar
+)
+
+// wildmatch reports whether text, a path with forward slashes, matches a
+// .gitignore glob the way git does: "*", "?" and classes such as [a-z],
+// [!0-9] or [[:space:]] do not match a slash, "**" between slashes matches
+// any number of directories, and a backslash matches the next character
+// literally
+func wildmatch(pattern, text string, ignoreCase bool) bool {
+	return matchWild(pattern, text, ignoreCase) == wildMatch
+}
+
+// matchWild implements wildmatch
+func matchWild(pattern, text string, ignoreCase bool) int {
+	p, t := 0, 0
+	for ; p < len(pattern); p, t = p+1, t+1 {
+		pc := pattern[p]
+		if t == len(text) && pc != '*' {
+			return wildAbortAll
+		}
+		var tc byte
+		if t < len(text) {
+			tc = text[t]
+		}
+		if ignoreCase {
+			tc, pc = toLowerASCII(tc), toLowerASCII(pc)
+		}
+
+		switch pc {
+		case '?':
+			if tc == '/' {
+				return wildNoMatch
+			}
+		case '*':
+			matchSlash := false
+			if p++; p < len(pattern) && pattern[p] == '*' {
+				start := p - 1
+				for p < len(pattern) && pattern[p] == '*' {
+					p++
+				}
+				// Only "**" on its own between slashes matches across them
+				if (start == 0 || pattern[start-1] == '/') &&
+					(p == len(pattern) || pattern[p] == '/' || (pattern[p] == '\\' && p+1 < len(pattern) && pattern[p+1] == '/')) {
+					// "a/**/b" also matches "a/b"
+					if p < len(pattern) && pattern[p] == '/' && matchWild(pattern[p+1:], text[t:], ignoreCase) == wildMatch {
+						return wildMatch
+					}
+					matchSlash = true
+				}
+			}
+			if p == len(pattern) {
+				// A trailing "**" matches everything, and "*" the rest of the name
+				if !matchSlash && strings.IndexByte(text[t:], '/') >= 0 {
+					return wildNoMatch
+				}
+				return wildMatch
+			}
+			if !matchSlash && pattern[p] == '/' {
+				// "*/" matches the rest of the name and the slash
+				slash := strings.IndexByte(text[t:], '/')
+				if slash < 0 {
+					return wildNoMatch
+				}
+				t += slash
+				continue
+			}
+			for t < len(text) {
+				// Skip ahead to the literal after the asterisk, if it is one
+				if !isGlobSpecial(pattern[p]) {
+					want := pattern[p]
+					if ignoreCase {
+						want = toLowerASCII(want)
+					}
+					for t < len(text) && (matchSlash || text[t] != '/') {
+						c := text[t]
+						if ignoreCase {
+							c = toLowerASCII(c)
+						}
+						if c == want {
+							break
+						}
+						t++
+					}
+					if t == len(text) || (!matchSlash && text[t] == '/') {
+						return wildNoMatch
+					}
+				}
+				if matched := matchWild(pattern[p:], text[t:], ignoreCase); matched != wildNoMatch {
+					if !matchSlash || matched != wildAbortToDoubleStar {
+						return matched
+					}
+				} else if !matchSlash && text[t] == '/' {
+					return wildAbortToDoubleStar
+				}
+				t++
+			}
+			return wildAbortAll
+		case '[':
+			end, matched, ok := matchClass(pattern, p+1, tc, ignoreCase)
+			if !ok {
+				return wildAbortAll
+			}
+			if !matched || tc == '/' {
+				return wildNoMatch
+			}
+			p = end
+		case '\\':
+			// The next character is literal; a trailing backslash matches nothing
+			if p++; p == len(pattern) {
+				return wildNoMatch
+			}
+			pc = pattern[p]
+			if ignoreCase {
+				pc = toLowerASCII(pc)
+			}
+			fallthrough
+		default:
+			if tc != pc {
+				return wildNoMatch
+			}
+		}
+	}
+	if t < len(text) {
+		return wildNoMatch
+	}
+	return wildMatch
+}
+
+// matchClass matches c against the bracket expression whose contents start
+// at pattern[p], returning the index of its closing bracket and whether it
+// matched, or false if the expression is malformed. A "]" right after the
+// opening bracket or its "!" is literal.
+func matchClass(pattern string, p int, c byte, ignoreCase bool) (int, bool, bool) {
+	at := func(i int) byte {
+		if i < len(pattern) {
+			return pattern[i]
+		}
+		return 0
+	}
+
+	pc := at(p)
+	negated := pc == '!' || pc == '^'
+	if negated {
+		p++
+		pc = at(p)
+	}
+	matched := false
+	var prev byte
+	for {
+		if pc == 0 {
+			return 0, false, false
+		}
+		switch {
+		case pc == '\\':
+			p++
+			if pc = at(p); pc == 0 {
+				return 0, false, false
+			}
+			if matchFold(c, pc, ignoreCase) {
+				matched = true
+			}
+		case pc == '-' && prev != 0 && at(p+1) != 0 && at(p+1) != ']':
+			p++
+			if pc = at(p); pc == '\\' {
+				p++
+				if pc = at(p); pc == 0 {
+					return 0, false, false
+				}
+			}
+			if prev <= c && c <= pc {
+				matched = true
+			} else if ignoreCase && 'a' <= c && c <= 'z' && prev <= c-'a'+'A' && c-'a'+'A' <= pc {
+				matched = true
+			}
+			pc = 0 // A range can't start another one
+		case pc == '[' && at(p+1) == ':':
+			start := p + 2
+			end := start
+			for end < len(pattern) && pattern[end] != ']' {
+				end++
+			}
+			if end == len(pattern) {
+				return 0, false, false
+			}
+			if end-start < 1 || pattern[end-1] != ':' {
+				// Not a "[:name:]", so the bracket is literal
+				if c == '[' {
+					matched = true
+				}
+				break
+			}
+			inClass, known := matchCharClass(pattern[start:end-1], c, ignoreCase)
+			if !known {
+				return 0, false, false
+			}
+			if inClass {
+				matched = true
+			}
+			p = end
+			pc = 0
+		default:
+			if matchFold(c, pc, ignoreCase) {
+				matched = true
+			}
+		}
+		prev = pc
+		p++
+		if pc = at(p); pc == ']' {
+			return p, matched != negated, true
+		}
+	}
+}
+
+// matchCharClass reports whether c is in the POSIX class with the given
+// name, and whether the name is known
+func matchCharClass(name string, c byte, ignoreCase bool) (bool, bool) {
+	lower := 'a' <= c && c <= 'z'
+	upper := 'A' <= c && c <= 'Z'
+	digit := '0' <= c && c <= '9'
+	switch name {
+	case "alnum":
+		return lower || upper || digit, true
+	case "alpha":
+		return lower || upper, true
+	case "blank":
+		return c == ' ' || c == '\t', true
+	case "cntrl":
+		return c < ' ' || c == 0x7f, true
+	case "digit":
+		return digit, true
+	case "graph":
+		return c > ' ' && c < 0x7f, true
+	case "lower":
+		return lower || (ignoreCase && upper), true
+	case "print":
+		return c >= ' ' && c < 0x7f, true
+	case "punct":
+		return c > ' ' && c < 0x7f && !lower && !upper && !digit, true
+	case "space":
+		return c == ' ' || ('\t' <= c && c <= '\r'), true
+	case "upper":
+		return upper || (ignoreCase && lower), true
+	case "xdigit":
+		return digit || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F'), true
+	}
+	return false, false
+}
+
+// matchFold reports whether two bytes are equal, ignoring ASCII case if asked
+func matchFold(a, b byte, ignoreCase bool) bool {
+	if ignoreCase {
+		return toLowerASCII(a) == toLowerASCII(b)
+	}
+	return a == b
+}
+
+// toLowerASCII lowers an ASCII capital letter
+func toLowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// isGlobSpecial reports whether c has a meaning of its own in a glob
+func isGlobSpecial(c byte) bool {
+	return c == '*' || c == '?' || c == '[' || c == '\\'
+}
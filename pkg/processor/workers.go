@@ -0,0 +1,104 @@
+// File: pkg/processor/workers.go
+package processor
+
+import (
+	"sync"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// fileJob is an eligible file handed to a worker
+type fileJob struct {
+	path    string
+	relPath string
+}
+
+// workerPool processes files on several goroutines. Each worker has its own
+// processor for the run, so the lines it prints and the statistics it
+// counts stay apart; only the walk's goroutine records outcomes, so the
+// statistics, results and callbacks of the run are never shared.
+type workerPool struct {
+	jobs     chan fileJob
+	outcomes chan fileOutcome
+	pending  int // Files handed to a worker and not recorded yet
+}
+
+// startWorkers starts n workers for the current run. The script must be
+// loaded already.
+func (p *Processor) startWorkers(n int) *workerPool {
+	pool := &workerPool{
+		jobs:     make(chan fileJob),
+		outcomes: make(chan fileOutcome, n),
+	}
+	p.outputMu = &sync.Mutex{}
+	for i := 0; i < n; i++ {
+		worker := p.fork(p.options)
+		go func() {
+			for job := range pool.jobs {
+				outcome := worker.runFile(job.path, job.relPath)
+				outcome.stats, outcome.output = worker.statistics, worker.output.chunks
+				worker.statistics, worker.output.chunks = models.Stats{}, nil
+				pool.outcomes <- outcome
+			}
+		}()
+	}
+	return pool
+}
+
+// submit hands a file to the next free worker, recording the outcomes of
+// the files that finish in the meantime. It returns the error that ends the
+// run, if one of them failed.
+func (pool *workerPool) submit(p *Processor, path, relPath string) error {
+	for {
+		select {
+		case pool.jobs <- fileJob{path: path, relPath: relPath}:
+			pool.pending++
+			return nil
+		case outcome := <-pool.outcomes:
+			pool.pending--
+			if err := p.recordFile(outcome); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// finish stops the workers once they are done and records the outcomes
+// of the files they were still processing, returning the first error that
+// ends the run
+func (pool *workerPool) finish(p *Processor) error {
+	close(pool.jobs)
+	var first error
+	for ; pool.pending > 0; pool.pending-- {
+		if err := p.recordFile(<-pool.outcomes); err != nil && first == nil {
+			first = err
+		}
+	}
+	p.outputMu = nil
+	return first
+}
+
+// fork returns a processor with p's root and configuration, including its
+// loaded script, but its own statistics, results and output
+func (p *Processor) fork(options *Options) *Processor {
+	q := &Processor{
+		rootDir:   p.rootDir,
+		options:   options,
+		config:    p.config,
+		fileTypes: p.fileTypes,
+		fileNames: p.fileNames,
+		nameGlobs: p.nameGlobs,
+		header:    p.header,
+		notice:    p.notice,
+		project:   p.project,
+		languages: p.languages,
+		excluded:  p.excluded,
+		planning:  p.planning,
+		backup:    p.backup,
+		outputMu:  p.outputMu,
+		script:    p.script,
+		scriptErr: p.scriptErr,
+	}
+	q.scriptOnce.Do(func() {})
+	return q
+}
@@ -0,0 +1,104 @@
+// File: pkg/processor/workers_test.go
+package processor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestProcessWorkers(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-workers-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Missing, stale and up-to-date headers, and a binary file
+	for i := 0; i < 60; i++ {
+		name := fmt.Sprintf("dir%d/file%d.go", i%4, i)
+		content := "package a\n"
+		switch i % 3 {
+		case 1:
+			content = "// File: old.go\npackage a\n"
+		case 2:
+			content = "// File: " + name + "\npackage a\n"
+		}
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "data.go"), []byte("\x00\x01\x02"), 0644); err != nil {
+		t.Fatalf("Failed to create data.go: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "dir1", "Dockerfile"), []byte("FROM scratch\n"), 0644); err != nil {
+		t.Fatalf("Failed to create Dockerfile: %v", err)
+	}
+
+	serial := NewProcessor(tempDir, &Options{DryRun: true, Verbose: true, Stdout: &bytes.Buffer{}})
+	expected, err := serial.Process()
+	if err != nil {
+		t.Fatalf("Process() failed: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	var results int
+	options := &Options{
+		DryRun:   true,
+		Verbose:  true,
+		Workers:  4,
+		Stdout:   &stdout,
+		Stderr:   &bytes.Buffer{},
+		OnResult: func(models.FileResult) { results++ },
+	}
+	p := NewProcessor(tempDir, options)
+	stats, err := p.Process()
+	if err != nil {
+		t.Fatalf("Process() with 4 workers failed: %v", err)
+	}
+	if stats != expected {
+		t.Errorf("Process() with 4 workers = %+v, expected %+v", stats, expected)
+	}
+	if results != len(serial.Results()) || len(p.Results()) != len(serial.Results()) {
+		t.Errorf("Process() with 4 workers recorded %d results (%d reported), expected %d", len(p.Results()), results, len(serial.Results()))
+	}
+	if lines := strings.Count(stdout.String(), "Would update: "); lines != expected.Updated {
+		t.Errorf("Process() with 4 workers printed %d updates, expected %d", lines, expected.Updated)
+	}
+
+	// Writing the files gives the same result as a serial run
+	options.DryRun = false
+	if _, err := p.Process(); err != nil {
+		t.Fatalf("Process() with 4 workers failed: %v", err)
+	}
+	if stats, _ := serial.Process(); stats.Updated != 0 {
+		t.Errorf("Process() after 4 workers updated %d files, expected 0", stats.Updated)
+	}
+
+	// A failure stops the walk, and the files already handed out are recorded
+	t.Setenv("PATHFIX_HOOK_LOG", filepath.Join(tempDir, "hooks.log"))
+	t.Setenv("PATHFIX_HOOK_FAIL", "file")
+	for i := 0; i < 60; i += 3 {
+		name := filepath.Join(tempDir, fmt.Sprintf("dir%d", i%4), fmt.Sprintf("file%d.go", i))
+		os.WriteFile(name, []byte("package a\n"), 0644)
+	}
+	options.FailFast = true
+	p.config.Hooks.PerFile = []string{os.Args[0], "-test.run=^TestHookHelperProcess$", "--", "file"}
+	stats, err = p.Process()
+	if !errors.Is(err, ErrStopped) || stats.Errors == 0 {
+		t.Errorf("Process() with 4 workers and FailFast = %+v, %v, expected a failure", stats, err)
+	}
+	if len(p.Results()) >= len(serial.Results()) {
+		t.Errorf("Process() with 4 workers and FailFast = %+v with %d results, expected it to stop early", stats, len(p.Results()))
+	}
+}
@@ -0,0 +1,30 @@
+// File: pkg/report/csv.go
+package report
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// csvHeader names the columns of a CSV report
+var csvHeader = []string{"path", "language", "action", "reason", "error"}
+
+// WriteCSV writes one row per file with its path, language, action, and the
+// reason or error, after a header row naming the columns
+func WriteCSV(w io.Writer, results []models.FileResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, result := range results {
+		row := []string{result.Path, processor.Language(result.Path), result.Action, result.Reason, result.Error}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
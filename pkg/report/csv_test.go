@@ -0,0 +1,30 @@
+// File: pkg/report/csv_test.go
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestWriteCSV(t *testing.T) {
+	results := []models.FileResult{
+		{Path: "src/Main.GO", Action: models.ActionUpdated, Reason: models.ReasonStaleHeader},
+		{Path: "Makefile", Action: models.ActionSkipped, Reason: "unsupported file type"},
+		{Path: "lib/a,b.c", Action: models.ActionError, Error: "open lib/a,b.c: permission denied"},
+	}
+
+	var sb strings.Builder
+	if err := WriteCSV(&sb, results); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	expected := "path,language,action,reason,error\n" +
+		"src/Main.GO,.go,updated,stale header,\n" +
+		"Makefile,Makefile,skipped,unsupported file type,\n" +
+		"\"lib/a,b.c\",.c,error,,\"open lib/a,b.c: permission denied\"\n"
+	if sb.String() != expected {
+		t.Errorf("WriteCSV = %q, expected %q", sb.String(), expected)
+	}
+}
@@ -0,0 +1,169 @@
+// File: pkg/report/html.go
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// HTMLData is what an HTML report shows
+type HTMLData struct {
+	Report   models.Report         // The run, with diffs for the updated files
+	Coverage models.CoverageReport // Header coverage before the run
+}
+
+// htmlTable is a coverage table with the heading of its first column
+type htmlTable struct {
+	Title string
+	Rows  map[string]models.Coverage
+}
+
+// htmlCoverage is a row of a coverage table
+type htmlCoverage struct {
+	Name string
+	models.Coverage
+}
+
+// htmlDiffLine is a line of a diff with the class that colors it
+type htmlDiffLine struct {
+	Class string
+	Text  string
+}
+
+// WriteHTML writes a self-contained HTML page summarizing a run: its
+// statistics, coverage by directory and language, the files that failed or
+// were skipped, and each change with its diff in an expandable section
+func WriteHTML(w io.Writer, data HTMLData) error {
+	return htmlTemplate.Execute(w, data)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"coverageRows": coverageRows,
+	"diffLines":    diffLines,
+	"results":      resultsWith,
+	"percent":      func(p float64) string { return fmt.Sprintf("%.1f%%", p) },
+	"table": func(title string, rows map[string]models.Coverage) htmlTable {
+		return htmlTable{Title: title, Rows: rows}
+	},
+}).Parse(htmlSource))
+
+// coverageRows returns the entries of a coverage map sorted by name
+func coverageRows(coverage map[string]models.Coverage) []htmlCoverage {
+	rows := make([]htmlCoverage, 0, len(coverage))
+	for name, c := range coverage {
+		rows = append(rows, htmlCoverage{Name: name, Coverage: c})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return rows
+}
+
+// diffLines splits a unified diff into lines classified for coloring
+func diffLines(diff string) []htmlDiffLine {
+	var lines []htmlDiffLine
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			class = "meta"
+		case strings.HasPrefix(line, "+"):
+			class = "add"
+		case strings.HasPrefix(line, "-"):
+			class = "del"
+		}
+		lines = append(lines, htmlDiffLine{Class: class, Text: strings.TrimSuffix(line, "\n")})
+	}
+	return lines
+}
+
+// resultsWith returns the results with the given action
+func resultsWith(results []models.FileResult, action string) []models.FileResult {
+	var matched []models.FileResult
+	for _, result := range results {
+		if result.Action == action {
+			matched = append(matched, result)
+		}
+	}
+	return matched
+}
+
+const htmlSource = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>pathfix report: {{.Report.Root}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 70em; padding: 0 1em; color: #1f2328; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.25em; margin-top: 2em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+table { border-collapse: collapse; margin: .5em 0; }
+th, td { text-align: left; padding: .25em .8em; border-bottom: 1px solid #eaeef2; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+.summary td:first-child { color: #59636e; }
+.bar { display: inline-block; width: 8em; height: .7em; background: #eaeef2; vertical-align: middle; }
+.bar span { display: block; height: 100%; background: #2da44e; }
+details { margin: .3em 0; border: 1px solid #d0d7de; border-radius: 6px; }
+summary { cursor: pointer; padding: .4em .8em; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+summary .reason { color: #59636e; font-family: sans-serif; margin-left: .5em; }
+pre { margin: 0; padding: .5em .8em; overflow-x: auto; border-top: 1px solid #d0d7de; font-size: .85em; }
+pre span { display: block; white-space: pre; }
+.add { background: #dafbe1; }
+.del { background: #ffebe9; }
+.meta { color: #59636e; }
+.error { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>pathfix report</h1>
+<p><code>{{.Report.Root}}</code>{{if .Report.DryRun}} &mdash; dry run, no files were modified{{end}}{{if .Report.Stopped}} &mdash; stopped at the first error{{end}}</p>
+
+<h2>Summary</h2>
+{{with .Report.Stats}}<table class="summary">
+<tr><td>Files processed</td><td class="num">{{.Processed}}</td></tr>
+<tr><td>{{if $.Report.DryRun}}Would be updated{{else}}Updated{{end}}</td><td class="num">{{.Updated}}</td></tr>
+<tr><td>&nbsp;&nbsp;Missing a header</td><td class="num">{{.Missing}}</td></tr>
+<tr><td>&nbsp;&nbsp;With a stale header</td><td class="num">{{.Stale}}</td></tr>
+<tr><td>Lines added, removed, rewritten</td><td class="num">&#43;{{.LinesAdded}} &minus;{{.LinesRemoved}} ~{{.LinesRewritten}}</td></tr>
+<tr><td>Bytes added, removed</td><td class="num">&#43;{{.BytesAdded}} &minus;{{.BytesRemoved}}</td></tr>
+<tr><td>Skipped</td><td class="num">{{.Skipped}}</td></tr>
+<tr><td>Errors</td><td class="num">{{.Errors}}</td></tr>
+<tr><td>Warnings</td><td class="num">{{.Warnings}}</td></tr>
+</table>{{end}}
+<p>Headers up to date before the run: {{.Coverage.Total.Covered}} of {{.Coverage.Total.Files}} files ({{percent .Coverage.Total.Percent}}).</p>
+
+{{define "coverage"}}<table>
+<tr><th>{{.Title}}</th><th class="num">Files</th><th class="num">Up to date</th><th class="num">Coverage</th><th></th></tr>
+{{range coverageRows .Rows}}<tr><td><code>{{.Name}}</code></td><td class="num">{{.Files}}</td><td class="num">{{.Covered}}</td><td class="num">{{percent .Percent}}</td><td><span class="bar"><span style="width: {{printf "%.0f" .Percent}}%"></span></span></td></tr>
+{{end}}</table>{{end}}
+<h2>Coverage by Directory</h2>
+{{template "coverage" table "Directory" .Coverage.Directories}}
+
+<h2>Coverage by Language</h2>
+{{template "coverage" table "Language" .Coverage.Languages}}
+
+{{with results .Report.Results "updated"}}<h2>Changes ({{len .}})</h2>
+{{range .}}<details>
+<summary>{{.Path}}<span class="reason">{{.Reason}}</span></summary>
+{{if .Diff}}<pre>{{range diffLines .Diff}}<span class="{{.Class}}">{{.Text}}</span>{{end}}</pre>{{end}}
+</details>
+{{end}}{{end}}
+{{with results .Report.Results "error"}}<h2>Errors ({{len .}})</h2>
+<table>
+{{range .}}<tr><td><code>{{.Path}}</code></td><td class="error">{{.Error}}</td></tr>
+{{end}}</table>{{end}}
+{{with results .Report.Results "skipped"}}<details>
+<summary>Skipped files ({{len .}})</summary>
+<table>
+{{range .}}<tr><td><code>{{.Path}}</code></td><td>{{.Reason}}</td></tr>
+{{end}}</table>
+</details>{{end}}
+</body>
+</html>
+`
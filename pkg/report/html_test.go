@@ -0,0 +1,68 @@
+// File: pkg/report/html_test.go
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestWriteHTML(t *testing.T) {
+	data := HTMLData{
+		Report: models.Report{
+			Root:   "/repo",
+			DryRun: true,
+			Stats:  models.Stats{Processed: 3, Updated: 1, Missing: 1, Errors: 1, Skipped: 1},
+			Results: []models.FileResult{
+				{Path: "src/<main>.go", Action: models.ActionUpdated, Reason: models.ReasonMissingHeader,
+					Diff: "--- a/src/main.go\n+++ b/src/main.go\n@@ -1 +1,2 @@\n+// File: src/main.go\n package main\n"},
+				{Path: "lib/a.c", Action: models.ActionError, Error: "permission denied"},
+				{Path: "logo.png", Action: models.ActionSkipped, Reason: "known binary extension"},
+			},
+		},
+		Coverage: models.CoverageReport{
+			Total:       models.Coverage{Files: 2, Covered: 0, Percent: 0},
+			Directories: map[string]models.Coverage{"src": {Files: 1}, "lib": {Files: 1}},
+			Languages:   map[string]models.Coverage{".go": {Files: 1}, ".c": {Files: 1}},
+		},
+	}
+
+	var sb strings.Builder
+	if err := WriteHTML(&sb, data); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	page := sb.String()
+
+	for _, want := range []string{
+		"<code>/repo</code> &mdash; dry run",
+		"<summary>src/&lt;main&gt;.go<span class=\"reason\">missing header</span></summary>",
+		"<span class=\"add\">&#43;// File: src/main.go</span>",
+		"<span class=\"\"> package main</span>",
+		"<td class=\"error\">permission denied</td>",
+		"Skipped files (1)",
+		"Changes (1)",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("WriteHTML output does not contain %q", want)
+		}
+	}
+
+	// Directories are listed in order
+	if strings.Index(page, "<code>lib</code>") > strings.Index(page, "<code>src</code>") {
+		t.Errorf("WriteHTML listed directories out of order")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	lines := diffLines("--- a/x\n+++ b/x\n@@ -1 +1,2 @@\n+new\n-old\n same\n\\ No newline at end of file\n")
+	expected := []string{"meta", "meta", "meta", "add", "del", "", ""}
+	if len(lines) != len(expected) {
+		t.Fatalf("diffLines returned %d lines, expected %d", len(lines), len(expected))
+	}
+	for i, line := range lines {
+		if line.Class != expected[i] {
+			t.Errorf("diffLines line %d (%q) has class %q, expected %q", i, line.Text, line.Class, expected[i])
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// File: pkg/report/markdown.go
+package report
+
+import (
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// maxMarkdownFiles caps the files listed in each section of a Markdown
+// summary, which GitHub limits to 1 MiB per step
+const maxMarkdownFiles = 1000
+
+// MarkdownData is what a Markdown summary shows
+type MarkdownData struct {
+	Report         models.Report
+	TopDirectories []models.DirectoryStats // The directories with the most missing or stale headers
+}
+
+// markdownList is a section of files, up to maxMarkdownFiles of them
+type markdownList struct {
+	Results []models.FileResult
+	Total   int
+}
+
+// More returns the number of files left out of the list
+func (l markdownList) More() int {
+	return l.Total - len(l.Results)
+}
+
+// WriteMarkdown writes a summary of a run for a CI job page such as the
+// GitHub Actions step summary: its statistics, the directories needing
+// headers, and the changed and failed files in collapsible sections
+func WriteMarkdown(w io.Writer, data MarkdownData) error {
+	return markdownTemplate.Execute(w, data)
+}
+
+var markdownTemplate = template.Must(template.New("summary").Funcs(template.FuncMap{
+	"cell": markdownCell,
+	"code": markdownCode,
+	"line": markdownLine,
+	"list": func(results []models.FileResult, action string) markdownList {
+		matched := resultsWith(results, action)
+		list := markdownList{Results: matched, Total: len(matched)}
+		if len(matched) > maxMarkdownFiles {
+			list.Results = matched[:maxMarkdownFiles]
+		}
+		return list
+	},
+}).Parse(markdownSource))
+
+// markdownCell escapes text for a table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// markdownLine joins the lines of text with spaces
+func markdownLine(text string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(text)
+}
+
+// markdownCode returns text as an inline code span, with a fence longer than
+// any run of backticks in it
+func markdownCode(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + markdownLine(text) + fence
+}
+
+const markdownSource = `## pathfix{{if .Report.DryRun}} (dry run){{end}}
+
+{{with .Report.Stats}}{{if eq .Updated 0}}All headers are up to date.{{else}}{{.Updated}} files {{if $.Report.DryRun}}need{{else}}got{{end}} a header added or updated.{{end}}{{if $.Report.Stopped}} The run stopped at the first error.{{end}}
+
+| | Files |
+| --- | ---: |
+| Processed | {{.Processed}} |
+| {{if $.Report.DryRun}}Would be updated{{else}}Updated{{end}} | {{.Updated}} |
+| Missing a header | {{.Missing}} |
+| With a stale header | {{.Stale}} |
+| Skipped | {{.Skipped}} |
+| Errors | {{.Errors}} |
+| Warnings | {{.Warnings}} |
+{{end}}
+{{with .TopDirectories}}
+### Directories needing headers
+
+| Directory | Missing | Stale | Errors |
+| --- | ---: | ---: | ---: |
+{{range .}}| {{code .Directory | cell}} | {{.Stats.Missing}} | {{.Stats.Stale}} | {{.Stats.Errors}} |
+{{end}}{{end}}
+{{with list .Report.Results "updated"}}{{if .Results}}<details>
+<summary>Changed files ({{.Total}})</summary>
+
+{{range .Results}}- {{code .Path}}: {{.Reason}}
+{{end}}{{if .More}}- and {{.More}} more
+{{end}}
+</details>
+{{end}}{{end}}
+{{with list .Report.Results "error"}}{{if .Results}}<details>
+<summary>Errors ({{.Total}})</summary>
+
+{{range .Results}}- {{code .Path}}: {{line .Error}}
+{{end}}{{if .More}}- and {{.More}} more
+{{end}}
+</details>
+{{end}}{{end}}`
@@ -0,0 +1,68 @@
+// File: pkg/report/markdown_test.go
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	data := MarkdownData{
+		Report: models.Report{
+			Root:   "/src",
+			DryRun: true,
+			Stats:  models.Stats{Processed: 3, Updated: 1, Stale: 1, Errors: 1},
+			Results: []models.FileResult{
+				{Path: "a.go", Action: models.ActionUpdated, Reason: models.ReasonStaleHeader},
+				{Path: "b|`c`.go", Action: models.ActionError, Error: "permission\ndenied"},
+				{Path: "d.go", Action: models.ActionUnchanged},
+			},
+		},
+		TopDirectories: []models.DirectoryStats{{Directory: "lib|x", Stats: models.Stats{Stale: 1, Errors: 1}}},
+	}
+
+	var sb strings.Builder
+	if err := WriteMarkdown(&sb, data); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	output := sb.String()
+	for _, expected := range []string{
+		"## pathfix (dry run)",
+		"1 files need a header added or updated.",
+		"| Would be updated | 1 |",
+		"| `lib\\|x` | 0 | 1 | 1 |",
+		"<summary>Changed files (1)</summary>",
+		"- `a.go`: stale header",
+		"<summary>Errors (1)</summary>",
+		"- ``b|`c`.go``: permission denied",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("WriteMarkdown output lacks %q:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "d.go") {
+		t.Errorf("WriteMarkdown listed an unchanged file:\n%s", output)
+	}
+}
+
+func TestWriteMarkdownLimit(t *testing.T) {
+	var report models.Report
+	for i := 0; i < maxMarkdownFiles+5; i++ {
+		report.Results = append(report.Results, models.FileResult{Path: fmt.Sprintf("f%d.go", i), Action: models.ActionUpdated})
+	}
+
+	var sb strings.Builder
+	if err := WriteMarkdown(&sb, MarkdownData{Report: report}); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	output := sb.String()
+	if !strings.Contains(output, fmt.Sprintf("Changed files (%d)", maxMarkdownFiles+5)) || !strings.Contains(output, "- and 5 more") {
+		t.Errorf("WriteMarkdown did not report the 5 files left out")
+	}
+	if strings.Contains(output, fmt.Sprintf("`f%d.go`", maxMarkdownFiles)) {
+		t.Errorf("WriteMarkdown listed more than %d files", maxMarkdownFiles)
+	}
+}
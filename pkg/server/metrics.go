@@ -0,0 +1,262 @@
+// File: pkg/server/metrics.go
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// runDurationBuckets are the upper bounds, in seconds, of the run duration histogram
+var runDurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900}
+
+// metrics accumulates counters over the server's lifetime
+type metrics struct {
+	mu             sync.Mutex
+	files          map[[3]string]int // root, mode, action
+	runs           map[[3]string]int // root, mode, status
+	durationCounts map[[2]string][]int
+	durationSums   map[[2]string]float64
+	lastCompletion map[string]time.Time
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		files:          make(map[[3]string]int),
+		runs:           make(map[[3]string]int),
+		durationCounts: make(map[[2]string][]int),
+		durationSums:   make(map[[2]string]float64),
+		lastCompletion: make(map[string]time.Time),
+	}
+}
+
+// observeFile counts the outcome of a single file
+func (m *metrics) observeFile(root, mode string, result models.FileResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[[3]string{root, mode, result.Action}]++
+}
+
+// observeRun counts a finished run and its duration
+func (m *metrics) observeRun(root, mode, status string, started, finished time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runs[[3]string{root, mode, status}]++
+	key := [2]string{root, mode}
+	counts, ok := m.durationCounts[key]
+	if !ok {
+		counts = make([]int, len(runDurationBuckets)+1)
+		m.durationCounts[key] = counts
+	}
+	seconds := finished.Sub(started).Seconds()
+	for i, bound := range runDurationBuckets {
+		if seconds <= bound {
+			counts[i]++
+		}
+	}
+	counts[len(runDurationBuckets)]++
+	m.durationSums[key] += seconds
+	if status == StatusCompleted {
+		m.lastCompletion[root] = finished
+	}
+}
+
+// write renders the metrics in the Prometheus text exposition format.
+// queueDepth holds the files waiting per root where runs are queued, as in
+// watch mode.
+func (m *metrics) write(w io.Writer, inProgress, queueDepth map[string]int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP pathfix_files_total Files visited by runs, by outcome.")
+	fmt.Fprintln(w, "# TYPE pathfix_files_total counter")
+	for _, key := range sortedKeys3(m.files) {
+		fmt.Fprintf(w, "pathfix_files_total{root=%s,mode=%s,action=%s} %d\n",
+			quoteLabel(key[0]), quoteLabel(key[1]), quoteLabel(key[2]), m.files[key])
+	}
+
+	fmt.Fprintln(w, "# HELP pathfix_runs_total Finished runs, by final status.")
+	fmt.Fprintln(w, "# TYPE pathfix_runs_total counter")
+	for _, key := range sortedKeys3(m.runs) {
+		fmt.Fprintf(w, "pathfix_runs_total{root=%s,mode=%s,status=%s} %d\n",
+			quoteLabel(key[0]), quoteLabel(key[1]), quoteLabel(key[2]), m.runs[key])
+	}
+
+	fmt.Fprintln(w, "# HELP pathfix_run_duration_seconds Duration of finished runs.")
+	fmt.Fprintln(w, "# TYPE pathfix_run_duration_seconds histogram")
+	durationKeys := make([][2]string, 0, len(m.durationCounts))
+	for key := range m.durationCounts {
+		durationKeys = append(durationKeys, key)
+	}
+	sort.Slice(durationKeys, func(i, j int) bool {
+		return durationKeys[i][0] < durationKeys[j][0] ||
+			durationKeys[i][0] == durationKeys[j][0] && durationKeys[i][1] < durationKeys[j][1]
+	})
+	for _, key := range durationKeys {
+		labels := fmt.Sprintf("root=%s,mode=%s", quoteLabel(key[0]), quoteLabel(key[1]))
+		counts := m.durationCounts[key]
+		for i, bound := range runDurationBuckets {
+			fmt.Fprintf(w, "pathfix_run_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(bound, 'g', -1, 64), counts[i])
+		}
+		total := counts[len(runDurationBuckets)]
+		fmt.Fprintf(w, "pathfix_run_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, total)
+		fmt.Fprintf(w, "pathfix_run_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(m.durationSums[key], 'g', -1, 64))
+		fmt.Fprintf(w, "pathfix_run_duration_seconds_count{%s} %d\n", labels, total)
+	}
+
+	fmt.Fprintln(w, "# HELP pathfix_last_run_completion_timestamp_seconds Unix time of the last successfully completed run.")
+	fmt.Fprintln(w, "# TYPE pathfix_last_run_completion_timestamp_seconds gauge")
+	roots := make([]string, 0, len(m.lastCompletion))
+	for root := range m.lastCompletion {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	for _, root := range roots {
+		fmt.Fprintf(w, "pathfix_last_run_completion_timestamp_seconds{root=%s} %d\n", quoteLabel(root), m.lastCompletion[root].Unix())
+	}
+
+	fmt.Fprintln(w, "# HELP pathfix_runs_in_progress Runs currently in progress; at most one per root.")
+	fmt.Fprintln(w, "# TYPE pathfix_runs_in_progress gauge")
+	roots = roots[:0]
+	for root := range inProgress {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	for _, root := range roots {
+		fmt.Fprintf(w, "pathfix_runs_in_progress{root=%s} %d\n", quoteLabel(root), inProgress[root])
+	}
+
+	fmt.Fprintln(w, "# HELP pathfix_queue_depth Files waiting for the next run, in watch mode.")
+	fmt.Fprintln(w, "# TYPE pathfix_queue_depth gauge")
+	roots = roots[:0]
+	for root := range queueDepth {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	for _, root := range roots {
+		fmt.Fprintf(w, "pathfix_queue_depth{root=%s} %d\n", quoteLabel(root), queueDepth[root])
+	}
+}
+
+// sortedKeys3 returns the keys of a three-label counter in a stable order
+func sortedKeys3(counter map[[3]string]int) [][3]string {
+	keys := make([][3]string, 0, len(counter))
+	for key := range counter {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00")
+	})
+	return keys
+}
+
+// quoteLabel quotes a label value, escaping backslashes, quotes and newlines
+func quoteLabel(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
+
+// handleMetrics serves the metrics to Prometheus
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// Every root is reported, idle ones as 0, so alerts can tell them apart from missing series
+	inProgress := make(map[string]int, len(s.roots))
+	s.mu.Lock()
+	for name := range s.roots {
+		inProgress[name] = 0
+	}
+	for name := range s.active {
+		inProgress[name] = 1
+	}
+	s.mu.Unlock()
+
+	// Runs are not queued; one on a busy root is refused
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.write(w, inProgress, nil)
+}
+
+// WatchMetrics collects the same metrics for a processor that watches its
+// root, as fix --watch does, where each batch of new files is a run. The
+// root is reported under the given name, and runs in the given mode.
+type WatchMetrics struct {
+	metrics *metrics
+	root    string
+	mode    string
+
+	mu      sync.Mutex
+	depth   int
+	started time.Time // When the run in progress started, or zero
+}
+
+// NewWatchMetrics returns the metrics of a watched root
+func NewWatchMetrics(root, mode string) *WatchMetrics {
+	return &WatchMetrics{metrics: newMetrics(), root: root, mode: mode}
+}
+
+// Observe sets the callbacks of options that feed the metrics, keeping an
+// OnResult that is already set
+func (m *WatchMetrics) Observe(options *processor.Options) {
+	onResult := options.OnResult
+	options.OnResult = func(result models.FileResult) {
+		m.metrics.observeFile(m.root, m.mode, result)
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+	options.OnWatchQueue = func(depth int) {
+		m.mu.Lock()
+		m.depth = depth
+		m.mu.Unlock()
+	}
+	options.OnWatchRun = func() {
+		m.mu.Lock()
+		m.started = time.Now()
+		m.mu.Unlock()
+	}
+	options.OnWatchRunDone = func(err error) {
+		m.mu.Lock()
+		started := m.started
+		m.started = time.Time{}
+		m.mu.Unlock()
+
+		status := StatusCompleted
+		var fileErrors processor.FileErrors
+		if err != nil && !errors.As(err, &fileErrors) {
+			status = StatusFailed
+		}
+		m.metrics.observeRun(m.root, m.mode, status, started, time.Now())
+	}
+}
+
+// ServeHTTP serves the metrics to Prometheus
+func (m *WatchMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	m.mu.Lock()
+	inProgress := map[string]int{m.root: 0}
+	if !m.started.IsZero() {
+		inProgress[m.root] = 1
+	}
+	queueDepth := map[string]int{m.root: m.depth}
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.metrics.write(w, inProgress, queueDepth)
+}
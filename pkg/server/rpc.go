@@ -0,0 +1,147 @@
+// File: pkg/server/rpc.go
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// rpcRequest is a JSON-RPC 2.0 request, or a notification when ID is absent
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the error member of a response
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// FixParams are the parameters of the "fix" method
+type FixParams struct {
+	Path string `json:"path"` // File path, absolute or relative to the root directory
+	Text string `json:"text"` // Current buffer contents
+}
+
+// FixResult is the result of the "fix" method. Text holds the buffer to
+// save, which is the input unchanged unless Action is "updated".
+type FixResult struct {
+	Text string `json:"text"`
+	models.FileResult
+}
+
+// ServeRPC answers JSON-RPC 2.0 requests read from in, one message per line,
+// writing one response per line to out, until in is exhausted or a
+// "shutdown" request arrives. The processor and its configuration are
+// created once, so editors can keep the process open across saves.
+//
+// Methods:
+//
+//	fix       {"path": "src/main.go", "text": "..."} -> FixResult
+//	shutdown  stops the server after responding
+func ServeRPC(in io.Reader, out io.Writer, rootDir string, options processor.Options) error {
+	options.DryRun = true
+	p := processor.NewProcessor(rootDir, &options)
+	// Replies are the only output, even when the user config asks for more
+	options.Verbose = false
+
+	reader := bufio.NewReader(in)
+	encoder := json.NewEncoder(out)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			response, stop := handleRPC(p, rootDir, line)
+			if response != nil {
+				if err := encoder.Encode(response); err != nil {
+					return err
+				}
+			}
+			if stop {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// handleRPC answers a single message. It returns a nil response for
+// notifications, and whether the server should stop.
+func handleRPC(p *processor.Processor, rootDir string, line []byte) (*rpcResponse, bool) {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return rpcFailure(nil, rpcParseError, "parse error: "+err.Error()), false
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcFailure(req.ID, rpcInvalidRequest, "invalid request"), false
+	}
+
+	var response *rpcResponse
+	stop := false
+	switch req.Method {
+	case "fix":
+		var params FixParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Path == "" {
+			response = rpcFailure(req.ID, rpcInvalidParams, "fix requires a path and text")
+			break
+		}
+		relPath := params.Path
+		if filepath.IsAbs(relPath) {
+			if rel, err := filepath.Rel(rootDir, relPath); err == nil {
+				relPath = rel
+			}
+		}
+		text, result := p.FixBuffer(relPath, []byte(params.Text))
+		response = &rpcResponse{Result: FixResult{Text: string(text), FileResult: result}}
+	case "shutdown":
+		response = &rpcResponse{Result: struct{}{}}
+		stop = true
+	default:
+		response = rpcFailure(req.ID, rpcMethodNotFound, "method not found: "+req.Method)
+	}
+
+	// Notifications get no response
+	if req.ID == nil {
+		return nil, stop
+	}
+	response.JSONRPC = "2.0"
+	response.ID = req.ID
+	return response, stop
+}
+
+// rpcFailure builds an error response
+func rpcFailure(id json.RawMessage, code int, message string) *rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
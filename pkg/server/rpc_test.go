@@ -0,0 +1,96 @@
+// File: pkg/server/rpc_test.go
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+func TestServeRPC(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-rpc-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("gen/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+
+	input := strings.Join([]string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "fix", "params": {"path": "src/main.go", "text": "package main\n"}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "fix", "params": {"path": "src/main.go", "text": "// File: src/main.go\npackage main\n"}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "fix", "params": {"path": "` + filepath.ToSlash(filepath.Join(tempDir, "app.py")) + `", "text": "#!/usr/bin/env python\nprint()\n"}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "fix", "params": {"path": "gen/out.go", "text": "package gen\n"}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "fix", "params": {"path": "notes.xyz", "text": "notes\n"}}`,
+		`{"jsonrpc": "2.0", "method": "fix", "params": {"path": "a.go", "text": ""}}`,
+		`{"jsonrpc": "2.0", "id": 6, "method": "format"}`,
+		`not json`,
+		`{"jsonrpc": "2.0", "id": 7, "method": "shutdown"}`,
+		`{"jsonrpc": "2.0", "id": 8, "method": "fix", "params": {"path": "after.go", "text": ""}}`,
+	}, "\n")
+
+	var output bytes.Buffer
+	if err := ServeRPC(strings.NewReader(input), &output, tempDir, processor.Options{}); err != nil {
+		t.Fatalf("ServeRPC failed: %v", err)
+	}
+
+	type response struct {
+		ID     json.RawMessage
+		Result *FixResult
+		Error  *rpcError
+	}
+	var responses []response
+	decoder := json.NewDecoder(&output)
+	for decoder.More() {
+		var r response
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		responses = append(responses, r)
+	}
+
+	// The notification gets no response, and nothing is read after shutdown
+	if len(responses) != 8 {
+		t.Fatalf("ServeRPC wrote %d responses, expected 8", len(responses))
+	}
+
+	tests := []struct {
+		id     string
+		action string
+		text   string
+	}{
+		{"1", "updated", "// File: src/main.go\npackage main\n"},
+		{"2", "unchanged", "// File: src/main.go\npackage main\n"},
+		{"3", "updated", "#!/usr/bin/env python\n# File: app.py\nprint()\n"},
+		{"4", "skipped", "package gen\n"},
+		{"5", "skipped", "notes\n"},
+	}
+	for i, test := range tests {
+		r := responses[i]
+		if string(r.ID) != test.id || r.Result == nil {
+			t.Errorf("response %d = %+v, expected a result for id %s", i, r, test.id)
+			continue
+		}
+		if r.Result.Action != test.action || r.Result.Text != test.text {
+			t.Errorf("fix(id %s) = %s %q, expected %s %q", test.id, r.Result.Action, r.Result.Text, test.action, test.text)
+		}
+	}
+
+	errorCodes := []int{rpcMethodNotFound, rpcParseError}
+	for i, code := range errorCodes {
+		r := responses[len(tests)+i]
+		if r.Error == nil || r.Error.Code != code {
+			t.Errorf("response %d error = %+v, expected code %d", len(tests)+i, r.Error, code)
+		}
+	}
+	if last := responses[len(responses)-1]; string(last.ID) != "7" || last.Error != nil {
+		t.Errorf("shutdown response = %+v, expected a result for id 7", last)
+	}
+}
@@ -0,0 +1,402 @@
+// File: pkg/server/server.go
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// Run modes accepted by the API
+const (
+	ModeFix   = "fix"   // Add or update headers
+	ModeCheck = "check" // Report files whose headers are missing or stale without modifying them
+)
+
+// Run states
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// maxRuns is how many finished runs are kept for report retrieval
+const maxRuns = 100
+
+// Root is a directory the server is allowed to process
+type Root struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Options configures the server
+type Options struct {
+	Roots     []Root            // Directories runs may target, addressed by name
+	Token     string            // Bearer token required on every request except /healthz; empty disables auth
+	Processor processor.Options // Template for each run's processor options (DryRun is set from the mode)
+}
+
+// Server exposes pathfix runs over HTTP
+type Server struct {
+	options Options
+	roots   map[string]Root
+	metrics *metrics
+
+	mu     sync.Mutex
+	runs   map[string]*run
+	order  []string          // Run IDs, oldest first
+	active map[string]string // Root name to the ID of its running run
+	nextID int
+}
+
+// run tracks a single fix or check run
+type run struct {
+	mu       sync.Mutex
+	id       string
+	root     string
+	mode     string
+	status   string
+	started  time.Time
+	finished time.Time
+	stats    models.Stats
+	results  []models.FileResult
+	err      string
+	changed  chan struct{} // Closed and replaced whenever the run makes progress
+}
+
+// Report is the JSON representation of a run
+type Report struct {
+	ID       string              `json:"id"`
+	Root     string              `json:"root"`
+	Mode     string              `json:"mode"`
+	Status   string              `json:"status"`
+	OK       bool                `json:"ok"` // No errors, and for check runs no pending updates
+	Started  time.Time           `json:"started"`
+	Finished *time.Time          `json:"finished,omitempty"`
+	Stats    models.Stats        `json:"stats"`
+	Error    string              `json:"error,omitempty"`
+	Results  []models.FileResult `json:"results,omitempty"`
+}
+
+// runRequest is the body of POST /runs
+type runRequest struct {
+	Root string `json:"root"`
+	Mode string `json:"mode"`
+}
+
+// New creates a server for the given roots
+func New(options Options) (*Server, error) {
+	s := &Server{
+		options: options,
+		roots:   make(map[string]Root),
+		metrics: newMetrics(),
+		runs:    make(map[string]*run),
+		active:  make(map[string]string),
+	}
+	for _, root := range options.Roots {
+		if root.Name == "" {
+			return nil, fmt.Errorf("root %s has no name", root.Path)
+		}
+		if _, ok := s.roots[root.Name]; ok {
+			return nil, fmt.Errorf("duplicate root name: %s", root.Name)
+		}
+		s.roots[root.Name] = root
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler serving the API:
+//
+//	GET  /healthz            liveness probe, never requires auth
+//	GET  /roots              configured roots
+//	GET  /runs               summaries of recent runs
+//	POST /runs               start a run: {"root": "name", "mode": "fix"|"check"}
+//	GET  /runs/{id}          full report of a run
+//	GET  /runs/{id}/events   per-file results as newline-delimited JSON until the run ends
+//	GET  /metrics            Prometheus metrics
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/roots", s.authorize(http.HandlerFunc(s.handleRoots)))
+	mux.Handle("/runs", s.authorize(http.HandlerFunc(s.handleRuns)))
+	mux.Handle("/runs/", s.authorize(http.HandlerFunc(s.handleRun)))
+	mux.Handle("/metrics", s.authorize(http.HandlerFunc(s.handleMetrics)))
+	return mux
+}
+
+// authorize rejects requests without the configured bearer token
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.options.Token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.options.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="pathfix"`)
+				writeError(w, http.StatusUnauthorized, "missing or invalid token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleRoots lists the configured roots
+func (s *Server) handleRoots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	roots := append([]Root(nil), s.options.Roots...)
+	sort.Slice(roots, func(i, j int) bool { return roots[i].Name < roots[j].Name })
+	writeJSON(w, http.StatusOK, roots)
+}
+
+// handleRuns lists runs or starts a new one
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		reports := make([]Report, 0, len(s.order))
+		for _, id := range s.order {
+			reports = append(reports, s.runs[id].report(false))
+		}
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, reports)
+	case http.MethodPost:
+		var req runRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if req.Mode == "" {
+			req.Mode = ModeFix
+		}
+		if req.Mode != ModeFix && req.Mode != ModeCheck {
+			writeError(w, http.StatusBadRequest, "unknown mode: "+req.Mode)
+			return
+		}
+		rn, status, err := s.start(req.Root, req.Mode)
+		if err != nil {
+			writeError(w, status, err.Error())
+			return
+		}
+		w.Header().Set("Location", "/runs/"+rn.id)
+		writeJSON(w, http.StatusAccepted, rn.report(false))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleRun serves a run's report or its event stream
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/runs/"), "/")
+
+	s.mu.Lock()
+	rn, ok := s.runs[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown run: "+id)
+		return
+	}
+
+	switch sub {
+	case "":
+		writeJSON(w, http.StatusOK, rn.report(true))
+	case "events":
+		s.streamEvents(w, r, rn)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// start launches a run on the named root unless one is already in progress there
+func (s *Server) start(rootName, mode string) (*run, int, error) {
+	root, ok := s.roots[rootName]
+	if !ok {
+		return nil, http.StatusNotFound, fmt.Errorf("unknown root: %s", rootName)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, busy := s.active[rootName]; busy {
+		return nil, http.StatusConflict, fmt.Errorf("run %s is already in progress on %s", id, rootName)
+	}
+
+	s.nextID++
+	rn := &run{
+		id:      strconv.Itoa(s.nextID),
+		root:    rootName,
+		mode:    mode,
+		status:  StatusRunning,
+		started: time.Now(),
+		changed: make(chan struct{}),
+	}
+	s.runs[rn.id] = rn
+	s.order = append(s.order, rn.id)
+	s.active[rootName] = rn.id
+	s.pruneLocked()
+
+	go s.execute(rn, root)
+	return rn, http.StatusAccepted, nil
+}
+
+// pruneLocked forgets the oldest finished runs beyond maxRuns
+func (s *Server) pruneLocked() {
+	for i := 0; len(s.order) > maxRuns && i < len(s.order); {
+		id := s.order[i]
+		if s.active[s.runs[id].root] == id {
+			i++
+			continue
+		}
+		delete(s.runs, id)
+		s.order = append(s.order[:i], s.order[i+1:]...)
+	}
+}
+
+// execute performs the run and releases the root's lock
+func (s *Server) execute(rn *run, root Root) {
+	options := s.options.Processor
+	options.DryRun = rn.mode == ModeCheck
+	options.Verbose = false
+	options.OnResult = func(result models.FileResult) {
+		s.metrics.observeFile(rn.root, rn.mode, result)
+		rn.add(result)
+	}
+
+	// A root whose config file cannot be loaded fails instead of being
+	// processed with the defaults
+	var stats models.Stats
+	p := processor.NewProcessor(root.Path, &options)
+	err := p.ConfigError()
+	if err == nil {
+		stats, err = p.Process()
+	}
+
+	// Release the root before publishing the result so that clients which
+	// see the run finish can start the next one immediately
+	s.mu.Lock()
+	delete(s.active, rn.root)
+	s.mu.Unlock()
+
+	rn.mu.Lock()
+	rn.stats = stats
+	rn.finished = time.Now()
+	rn.status = StatusCompleted
+	var fileErrors processor.FileErrors
+	if err != nil && !errors.As(err, &fileErrors) {
+		rn.status = StatusFailed
+		rn.err = err.Error()
+	}
+	s.metrics.observeRun(rn.root, rn.mode, rn.status, rn.started, rn.finished)
+	rn.notifyLocked()
+	rn.mu.Unlock()
+}
+
+// add records a file result as the walk progresses
+func (rn *run) add(result models.FileResult) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.results = append(rn.results, result)
+	rn.notifyLocked()
+}
+
+// notifyLocked wakes up event streams waiting on the run
+func (rn *run) notifyLocked() {
+	close(rn.changed)
+	rn.changed = make(chan struct{})
+}
+
+// report snapshots the run, optionally with its per-file results
+func (rn *run) report(withResults bool) Report {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+
+	report := Report{
+		ID:      rn.id,
+		Root:    rn.root,
+		Mode:    rn.mode,
+		Status:  rn.status,
+		Started: rn.started,
+		Stats:   rn.stats,
+		Error:   rn.err,
+	}
+	if !rn.finished.IsZero() {
+		finished := rn.finished
+		report.Finished = &finished
+		report.OK = rn.status == StatusCompleted && rn.stats.Errors == 0 &&
+			(rn.mode != ModeCheck || rn.stats.Updated == 0)
+	}
+	if withResults {
+		report.Results = append([]models.FileResult(nil), rn.results...)
+	}
+	return report
+}
+
+// streamEvents writes each file result as a line of JSON, followed by the
+// final report once the run ends
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request, rn *run) {
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	sent := 0
+	for {
+		rn.mu.Lock()
+		pending := append([]models.FileResult(nil), rn.results[sent:]...)
+		done := !rn.finished.IsZero()
+		changed := rn.changed
+		rn.mu.Unlock()
+
+		for _, result := range pending {
+			if err := encoder.Encode(result); err != nil {
+				return
+			}
+		}
+		sent += len(pending)
+
+		if done {
+			encoder.Encode(rn.report(false))
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeJSON writes v as the response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response body
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
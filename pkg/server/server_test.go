@@ -0,0 +1,309 @@
+// File: pkg/server/server_test.go
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// newTestServer starts a server with a single root containing one Go file
+func newTestServer(t *testing.T, token string) (*httptest.Server, string) {
+	tempDir, err := os.MkdirTemp("", "pathfix-server-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	s, err := New(Options{
+		Roots: []Root{{Name: "repo", Path: tempDir}},
+		Token: token,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return ts, tempDir
+}
+
+// do sends a request with the bearer token and decodes the JSON response into v
+func do(t *testing.T, method, url, token, body string, v interface{}) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("Failed to decode response of %s %s: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// waitForRun reads the event stream of a run until its final report
+func waitForRun(t *testing.T, ts *httptest.Server, token, id string) ([]string, Report) {
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/runs/"+id+"/events", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to stream events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) == 0 {
+		t.Fatalf("Event stream of run %s was empty", id)
+	}
+	var report Report
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &report); err != nil {
+		t.Fatalf("Failed to decode final report: %v", err)
+	}
+	return lines[:len(lines)-1], report
+}
+
+func TestServerAuth(t *testing.T) {
+	ts, _ := newTestServer(t, "secret")
+
+	tests := []struct {
+		path   string
+		token  string
+		status int
+	}{
+		{"/healthz", "", http.StatusOK},
+		{"/roots", "", http.StatusUnauthorized},
+		{"/roots", "wrong", http.StatusUnauthorized},
+		{"/roots", "secret", http.StatusOK},
+		{"/runs", "secret", http.StatusOK},
+		{"/runs/42", "secret", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		if status := do(t, http.MethodGet, ts.URL+test.path, test.token, "", nil); status != test.status {
+			t.Errorf("GET %s with token %q = %d, expected %d", test.path, test.token, status, test.status)
+		}
+	}
+
+	// The token is only accepted as a bearer token
+	for _, header := range []string{"secret", "Basic secret", "Bearersecret"} {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/roots", nil)
+		req.Header.Set("Authorization", header)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /roots failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("GET /roots with Authorization %q = %d, expected %d", header, resp.StatusCode, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestServerRuns(t *testing.T) {
+	ts, tempDir := newTestServer(t, "secret")
+
+	// A check run reports the missing header without touching the file
+	var started Report
+	if status := do(t, http.MethodPost, ts.URL+"/runs", "secret", `{"root": "repo", "mode": "check"}`, &started); status != http.StatusAccepted {
+		t.Fatalf("POST /runs = %d, expected %d", status, http.StatusAccepted)
+	}
+	events, report := waitForRun(t, ts, "secret", started.ID)
+	if len(events) != 1 || !strings.Contains(events[0], `"path":"main.go"`) {
+		t.Errorf("check run events = %v, expected one event for main.go", events)
+	}
+	if report.Status != StatusCompleted || report.OK || report.Stats.Updated != 1 {
+		t.Errorf("check run report = %+v, expected a completed run with one pending update", report)
+	}
+	content, _ := os.ReadFile(filepath.Join(tempDir, "main.go"))
+	if string(content) != "package main\n" {
+		t.Errorf("check run modified main.go: %q", content)
+	}
+
+	// A fix run adds the header, after which a check run passes
+	if status := do(t, http.MethodPost, ts.URL+"/runs", "secret", `{"root": "repo"}`, &started); status != http.StatusAccepted {
+		t.Fatalf("POST /runs = %d, expected %d", status, http.StatusAccepted)
+	}
+	if _, report = waitForRun(t, ts, "secret", started.ID); !report.OK {
+		t.Errorf("fix run report = %+v, expected ok", report)
+	}
+	content, _ = os.ReadFile(filepath.Join(tempDir, "main.go"))
+	if !strings.HasPrefix(string(content), "// File: main.go\n") {
+		t.Errorf("fix run did not add the header: %q", content)
+	}
+
+	do(t, http.MethodPost, ts.URL+"/runs", "secret", `{"root": "repo", "mode": "check"}`, &started)
+	waitForRun(t, ts, "secret", started.ID)
+	var full Report
+	do(t, http.MethodGet, ts.URL+"/runs/"+started.ID, "secret", "", &full)
+	if !full.OK || len(full.Results) != 1 || full.Results[0].Action != "unchanged" {
+		t.Errorf("GET /runs/%s = %+v, expected an ok report with main.go unchanged", started.ID, full)
+	}
+}
+
+func TestServerConfigError(t *testing.T) {
+	ts, tempDir := newTestServer(t, "secret")
+	if err := os.WriteFile(filepath.Join(tempDir, ".pathfix.json"), []byte("{"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// A root whose config cannot be loaded fails without being processed
+	var started Report
+	if status := do(t, http.MethodPost, ts.URL+"/runs", "secret", `{"root": "repo"}`, &started); status != http.StatusAccepted {
+		t.Fatalf("POST /runs = %d, expected %d", status, http.StatusAccepted)
+	}
+	if _, report := waitForRun(t, ts, "secret", started.ID); report.Status != StatusFailed || report.Error == "" {
+		t.Errorf("run report = %+v, expected a failed run", report)
+	}
+	content, _ := os.ReadFile(filepath.Join(tempDir, "main.go"))
+	if string(content) != "package main\n" {
+		t.Errorf("run with an invalid config modified main.go: %q", content)
+	}
+}
+
+func TestServerRejectsInvalidRuns(t *testing.T) {
+	ts, _ := newTestServer(t, "")
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"root": "missing"}`, http.StatusNotFound},
+		{`{"root": "repo", "mode": "delete"}`, http.StatusBadRequest},
+		{`not json`, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		if status := do(t, http.MethodPost, ts.URL+"/runs", "", test.body, nil); status != test.status {
+			t.Errorf("POST /runs %s = %d, expected %d", test.body, status, test.status)
+		}
+	}
+}
+
+func TestServerLocksRoot(t *testing.T) {
+	s, err := New(Options{Roots: []Root{{Name: "repo", Path: "."}}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// Simulate a run in progress
+	s.active["repo"] = "1"
+	if _, status, err := s.start("repo", ModeFix); err == nil || status != http.StatusConflict {
+		t.Errorf("start(repo) = %d, %v, expected %d", status, err, http.StatusConflict)
+	}
+}
+
+func TestServerMetrics(t *testing.T) {
+	ts, _ := newTestServer(t, "secret")
+
+	var started Report
+	do(t, http.MethodPost, ts.URL+"/runs", "secret", `{"root": "repo"}`, &started)
+	waitForRun(t, ts, "secret", started.ID)
+
+	if status := do(t, http.MethodGet, ts.URL+"/metrics", "", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("GET /metrics without token = %d, expected %d", status, http.StatusUnauthorized)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	expected := []string{
+		`pathfix_files_total{root="repo",mode="fix",action="updated"} 1`,
+		`pathfix_runs_total{root="repo",mode="fix",status="completed"} 1`,
+		`pathfix_run_duration_seconds_bucket{root="repo",mode="fix",le="+Inf"} 1`,
+		`pathfix_run_duration_seconds_count{root="repo",mode="fix"} 1`,
+		`pathfix_last_run_completion_timestamp_seconds{root="repo"} `,
+		`pathfix_runs_in_progress{root="repo"} 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("GET /metrics is missing %q:\n%s", line, body)
+		}
+	}
+}
+
+func TestWatchMetrics(t *testing.T) {
+	metrics := NewWatchMetrics("repo", ModeFix)
+	var options processor.Options
+	metrics.Observe(&options)
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return recorder.Body.String()
+	}
+
+	// New files wait in the queue until their run starts
+	options.OnWatchQueue(3)
+	if body := scrape(); !strings.Contains(body, `pathfix_queue_depth{root="repo"} 3`) || !strings.Contains(body, `pathfix_runs_in_progress{root="repo"} 0`) {
+		t.Errorf("metrics with 3 queued files:\n%s", body)
+	}
+	options.OnWatchQueue(0)
+	options.OnWatchRun()
+	options.OnResult(models.FileResult{Path: "main.go", Action: models.ActionUpdated})
+	if body := scrape(); !strings.Contains(body, `pathfix_queue_depth{root="repo"} 0`) || !strings.Contains(body, `pathfix_runs_in_progress{root="repo"} 1`) {
+		t.Errorf("metrics during a run:\n%s", body)
+	}
+
+	// Files that fail do not fail the run
+	options.OnWatchRunDone(processor.FileErrors{})
+	body := scrape()
+	expected := []string{
+		`pathfix_files_total{root="repo",mode="fix",action="updated"} 1`,
+		`pathfix_runs_total{root="repo",mode="fix",status="completed"} 1`,
+		`pathfix_run_duration_seconds_count{root="repo",mode="fix"} 1`,
+		`pathfix_runs_in_progress{root="repo"} 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("metrics after a run are missing %q:\n%s", line, body)
+		}
+	}
+}
+
+func TestQuoteLabel(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"repo", `"repo"`},
+		{`a"b`, `"a\"b"`},
+		{`C:\src`, `"C:\\src"`},
+		{"a\nb", `"a\nb"`},
+	}
+
+	for _, test := range tests {
+		if result := quoteLabel(test.value); result != test.expected {
+			t.Errorf("quoteLabel(%q) = %s, expected %s", test.value, result, test.expected)
+		}
+	}
+}
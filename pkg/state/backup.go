@@ -0,0 +1,203 @@
+// File: pkg/state/backup.go
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BackupDir holds the backups inside the state directory, one directory per
+// run named after its start time
+const BackupDir = "backup"
+
+// backupManifest lists a backup's files, one JSON BackupFile per line. The
+// copies themselves are kept beneath backupFiles, at their relative paths.
+const (
+	backupManifest = "manifest.jsonl"
+	backupFiles    = "files"
+)
+
+// backupTimeFormat names backup directories so that they sort by time
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+// BackupFile is a file saved in a backup before it was modified
+type BackupFile struct {
+	Path string      `json:"path"`   // Relative to the root, with forward slashes
+	Mode fs.FileMode `json:"mode"`   // Permissions of the original
+	Hash string      `json:"sha256"` // Of the content the run wrote, to detect later changes
+}
+
+// Backup holds the original content of the files a run modified, so that
+// the run can be undone. It is safe for concurrent use, and its directory is
+// created when the first file is saved.
+type Backup struct {
+	dir  *Dir
+	path string
+
+	mu sync.Mutex // Serializes saves
+}
+
+// NewBackup returns the backup of a run that started at the given time
+func (d *Dir) NewBackup(start time.Time) *Backup {
+	return &Backup{dir: d, path: filepath.Join(d.File(BackupDir), start.UTC().Format(backupTimeFormat))}
+}
+
+// Backups returns the recorded backups, oldest first
+func (d *Dir) Backups() ([]*Backup, error) {
+	entries, err := os.ReadDir(d.File(BackupDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading backups: %w", err)
+	}
+	var backups []*Backup
+	for _, entry := range entries {
+		if entry.IsDir() {
+			backups = append(backups, &Backup{dir: d, path: filepath.Join(d.File(BackupDir), entry.Name())})
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].path < backups[j].path })
+	return backups, nil
+}
+
+// LatestBackup returns the backup of the last run that modified files, or
+// nil if there is none
+func (d *Dir) LatestBackup() (*Backup, error) {
+	backups, err := d.Backups()
+	if err != nil || len(backups) == 0 {
+		return nil, err
+	}
+	return backups[len(backups)-1], nil
+}
+
+// Path returns the backup's directory
+func (b *Backup) Path() string {
+	return b.path
+}
+
+// Time returns when the backed up run started
+func (b *Backup) Time() (time.Time, error) {
+	return time.Parse(backupTimeFormat, filepath.Base(b.path))
+}
+
+// Save copies a file's original content into the backup before it is
+// replaced with newContent. relPath is relative to the root directory.
+func (b *Backup) Save(relPath string, mode fs.FileMode, content, newContent []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.dir.Ensure(); err != nil {
+		return err
+	}
+
+	relPath = filepath.ToSlash(relPath)
+	copyPath := filepath.Join(b.path, backupFiles, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(copyPath), 0755); err != nil {
+		return fmt.Errorf("error creating backup: %w", err)
+	}
+	if err := os.WriteFile(copyPath, content, 0644); err != nil {
+		return fmt.Errorf("error writing backup of %s: %w", relPath, err)
+	}
+	data, err := json.Marshal(BackupFile{Path: relPath, Mode: mode.Perm(), Hash: hashContent(newContent)})
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(b.path, backupManifest), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing backup manifest: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing backup manifest: %w", err)
+	}
+	return nil
+}
+
+// Files returns the files in the backup, in the order they were saved
+func (b *Backup) Files() ([]BackupFile, error) {
+	file, err := os.Open(filepath.Join(b.path, backupManifest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup manifest: %w", err)
+	}
+	defer file.Close()
+
+	var files []BackupFile
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry BackupFile
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("error reading backup manifest: %w", err)
+		}
+		files = append(files, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading backup manifest: %w", err)
+	}
+	return files, nil
+}
+
+// Restore writes the backed up files back beneath rootDir and returns the
+// relative paths of those it restored. Files that were changed or deleted
+// after the run are left alone unless force is set, and returned as
+// conflicts. Once nothing is left to restore, the backup is deleted, so the
+// next undo goes back another run.
+func (b *Backup) Restore(rootDir string, force bool) (restored, conflicts []string, err error) {
+	files, err := b.Files()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range files {
+		name := filepath.FromSlash(entry.Path)
+		original, err := os.ReadFile(filepath.Join(b.path, backupFiles, name))
+		if err != nil {
+			return restored, conflicts, fmt.Errorf("error reading backup of %s: %w", entry.Path, err)
+		}
+		target := filepath.Join(rootDir, name)
+		current, err := os.ReadFile(target)
+		switch {
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			return restored, conflicts, err
+		case err == nil && bytes.Equal(current, original):
+			// Already restored, or reverted during the run
+			continue
+		case err == nil && hashContent(current) == entry.Hash, force:
+		default:
+			conflicts = append(conflicts, entry.Path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return restored, conflicts, err
+		}
+		if err := os.WriteFile(target, original, entry.Mode); err != nil {
+			return restored, conflicts, err
+		}
+		restored = append(restored, entry.Path)
+	}
+
+	if len(conflicts) == 0 {
+		if err := os.RemoveAll(b.path); err != nil {
+			return restored, conflicts, fmt.Errorf("error removing backup: %w", err)
+		}
+	}
+	return restored, conflicts, nil
+}
+
+// hashContent returns the hex SHA-256 of content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
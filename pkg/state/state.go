@@ -0,0 +1,132 @@
+// File: pkg/state/state.go
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// DefaultDir is where state is kept, relative to the root directory
+const DefaultDir = ".pathfix"
+
+// HistoryFile is the run history inside the state directory, one JSON
+// HistoryEntry per line
+const HistoryFile = "history.jsonl"
+
+// marker is the .gitignore written into new state directories. It keeps the
+// directory out of version control and identifies it as safe to delete.
+const marker = "# Created by pathfix; this directory holds local state\n*\n"
+
+// Dir is a repository's state directory, which holds data that must persist
+// between runs. It is created when something is first written to it.
+type Dir struct {
+	path string
+}
+
+// Open returns the state directory for a root directory. A relative dir is
+// resolved against the root, and an empty one means DefaultDir.
+func Open(rootDir, dir string) *Dir {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(rootDir, dir)
+	}
+	return &Dir{path: filepath.Clean(dir)}
+}
+
+// Path returns the directory's path
+func (d *Dir) Path() string {
+	return d.path
+}
+
+// File returns the path of a file inside the directory
+func (d *Dir) File(name string) string {
+	return filepath.Join(d.path, name)
+}
+
+// Ensure creates the directory with its .gitignore if it does not exist
+func (d *Dir) Ensure() error {
+	if err := os.MkdirAll(d.path, 0755); err != nil {
+		return fmt.Errorf("error creating state directory: %w", err)
+	}
+	ignore := d.File(".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, fs.ErrNotExist) {
+		if err := os.WriteFile(ignore, []byte(marker), 0644); err != nil {
+			return fmt.Errorf("error creating state directory: %w", err)
+		}
+	}
+	return nil
+}
+
+// Clean deletes the directory and everything in it. It refuses to delete a
+// directory that pathfix did not create, since the path is configurable.
+func (d *Dir) Clean() error {
+	content, err := os.ReadFile(d.File(".gitignore"))
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, statErr := os.Stat(d.path); errors.Is(statErr, fs.ErrNotExist) {
+			return nil
+		}
+	}
+	if err != nil || !bytes.Equal(content, []byte(marker)) {
+		return fmt.Errorf("%s is not a pathfix state directory", d.path)
+	}
+	if err := os.RemoveAll(d.path); err != nil {
+		return fmt.Errorf("error removing state directory: %w", err)
+	}
+	return nil
+}
+
+// AppendHistory records a run in the history file
+func (d *Dir) AppendHistory(entry models.HistoryEntry) error {
+	if err := d.Ensure(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(d.File(HistoryFile), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening run history: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing run history: %w", err)
+	}
+	return nil
+}
+
+// History returns the recorded runs, oldest first
+func (d *Dir) History() ([]models.HistoryEntry, error) {
+	file, err := os.Open(d.File(HistoryFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading run history: %w", err)
+	}
+	defer file.Close()
+
+	var entries []models.HistoryEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry models.HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("error reading run history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading run history: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,175 @@
+// File: pkg/state/state_test.go
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestOpen(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{"", filepath.FromSlash("/repo/.pathfix")},
+		{"cache/pathfix", filepath.FromSlash("/repo/cache/pathfix")},
+		{filepath.FromSlash("/var/lib/pathfix/"), filepath.FromSlash("/var/lib/pathfix")},
+	}
+
+	for _, test := range tests {
+		if filepath.IsAbs(test.dir) != filepath.IsAbs(test.expected) {
+			continue // Absolute Unix paths are relative on Windows
+		}
+		if got := Open(root, test.dir).Path(); got != test.expected {
+			t.Errorf("Open(%s) = %s, expected %s", test.dir, got, test.expected)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-state-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	dir := Open(tempDir, "")
+	if entries, err := dir.History(); err != nil || len(entries) != 0 {
+		t.Errorf("History() before any run = %v, %v, expected no entries", entries, err)
+	}
+
+	for _, updated := range []int{3, 0} {
+		if err := dir.AppendHistory(models.HistoryEntry{Root: tempDir, Stats: models.Stats{Updated: updated}}); err != nil {
+			t.Fatalf("AppendHistory failed: %v", err)
+		}
+	}
+	entries, err := dir.History()
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Stats.Updated != 3 || entries[1].Stats.Updated != 0 {
+		t.Errorf("History() = %v, expected runs with 3 and 0 updates", entries)
+	}
+
+	// The directory ignores itself
+	content, err := os.ReadFile(filepath.Join(tempDir, DefaultDir, ".gitignore"))
+	if err != nil || string(content) != marker {
+		t.Errorf("state .gitignore = %q, %v, expected %q", content, err, marker)
+	}
+}
+
+func TestClean(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-state-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A directory pathfix did not create is left alone
+	other := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(other, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", other, err)
+	}
+	if err := Open(tempDir, "src").Clean(); err == nil {
+		t.Errorf("Clean(src) succeeded, expected an error")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Clean(src) removed the directory: %v", err)
+	}
+
+	dir := Open(tempDir, "")
+	if err := dir.Clean(); err != nil {
+		t.Errorf("Clean() without a state directory failed: %v", err)
+	}
+	if err := dir.AppendHistory(models.HistoryEntry{}); err != nil {
+		t.Fatalf("AppendHistory failed: %v", err)
+	}
+	if err := dir.Clean(); err != nil {
+		t.Errorf("Clean() failed: %v", err)
+	}
+	if _, err := os.Stat(dir.Path()); !os.IsNotExist(err) {
+		t.Errorf("Clean() left %s behind", dir.Path())
+	}
+}
+
+func TestBackup(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "pathfix-state-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	dir := Open(tempDir, "")
+	if backup, err := dir.LatestBackup(); err != nil || backup != nil {
+		t.Errorf("LatestBackup() before any run = %v, %v, expected none", backup, err)
+	}
+
+	// Two runs, the second modifying a.go and sub/b.go
+	originals := map[string]string{"a.go": "package a\n", "sub/b.go": "package b\n"}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := dir.NewBackup(start.Add(-time.Hour)).Save("old.go", 0644, nil, nil); err != nil {
+		t.Fatalf("Save(old.go) failed: %v", err)
+	}
+	backup := dir.NewBackup(start)
+	for name, original := range originals {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		updated := "// File: " + name + "\n" + original
+		if err := backup.Save(name, 0644, []byte(original), []byte(updated)); err != nil {
+			t.Fatalf("Save(%s) failed: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	latest, err := dir.LatestBackup()
+	if err != nil || latest == nil || latest.Path() != backup.Path() {
+		t.Fatalf("LatestBackup() = %v, %v, expected %s", latest, err, backup.Path())
+	}
+	if got, err := latest.Time(); err != nil || !got.Equal(start) {
+		t.Errorf("Time() = %v, %v, expected %v", got, err, start)
+	}
+
+	// A file edited after the run is only restored when forced
+	edited := filepath.Join(tempDir, "sub", "b.go")
+	if err := os.WriteFile(edited, []byte("package b\n\nfunc B() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit sub/b.go: %v", err)
+	}
+	restored, conflicts, err := latest.Restore(tempDir, false)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if len(restored) != 1 || restored[0] != "a.go" || len(conflicts) != 1 || conflicts[0] != "sub/b.go" {
+		t.Errorf("Restore() = %v, %v, expected [a.go], [sub/b.go]", restored, conflicts)
+	}
+	if _, err := os.Stat(latest.Path()); err != nil {
+		t.Errorf("Restore() with conflicts removed the backup: %v", err)
+	}
+
+	restored, conflicts, err = latest.Restore(tempDir, true)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if len(restored) != 1 || restored[0] != "sub/b.go" || len(conflicts) != 0 {
+		t.Errorf("Restore(force) = %v, %v, expected [sub/b.go], []", restored, conflicts)
+	}
+	for name, original := range originals {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(name)))
+		if err != nil || string(content) != original {
+			t.Errorf("%s after Restore() = %q, %v, expected %q", name, content, err, original)
+		}
+	}
+
+	// The restored backup is gone, so the next undo goes back a run
+	if latest, err := dir.LatestBackup(); err != nil || latest == nil || latest.Path() == backup.Path() {
+		t.Errorf("LatestBackup() after Restore() = %v, %v, expected the earlier run", latest, err)
+	}
+}
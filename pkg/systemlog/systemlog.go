@@ -0,0 +1,149 @@
+// File: pkg/systemlog/systemlog.go
+package systemlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// Supported targets
+const (
+	TargetSyslog   = "syslog"
+	TargetJournald = "journald"
+)
+
+// identifier tags every message
+const identifier = "pathfix"
+
+// Syslog priorities, as used by journald's PRIORITY field
+const (
+	priorityErr     = 3
+	priorityWarning = 4
+	priorityNotice  = 5
+	priorityInfo    = 6
+	priorityDebug   = 7
+)
+
+// priority ranks a log entry: failures are errors, warnings warnings,
+// changes notices, runs starting and finishing info, other files debug
+func priority(entry models.LogEntry) int {
+	switch {
+	case entry.File != nil && entry.File.Action == models.ActionError:
+		return priorityErr
+	case entry.File != nil && entry.File.Warning != "":
+		return priorityWarning
+	case entry.File != nil && entry.File.Action == models.ActionUpdated:
+		return priorityNotice
+	case entry.File != nil:
+		return priorityDebug
+	case entry.Error != "":
+		return priorityErr
+	}
+	return priorityInfo
+}
+
+// message describes a log entry for people reading the log
+func message(entry models.LogEntry) string {
+	mode := ""
+	if entry.DryRun {
+		mode = " (dry run)"
+	}
+	switch entry.Event {
+	case models.LogStart:
+		return fmt.Sprintf("run started in %s%s", entry.Root, mode)
+	case models.LogFinish:
+		msg := fmt.Sprintf("run finished in %s%s", entry.Root, mode)
+		if entry.Stats != nil {
+			msg += fmt.Sprintf(": %d processed, %d updated, %d skipped, %d errors, %d warnings",
+				entry.Stats.Processed, entry.Stats.Updated, entry.Stats.Skipped, entry.Stats.Errors, entry.Stats.Warnings)
+		}
+		if entry.Error != "" {
+			msg += ": " + entry.Error
+		}
+		return msg
+	}
+
+	if entry.File == nil {
+		return entry.Event
+	}
+	msg := entry.File.Action + ": " + entry.File.Path
+	for _, detail := range []string{entry.File.Reason, entry.File.Error, entry.File.Warning} {
+		if detail != "" {
+			msg += " (" + detail + ")"
+		}
+	}
+	return msg
+}
+
+// decode parses a structured log line written by the processor
+func decode(line []byte) (models.LogEntry, error) {
+	var entry models.LogEntry
+	err := json.Unmarshal(bytes.TrimSpace(line), &entry)
+	return entry, err
+}
+
+// journalFields returns the journald fields for a log entry
+func journalFields(entry models.LogEntry) map[string]string {
+	fields := map[string]string{
+		"MESSAGE":           message(entry),
+		"PRIORITY":          strconv.Itoa(priority(entry)),
+		"SYSLOG_IDENTIFIER": identifier,
+		"PATHFIX_EVENT":     entry.Event,
+		"PATHFIX_ROOT":      entry.Root,
+		"PATHFIX_DRY_RUN":   strconv.FormatBool(entry.DryRun),
+	}
+	if entry.File != nil {
+		fields["PATHFIX_PATH"] = entry.File.Path
+		fields["PATHFIX_ACTION"] = entry.File.Action
+		for key, value := range map[string]string{
+			"PATHFIX_REASON":  entry.File.Reason,
+			"PATHFIX_ERROR":   entry.File.Error,
+			"PATHFIX_WARNING": entry.File.Warning,
+		} {
+			if value != "" {
+				fields[key] = value
+			}
+		}
+	}
+	if entry.Stats != nil {
+		fields["PATHFIX_PROCESSED"] = strconv.Itoa(entry.Stats.Processed)
+		fields["PATHFIX_UPDATED"] = strconv.Itoa(entry.Stats.Updated)
+		fields["PATHFIX_SKIPPED"] = strconv.Itoa(entry.Stats.Skipped)
+		fields["PATHFIX_ERRORS"] = strconv.Itoa(entry.Stats.Errors)
+		fields["PATHFIX_WARNINGS"] = strconv.Itoa(entry.Stats.Warnings)
+	}
+	if entry.Error != "" {
+		fields["PATHFIX_RUN_ERROR"] = entry.Error
+	}
+	return fields
+}
+
+// encodeJournal serializes fields in journald's native protocol. Values
+// containing newlines use the length-prefixed binary form.
+func encodeJournal(fields map[string]string) []byte {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		value := fields[key]
+		if !strings.Contains(value, "\n") {
+			fmt.Fprintf(&buf, "%s=%s\n", key, value)
+			continue
+		}
+		buf.WriteString(key + "\n")
+		binary.Write(&buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value + "\n")
+	}
+	return buf.Bytes()
+}
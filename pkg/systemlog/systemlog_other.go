@@ -0,0 +1,93 @@
+//go:build !windows && !plan9
+
+// File: pkg/systemlog/systemlog_other.go
+package systemlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/syslog"
+	"net"
+)
+
+// Supported reports whether Open can connect on this platform
+const Supported = true
+
+// journalSocket is where journald accepts native protocol datagrams
+const journalSocket = "/run/systemd/journal/socket"
+
+// Open connects to the local system log. The returned writer accepts the
+// processor's structured log lines and forwards each one as an event.
+func Open(target string) (io.WriteCloser, error) {
+	switch target {
+	case TargetSyslog:
+		w, err := syslog.New(syslog.LOG_DAEMON|syslog.LOG_INFO, identifier)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to syslog: %w", err)
+		}
+		return &syslogWriter{w: w}, nil
+	case TargetJournald:
+		conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to journald: %w", err)
+		}
+		return &journalWriter{conn: conn}, nil
+	}
+	return nil, fmt.Errorf("unknown system log %q (expected %s or %s)", target, TargetSyslog, TargetJournald)
+}
+
+// syslogWriter sends entries to syslog with their priority. The message
+// carries the entry as JSON behind a CEE cookie, which rsyslog and
+// syslog-ng can parse into fields.
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+func (s *syslogWriter) Write(p []byte) (int, error) {
+	entry, err := decode(p)
+	if err != nil {
+		return 0, err
+	}
+	msg := "@cee:" + string(bytes.TrimSpace(p))
+	switch priority(entry) {
+	case priorityErr:
+		err = s.w.Err(msg)
+	case priorityWarning:
+		err = s.w.Warning(msg)
+	case priorityNotice:
+		err = s.w.Notice(msg)
+	case priorityDebug:
+		err = s.w.Debug(msg)
+	default:
+		err = s.w.Info(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *syslogWriter) Close() error {
+	return s.w.Close()
+}
+
+// journalWriter sends entries to journald as structured fields
+type journalWriter struct {
+	conn *net.UnixConn
+}
+
+func (j *journalWriter) Write(p []byte) (int, error) {
+	entry, err := decode(p)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := j.conn.Write(encodeJournal(journalFields(entry))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (j *journalWriter) Close() error {
+	return j.conn.Close()
+}
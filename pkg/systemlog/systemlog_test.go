@@ -0,0 +1,73 @@
+// File: pkg/systemlog/systemlog_test.go
+package systemlog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		entry    models.LogEntry
+		expected int
+	}{
+		{models.LogEntry{Event: models.LogStart}, priorityInfo},
+		{models.LogEntry{Event: models.LogFinish, Stats: &models.Stats{}}, priorityInfo},
+		{models.LogEntry{Event: models.LogFinish, Error: "error loading .gitignore"}, priorityErr},
+		{models.LogEntry{Event: models.LogFile, File: &models.FileResult{Action: models.ActionError}}, priorityErr},
+		{models.LogEntry{Event: models.LogFile, File: &models.FileResult{Action: models.ActionSkipped, Warning: "skipped by policy"}}, priorityWarning},
+		{models.LogEntry{Event: models.LogFile, File: &models.FileResult{Action: models.ActionUpdated}}, priorityNotice},
+		{models.LogEntry{Event: models.LogFile, File: &models.FileResult{Action: models.ActionUnchanged}}, priorityDebug},
+	}
+	for _, test := range tests {
+		if result := priority(test.entry); result != test.expected {
+			t.Errorf("priority(%+v) = %d, expected %d", test.entry, result, test.expected)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		entry    models.LogEntry
+		expected string
+	}{
+		{models.LogEntry{Event: models.LogStart, Root: "/src", DryRun: true}, "run started in /src (dry run)"},
+		{
+			models.LogEntry{Event: models.LogFinish, Root: "/src", Stats: &models.Stats{Processed: 3, Updated: 1, Skipped: 2}},
+			"run finished in /src: 3 processed, 1 updated, 2 skipped, 0 errors, 0 warnings",
+		},
+		{
+			models.LogEntry{Event: models.LogFile, File: &models.FileResult{Path: "a.bin", Action: models.ActionSkipped, Reason: "binary file"}},
+			"skipped: a.bin (binary file)",
+		},
+	}
+	for _, test := range tests {
+		if result := message(test.entry); result != test.expected {
+			t.Errorf("message(%+v) = %q, expected %q", test.entry, result, test.expected)
+		}
+	}
+}
+
+func TestEncodeJournal(t *testing.T) {
+	result := encodeJournal(map[string]string{
+		"PRIORITY": "6",
+		"MESSAGE":  "two\nlines",
+	})
+	expected := []byte("MESSAGE\n\x09\x00\x00\x00\x00\x00\x00\x00two\nlines\nPRIORITY=6\n")
+	if !bytes.Equal(result, expected) {
+		t.Errorf("encodeJournal() = %q, expected %q", result, expected)
+	}
+
+	fields := journalFields(models.LogEntry{
+		Event: models.LogFile,
+		File:  &models.FileResult{Path: "main.go", Action: models.ActionUpdated},
+	})
+	if fields["PATHFIX_PATH"] != "main.go" || fields["PRIORITY"] != "5" || fields["SYSLOG_IDENTIFIER"] != "pathfix" {
+		t.Errorf("journalFields() = %v, expected the file's path and notice priority", fields)
+	}
+	if _, ok := fields["PATHFIX_ERROR"]; ok {
+		t.Errorf("journalFields() = %v, expected no empty fields", fields)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build windows || plan9
+
+// File: pkg/systemlog/systemlog_unsupported.go
+package systemlog
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// Supported reports whether Open can connect on this platform
+const Supported = false
+
+// Open reports that there is no system log to connect to on this platform
+func Open(target string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("%s is not available on %s", target, runtime.GOOS)
+}
@@ -0,0 +1,368 @@
+// File: pkg/tui/tui.go
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+// Decision is the reviewer's choice for a proposed change
+type Decision int
+
+const (
+	Pending Decision = iota // Not decided yet; left alone
+	Apply                   // Write the proposed change
+	Skip                    // Leave the file alone
+)
+
+// ANSI escape sequences used for rendering
+const (
+	ansiReset      = "\x1b[0m"
+	ansiBold       = "\x1b[1m"
+	ansiDim        = "\x1b[2m"
+	ansiRed        = "\x1b[31m"
+	ansiGreen      = "\x1b[32m"
+	ansiYellow     = "\x1b[33m"
+	ansiCyan       = "\x1b[36m"
+	ansiReverse    = "\x1b[7m"
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiAltScreen  = "\x1b[?1049h\x1b[?25l"
+	ansiMainScreen = "\x1b[?25h\x1b[?1049l"
+)
+
+// Item is a file shown in the tree
+type Item struct {
+	Result   models.FileResult
+	Old, New []byte // Current and proposed content of files with a pending change
+	Diff     string // Unified diff of the proposed change
+	Decision Decision
+}
+
+// Actionable reports whether the item has a change to apply or skip
+func (it *Item) Actionable() bool {
+	return it.Result.Action == models.ActionUpdated
+}
+
+// row is a line of the tree: a directory, or a file when item >= 0
+type row struct {
+	depth int
+	label string
+	item  int
+}
+
+// Model is the state of the review screen
+type Model struct {
+	Items []Item
+
+	showAll       bool // Also list unchanged and skipped files
+	cursor        int  // Index into the file rows
+	offset        int  // First visible row
+	preview       bool // Showing the diff of the current file
+	previewOffset int
+	quit          bool
+	write         bool
+}
+
+// NewModel creates a model for the given items, sorted by path
+func NewModel(items []Item) *Model {
+	sort.Slice(items, func(i, j int) bool { return items[i].Result.Path < items[j].Result.Path })
+	return &Model{Items: items}
+}
+
+// Done reports whether the reviewer has quit, and whether to write the applied changes
+func (m *Model) Done() (quit, write bool) {
+	return m.quit, m.write
+}
+
+// visible reports whether an item is listed with the current filter
+func (m *Model) visible(it *Item) bool {
+	return m.showAll || it.Actionable() || it.Result.Action == models.ActionError
+}
+
+// rows lays out the visible items as a tree
+func (m *Model) rows() ([]row, []int) {
+	var rows []row
+	var fileRows []int
+	var lastDir []string
+	for i := range m.Items {
+		it := &m.Items[i]
+		if !m.visible(it) {
+			continue
+		}
+		dir, name := path.Split(it.Result.Path)
+		var parts []string
+		if dir != "" {
+			parts = strings.Split(strings.TrimSuffix(dir, "/"), "/")
+		}
+		common := 0
+		for common < len(parts) && common < len(lastDir) && parts[common] == lastDir[common] {
+			common++
+		}
+		for d := common; d < len(parts); d++ {
+			rows = append(rows, row{depth: d, label: parts[d] + "/", item: -1})
+		}
+		lastDir = parts
+		fileRows = append(fileRows, len(rows))
+		rows = append(rows, row{depth: len(parts), label: name, item: i})
+	}
+	return rows, fileRows
+}
+
+// current returns the item under the cursor, if any
+func (m *Model) current() *Item {
+	rows, fileRows := m.rows()
+	if m.cursor < 0 || m.cursor >= len(fileRows) {
+		return nil
+	}
+	return &m.Items[rows[fileRows[m.cursor]].item]
+}
+
+// HandleKey updates the model for a key press. Keys are single characters
+// or the names "up", "down", "pgup", "pgdown", "enter", "esc" and "ctrl-c".
+func (m *Model) HandleKey(key string) {
+	_, fileRows := m.rows()
+	if m.preview {
+		switch key {
+		case "up", "k":
+			if m.previewOffset > 0 {
+				m.previewOffset--
+			}
+		case "down", "j":
+			m.previewOffset++
+		case "enter", "esc", " ", "q":
+			m.preview = false
+		case "a", "s":
+			m.preview = false
+			m.HandleKey(key)
+		case "ctrl-c":
+			m.quit = true
+		}
+		return
+	}
+
+	switch key {
+	case "up", "k":
+		m.cursor--
+	case "down", "j":
+		m.cursor++
+	case "pgup":
+		m.cursor -= 10
+	case "pgdown":
+		m.cursor += 10
+	case "enter", " ":
+		if it := m.current(); it != nil && it.Diff != "" {
+			m.preview = true
+			m.previewOffset = 0
+		}
+	case "a", "s":
+		if it := m.current(); it != nil && it.Actionable() {
+			it.Decision = Apply
+			if key == "s" {
+				it.Decision = Skip
+			}
+			m.cursor++
+		}
+	case "A", "S":
+		for i := range m.Items {
+			if m.Items[i].Actionable() && m.Items[i].Decision == Pending {
+				m.Items[i].Decision = Apply
+				if key == "S" {
+					m.Items[i].Decision = Skip
+				}
+			}
+		}
+	case "f":
+		m.showAll = !m.showAll
+		m.cursor = 0
+		m.offset = 0
+		_, fileRows = m.rows()
+	case "w":
+		m.quit = true
+		m.write = true
+	case "q", "ctrl-c":
+		m.quit = true
+	}
+
+	if m.cursor >= len(fileRows) {
+		m.cursor = len(fileRows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// counts tallies the decisions on actionable items
+func (m *Model) counts() (pending, apply, skip int) {
+	for i := range m.Items {
+		if !m.Items[i].Actionable() {
+			continue
+		}
+		switch m.Items[i].Decision {
+		case Apply:
+			apply++
+		case Skip:
+			skip++
+		default:
+			pending++
+		}
+	}
+	return pending, apply, skip
+}
+
+// View renders the screen as lines of at most width columns
+func (m *Model) View(width, height int) []string {
+	pending, apply, skip := m.counts()
+	lines := []string{
+		ansiBold + fmt.Sprintf("pathfix review: %d to apply, %d to skip, %d undecided", apply, skip, pending) + ansiReset,
+	}
+	footer := ansiDim + "j/k move  enter diff  a apply  s skip  A/S all  f filter  w write & quit  q quit" + ansiReset
+	body := height - 2
+	if body < 1 {
+		body = 1
+	}
+
+	if m.preview {
+		if it := m.current(); it != nil {
+			diff := strings.Split(strings.TrimSuffix(it.Diff, "\n"), "\n")
+			if m.previewOffset > len(diff)-1 {
+				m.previewOffset = len(diff) - 1
+			}
+			for i := m.previewOffset; i < len(diff) && len(lines) <= body; i++ {
+				lines = append(lines, colorDiffLine(truncate(diff[i], width)))
+			}
+		}
+		footer = ansiDim + "j/k scroll  a apply  s skip  esc back" + ansiReset
+	} else {
+		rows, fileRows := m.rows()
+		if len(fileRows) == 0 {
+			lines = append(lines, "No files to review. Press f to show all files or q to quit.")
+		} else {
+			selected := fileRows[m.cursor]
+			if selected < m.offset {
+				m.offset = selected
+			}
+			if selected >= m.offset+body {
+				m.offset = selected - body + 1
+			}
+			for i := m.offset; i < len(rows) && i < m.offset+body; i++ {
+				lines = append(lines, m.renderRow(rows[i], i == selected, width))
+			}
+		}
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, footer)
+}
+
+// renderRow renders a tree row with its status marker
+func (m *Model) renderRow(r row, selected bool, width int) string {
+	indent := strings.Repeat("  ", r.depth)
+	if r.item < 0 {
+		return ansiCyan + truncate(indent+r.label, width) + ansiReset
+	}
+
+	it := &m.Items[r.item]
+	marker, color, note := "   ", ansiDim, it.Result.Action
+	switch {
+	case it.Result.Action == models.ActionError:
+		marker, color, note = "[!]", ansiRed, it.Result.Error
+	case it.Actionable() && it.Decision == Apply:
+		marker, color, note = "[+]", ansiGreen, "apply"
+	case it.Actionable() && it.Decision == Skip:
+		marker, color, note = "[-]", ansiYellow, "skip"
+	case it.Actionable():
+		marker, color, note = "[ ]", "", "pending"
+	case it.Result.Reason != "":
+		note = it.Result.Reason
+	}
+
+	text := truncate(fmt.Sprintf("%s%s %s  (%s)", indent, marker, r.label, note), width)
+	if selected {
+		return ansiReverse + color + text + ansiReset
+	}
+	return color + text + ansiReset
+}
+
+// colorDiffLine colors a line of a unified diff
+func colorDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return ansiGreen + line + ansiReset
+	case strings.HasPrefix(line, "-"):
+		return ansiRed + line + ansiReset
+	case strings.HasPrefix(line, "@@"):
+		return ansiCyan + line + ansiReset
+	}
+	return line
+}
+
+// truncate shortens s to at most width runes
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width])
+}
+
+// Run shows the review screen on the terminal until the reviewer quits
+func Run(m *Model, in *os.File, out io.Writer) error {
+	fd := int(in.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("terminal does not support raw mode: %w", err)
+	}
+	defer term.Restore(fd, state)
+	fmt.Fprint(out, ansiAltScreen)
+	defer fmt.Fprint(out, ansiMainScreen)
+
+	buf := make([]byte, 16)
+	for {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		fmt.Fprint(out, ansiClear+strings.Join(m.View(width, height), "\r\n"))
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return err
+		}
+		m.HandleKey(decodeKey(buf[:n]))
+		if quit, _ := m.Done(); quit {
+			return nil
+		}
+	}
+}
+
+// decodeKey names the key encoded by a terminal input sequence
+func decodeKey(seq []byte) string {
+	switch string(seq) {
+	case "\x1b[A", "\x1bOA":
+		return "up"
+	case "\x1b[B", "\x1bOB":
+		return "down"
+	case "\x1b[5~":
+		return "pgup"
+	case "\x1b[6~":
+		return "pgdown"
+	case "\r", "\n":
+		return "enter"
+	case "\x1b":
+		return "esc"
+	case "\x03":
+		return "ctrl-c"
+	}
+	return string(seq)
+}
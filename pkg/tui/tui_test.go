@@ -0,0 +1,103 @@
+// File: pkg/tui/tui_test.go
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourusername/pathfix/pkg/models"
+)
+
+func testItems() []Item {
+	return []Item{
+		{Result: models.FileResult{Path: "src/b.go", Action: models.ActionUpdated}, Diff: "--- a/src/b.go\n+++ b/src/b.go\n"},
+		{Result: models.FileResult{Path: "main.go", Action: models.ActionUpdated}, Diff: "--- a/main.go\n"},
+		{Result: models.FileResult{Path: "src/a.go", Action: models.ActionUnchanged}},
+		{Result: models.FileResult{Path: "src/lib/c.go", Action: models.ActionError, Error: "permission denied"}},
+	}
+}
+
+func TestModelTree(t *testing.T) {
+	m := NewModel(testItems())
+
+	tests := []struct {
+		showAll  bool
+		expected []string
+	}{
+		{false, []string{"main.go", "src/", "b.go", "lib/", "c.go"}},
+		{true, []string{"main.go", "src/", "a.go", "b.go", "lib/", "c.go"}},
+	}
+
+	for _, test := range tests {
+		m.showAll = test.showAll
+		rows, _ := m.rows()
+		var labels []string
+		for _, r := range rows {
+			labels = append(labels, r.label)
+		}
+		if strings.Join(labels, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("rows(showAll=%v) = %v, expected %v", test.showAll, labels, test.expected)
+		}
+	}
+}
+
+func TestModelKeys(t *testing.T) {
+	m := NewModel(testItems())
+
+	// Apply the first file, skip the second, then move past the end
+	for _, key := range []string{"a", "s", "down", "down"} {
+		m.HandleKey(key)
+	}
+	decisions := map[string]Decision{}
+	for _, it := range m.Items {
+		decisions[it.Result.Path] = it.Decision
+	}
+	if decisions["main.go"] != Apply || decisions["src/b.go"] != Skip {
+		t.Errorf("decisions = %v, expected main.go applied and src/b.go skipped", decisions)
+	}
+	if m.cursor != 2 {
+		t.Errorf("cursor = %d, expected 2", m.cursor)
+	}
+
+	// Errors cannot be applied
+	m.HandleKey("a")
+	if m.Items[3].Decision != Pending {
+		t.Errorf("decision for an error = %v, expected pending", m.Items[3].Decision)
+	}
+
+	// The diff preview opens on files with a change
+	m.HandleKey("up")
+	m.HandleKey("enter")
+	if !m.preview {
+		t.Errorf("enter on src/b.go did not open the preview")
+	}
+	view := strings.Join(m.View(80, 10), "\n")
+	if !strings.Contains(view, "+++ b/src/b.go") {
+		t.Errorf("preview = %q, expected the diff of src/b.go", view)
+	}
+	m.HandleKey("esc")
+
+	m.HandleKey("w")
+	if quit, write := m.Done(); !quit || !write {
+		t.Errorf("Done() after w = %v, %v, expected true, true", quit, write)
+	}
+}
+
+func TestDecodeKey(t *testing.T) {
+	tests := []struct {
+		seq      string
+		expected string
+	}{
+		{"\x1b[A", "up"},
+		{"\x1b[B", "down"},
+		{"\r", "enter"},
+		{"\x03", "ctrl-c"},
+		{"a", "a"},
+	}
+
+	for _, test := range tests {
+		if result := decodeKey([]byte(test.seq)); result != test.expected {
+			t.Errorf("decodeKey(%q) = %s, expected %s", test.seq, result, test.expected)
+		}
+	}
+}
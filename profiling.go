@@ -0,0 +1,87 @@
+// File: profiling.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// profileConfig holds the flags that write Go runtime profiles
+type profileConfig struct {
+	cpuProfile string
+	memProfile string
+	trace      string
+}
+
+// profileFlags registers the profiling flags
+func profileFlags(flags *flag.FlagSet) *profileConfig {
+	config := &profileConfig{}
+	flags.StringVar(&config.cpuProfile, "cpuprofile", "", "Write a CPU profile to this file, for go tool pprof")
+	flags.StringVar(&config.memProfile, "memprofile", "", "Write a heap profile to this file when the run ends, for go tool pprof")
+	flags.StringVar(&config.trace, "trace", "", "Write an execution trace to this file, for go tool trace")
+	return config
+}
+
+// start starts the configured profiles and returns a function that stops
+// them and writes the heap profile
+func (c *profileConfig) start() (func(), error) {
+	var stops []func()
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}
+
+	if c.cpuProfile != "" {
+		file, err := os.Create(c.cpuProfile)
+		if err != nil {
+			return nil, fmt.Errorf("error creating CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error starting CPU profile: %w", err)
+		}
+		stops = append(stops, func() {
+			pprof.StopCPUProfile()
+			file.Close()
+		})
+	}
+
+	if c.trace != "" {
+		file, err := os.Create(c.trace)
+		if err != nil {
+			stop()
+			return nil, fmt.Errorf("error creating trace: %w", err)
+		}
+		if err := trace.Start(file); err != nil {
+			file.Close()
+			stop()
+			return nil, fmt.Errorf("error starting trace: %w", err)
+		}
+		stops = append(stops, func() {
+			trace.Stop()
+			file.Close()
+		})
+	}
+
+	if c.memProfile != "" {
+		path := c.memProfile
+		stops = append(stops, func() {
+			file, err := os.Create(path)
+			if err != nil {
+				msg.Fprintf(os.Stderr, "Error creating heap profile: %v\n", err)
+				return
+			}
+			defer file.Close()
+			runtime.GC() // Report live objects as of the end of the run
+			if err := pprof.WriteHeapProfile(file); err != nil {
+				msg.Fprintf(os.Stderr, "Error writing heap profile: %v\n", err)
+			}
+		})
+	}
+	return stop, nil
+}
@@ -0,0 +1,130 @@
+// File: progress.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+const (
+	progressWidth    = 30                     // Characters in the bar
+	progressInterval = 100 * time.Millisecond // Least time between redraws
+)
+
+// progressBar shows on stderr how many of the counted files a run has
+// reached. On a terminal, a bar is redrawn in place, and the output of the
+// run goes through it so the bar stays on the last line; otherwise a line is
+// printed at every tenth of the files, which suits CI logs.
+type progressBar struct {
+	w        io.Writer
+	terminal bool
+	total    int
+	started  int
+	step     int       // The last tenth printed without a terminal
+	drawn    time.Time // When the bar was last drawn on a terminal
+	width    int       // Length of the bar on the screen, or 0 if it is not shown
+
+	mu sync.Mutex
+}
+
+// startProgress counts the files the run will check and sets up options to
+// show its progress. Nothing is shown if there are no files or they cannot
+// be counted; the run reports such errors itself.
+func startProgress(ctx context.Context, p *processor.Processor, options *processor.Options) *progressBar {
+	bar := &progressBar{w: os.Stderr, terminal: term.IsTerminal(int(os.Stderr.Fd()))}
+	msg.Fprintf(bar.w, "Counting files...\n")
+	total, err := p.CountFiles(ctx)
+	if err != nil || total == 0 {
+		return bar
+	}
+	bar.total = total
+	options.OnFileStart = bar.start
+	if bar.terminal {
+		if options.Stderr == nil {
+			options.Stderr = os.Stderr
+		}
+		options.Stdout = progressWriter{bar, options.Stdout}
+		options.Stderr = progressWriter{bar, options.Stderr}
+	}
+	return bar
+}
+
+// start counts a file handed over to be checked
+func (b *progressBar) start(path string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.started++
+	// Files added since they were counted stretch the total
+	if b.started > b.total {
+		b.total = b.started
+	}
+
+	if b.terminal {
+		if now := time.Now(); b.started == b.total || now.Sub(b.drawn) >= progressInterval {
+			b.drawn = now
+			b.draw()
+		}
+		return
+	}
+	if step := 10 * b.started / b.total; step > b.step {
+		b.step = step
+		fmt.Fprintf(b.w, "%s\n", b.status())
+	}
+}
+
+// status describes how far the run has got
+func (b *progressBar) status() string {
+	return msg.Sprintf("%d%% (%d of %d files)", 100*b.started/b.total, b.started, b.total)
+}
+
+// draw replaces the bar on the terminal
+func (b *progressBar) draw() {
+	filled := progressWidth * b.started / b.total
+	line := fmt.Sprintf("[%s%s] %s", strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled), b.status())
+	b.clear()
+	fmt.Fprint(b.w, line)
+	b.width = len([]rune(line))
+}
+
+// clear erases the bar, leaving the cursor at the start of its line
+func (b *progressBar) clear() {
+	if b.width > 0 {
+		fmt.Fprintf(b.w, "\r%s\r", strings.Repeat(" ", b.width))
+		b.width = 0
+	}
+}
+
+// finish erases the bar once the run is over, so the summary starts on a
+// clean line
+func (b *progressBar) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clear()
+}
+
+// progressWriter writes the output of a run above the progress bar
+type progressWriter struct {
+	bar *progressBar
+	w   io.Writer
+}
+
+func (pw progressWriter) Write(data []byte) (int, error) {
+	pw.bar.mu.Lock()
+	defer pw.bar.mu.Unlock()
+	shown := pw.bar.width > 0
+	pw.bar.clear()
+	n, err := pw.w.Write(data)
+	if shown {
+		pw.bar.draw()
+	}
+	return n, err
+}
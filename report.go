@@ -0,0 +1,124 @@
+// File: report.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/processor"
+	"github.com/yourusername/pathfix/pkg/report"
+)
+
+// reportFormats lists the formats accepted by --report
+var reportFormats = []string{"csv", "html", "json"}
+
+// reportSpec is a report to write after a run
+type reportSpec struct {
+	format string
+	path   string // "-" for stdout
+}
+
+// reportFlag collects the reports requested with --report FORMAT[=PATH]
+type reportFlag []reportSpec
+
+func (f *reportFlag) String() string {
+	return ""
+}
+
+func (f *reportFlag) Set(s string) error {
+	format, path, found := strings.Cut(s, "=")
+	if !found || path == "" {
+		path = "-"
+	}
+	for _, known := range reportFormats {
+		if format == known {
+			*f = append(*f, reportSpec{format: format, path: path})
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown report format %q (expected %s)", format, strings.Join(reportFormats, ", "))
+}
+
+// toStdout reports whether any report is written to stdout
+func (f reportFlag) toStdout() bool {
+	for _, spec := range f {
+		if spec.path == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// needsDiffs reports whether any report shows the diff of each change
+func (f reportFlag) needsDiffs() bool {
+	for _, spec := range f {
+		if spec.format == "html" {
+			return true
+		}
+	}
+	return false
+}
+
+// write writes each report on the run p has completed
+func (f reportFlag) write(p *processor.Processor) error {
+	for _, spec := range f {
+		if err := writeReport(spec, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summaryTopDirs is the number of directories a GitHub summary lists when
+// --top-dirs is not given
+const summaryTopDirs = 10
+
+// writeGitHubSummary appends a Markdown summary of the run p has completed to
+// the file GitHub Actions names in $GITHUB_STEP_SUMMARY
+func writeGitHubSummary(p *processor.Processor, topDirs int) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return errors.New("GITHUB_STEP_SUMMARY is not set")
+	}
+	if topDirs <= 0 {
+		topDirs = summaryTopDirs
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	data := report.MarkdownData{Report: p.Report(), TopDirectories: needingHeaders(p.DirectoryStats(-1, topDirs))}
+	if err := report.WriteMarkdown(file, data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeReport writes a report to its file or stdout
+func writeReport(spec reportSpec, p *processor.Processor) error {
+	var out io.Writer = os.Stdout
+	if spec.path != "-" {
+		file, err := os.Create(spec.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch spec.format {
+	case "csv":
+		return report.WriteCSV(out, p.Results())
+	case "html":
+		return report.WriteHTML(out, report.HTMLData{Report: p.Report(), Coverage: p.Coverage(1)})
+	default:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(p.Report())
+	}
+}
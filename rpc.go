@@ -0,0 +1,34 @@
+// File: rpc.go
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/pathfix/pkg/server"
+)
+
+// runRPC answers editor requests on stdin and stdout until shut down
+func runRPC(args []string) int {
+	var targetDir string
+
+	flags := flag.NewFlagSet("rpc", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Root directory that header paths are relative to")
+	options := processorFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+
+	if err := server.ServeRPC(os.Stdin, os.Stdout, absPath, *options); err != nil {
+		msg.Fprintf(os.Stderr, "Error serving requests: %v\n", err)
+		return exitFailure
+	}
+	return exitClean
+}
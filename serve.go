@@ -0,0 +1,119 @@
+// File: serve.go
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/pathfix/pkg/server"
+)
+
+// rootFlags collects repeated --root flags of the form name=path or path
+type rootFlags []server.Root
+
+func (r *rootFlags) String() string {
+	names := make([]string, len(*r))
+	for i, root := range *r {
+		names[i] = root.Name + "=" + root.Path
+	}
+	return strings.Join(names, ",")
+}
+
+func (r *rootFlags) Set(value string) error {
+	name, path, ok := strings.Cut(value, "=")
+	if !ok {
+		path = value
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = filepath.Base(absPath)
+	}
+	*r = append(*r, server.Root{Name: name, Path: absPath})
+	return nil
+}
+
+// runServe serves the HTTP API until interrupted
+func runServe(args []string) int {
+	var (
+		listen string
+		token  string
+		roots  rootFlags
+	)
+
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.StringVar(&listen, "listen", "127.0.0.1:8080", "Address to listen on")
+	flags.StringVar(&token, "token", "", "Bearer token required by the API (default $PATHFIX_TOKEN)")
+	flags.Var(&roots, "root", "Directory runs may target, as name=path or path (repeatable)")
+	options := processorFlags(flags)
+	logging := logFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if token == "" {
+		token = os.Getenv("PATHFIX_TOKEN")
+	}
+	if len(roots) == 0 {
+		msg.Fprintf(os.Stderr, "At least one --root is required\n")
+		return exitUsage
+	}
+	for _, root := range roots {
+		if info, err := os.Stat(root.Path); err != nil || !info.IsDir() {
+			msg.Fprintf(os.Stderr, "%s is not a directory\n", root.Path)
+			return exitUsage
+		}
+	}
+
+	logWriter, err := logging.open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return exitUsage
+	}
+	if logWriter != nil {
+		defer logWriter.Close()
+		options.Log = logWriter
+	}
+	options.History = true
+
+	srv, err := server.New(server.Options{
+		Roots:     roots,
+		Token:     token,
+		Processor: *options,
+	})
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error starting server: %v\n", err)
+		return exitUsage
+	}
+	if token == "" {
+		msg.Fprintf(os.Stderr, "Warning: no token configured, the API is unauthenticated\n")
+	}
+
+	httpServer := &http.Server{Addr: listen, Handler: srv.Handler()}
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	}()
+
+	msg.Printf("Serving %d roots on %s\n", len(roots), listen)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		msg.Fprintf(os.Stderr, "Error serving: %v\n", err)
+		return exitFailure
+	}
+	return exitClean
+}
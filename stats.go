@@ -0,0 +1,144 @@
+// File: stats.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// runStats reports header coverage without modifying anything
+func runStats(args []string) int {
+	var (
+		targetDir  string
+		depth      int
+		output     string
+		badgePath  string
+		badgeLabel string
+		top        int
+	)
+
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Target directory to report on")
+	flags.IntVar(&depth, "depth", 1, "Directory levels below the root to break coverage down by")
+	flags.StringVar(&output, "output", "text", "Output format: text, json, or badge for a shields.io endpoint badge")
+	flags.StringVar(&badgePath, "badge", "", "Also write a shields.io endpoint badge to this path")
+	flags.StringVar(&badgeLabel, "badge-label", "path headers", "Label shown on the badge")
+	flags.IntVar(&top, "top", 5, "Number of directories with the most missing or stale headers to list (0 for none)")
+	options := processorFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if output != "text" && output != "json" && output != "badge" {
+		msg.Fprintf(os.Stderr, "Unknown output format %q (expected text, json or badge)\n", output)
+		return exitUsage
+	}
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+
+	options.DryRun = true
+	p := processor.NewProcessor(absPath, options)
+	if _, err := p.Process(); runFailed(err) {
+		msg.Fprintf(os.Stderr, "Error processing directory: %v\n", err)
+		return exitFailure
+	}
+	report := p.Coverage(depth)
+	if top > 0 {
+		report.TopDirectories = needingHeaders(p.DirectoryStats(depth, top))
+	}
+	badge := processor.CoverageBadge(badgeLabel, report.Total)
+
+	if badgePath != "" {
+		data, err := json.MarshalIndent(badge, "", "  ")
+		if err == nil {
+			err = os.WriteFile(badgePath, append(data, '\n'), 0644)
+		}
+		if err != nil {
+			msg.Fprintf(os.Stderr, "Error writing badge: %v\n", err)
+			return exitFailure
+		}
+	}
+
+	switch output {
+	case "json", "badge":
+		var value interface{} = report
+		if output == "badge" {
+			value = badge
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(value); err != nil {
+			msg.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			return exitFailure
+		}
+		return exitClean
+	}
+
+	msg.Printf("Header coverage: %.1f%% (%d of %d files)\n", report.Total.Percent, report.Total.Covered, report.Total.Files)
+	printCoverage(msg.Sprintf("By directory"), report.Directories)
+	printCoverage(msg.Sprintf("By language"), report.Languages)
+	printTopDirectories(os.Stdout, report.TopDirectories)
+	return exitClean
+}
+
+// needingHeaders drops the directories with no missing or stale headers and
+// no errors from a list ordered by DirectoryStats
+func needingHeaders(dirs []models.DirectoryStats) []models.DirectoryStats {
+	for i, dir := range dirs {
+		if dir.Stats.Missing+dir.Stats.Stale+dir.Stats.Errors == 0 {
+			return dirs[:i]
+		}
+	}
+	return dirs
+}
+
+// printTopDirectories lists the directories that need attention, in order
+func printTopDirectories(w io.Writer, dirs []models.DirectoryStats) {
+	dirs = needingHeaders(dirs)
+	if len(dirs) == 0 {
+		return
+	}
+	width := 0
+	for _, dir := range dirs {
+		if len(dir.Directory) > width {
+			width = len(dir.Directory)
+		}
+	}
+
+	msg.Fprintf(w, "\n%s:\n", msg.Sprintf("Directories needing headers"))
+	for _, dir := range dirs {
+		msg.Fprintf(w, "  %-*s  %d missing, %d stale, %d errors\n", width, dir.Directory, dir.Stats.Missing, dir.Stats.Stale, dir.Stats.Errors)
+	}
+}
+
+// printCoverage prints a coverage breakdown sorted by name
+func printCoverage(title string, coverage map[string]models.Coverage) {
+	if len(coverage) == 0 {
+		return
+	}
+	names := make([]string, 0, len(coverage))
+	width := 0
+	for name := range coverage {
+		names = append(names, name)
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	msg.Printf("\n%s:\n", title)
+	for _, name := range names {
+		c := coverage[name]
+		msg.Printf("  %-*s  %5.1f%%  (%d/%d)\n", width, name, c.Percent, c.Covered, c.Files)
+	}
+}
@@ -0,0 +1,97 @@
+// File: tui.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/term"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+	"github.com/yourusername/pathfix/pkg/tui"
+)
+
+// runTUI reviews the proposed changes interactively and writes the accepted ones
+func runTUI(args []string) int {
+	var targetDir string
+
+	flags := flag.NewFlagSet("tui", flag.ContinueOnError)
+	flags.StringVar(&targetDir, "dir", ".", "Target directory to review")
+	options := processorFlags(flags)
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		msg.Fprintf(os.Stderr, "pathfix tui requires an interactive terminal\n")
+		return exitUsage
+	}
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error resolving path %s: %v\n", targetDir, err)
+		return exitUsage
+	}
+
+	// Collect the proposed changes with a dry run
+	msg.Fprintf(os.Stderr, "Scanning %s...\n", absPath)
+	var items []tui.Item
+	options.DryRun = true
+	p := processor.NewProcessor(absPath, options)
+	options.OnResult = func(result models.FileResult) {
+		item := tui.Item{Result: result}
+		if result.Action == models.ActionUpdated {
+			old, err := os.ReadFile(filepath.Join(absPath, filepath.FromSlash(result.Path)))
+			if err == nil {
+				item.Old = old
+				item.New, _ = p.FixBuffer(result.Path, old)
+				item.Diff = processor.UnifiedDiff(result.Path, item.Old, item.New)
+			}
+		}
+		items = append(items, item)
+	}
+	if _, err := p.Process(); runFailed(err) {
+		msg.Fprintf(os.Stderr, "Error processing directory: %v\n", err)
+		return exitFailure
+	}
+
+	model := tui.NewModel(items)
+	if err := tui.Run(model, os.Stdin, os.Stdout); err != nil {
+		msg.Fprintf(os.Stderr, "Error running terminal UI: %v\n", err)
+		return exitFailure
+	}
+	if _, write := model.Done(); !write {
+		msg.Printf("No files were modified.\n")
+		return exitClean
+	}
+
+	// Write the accepted changes, unless the file changed during the review
+	written, failed := 0, 0
+	for _, item := range model.Items {
+		if item.Decision != tui.Apply {
+			continue
+		}
+		filePath := filepath.Join(absPath, filepath.FromSlash(item.Result.Path))
+		current, err := os.ReadFile(filePath)
+		if err == nil && !bytes.Equal(current, item.Old) {
+			err = fmt.Errorf("file changed during review")
+		}
+		if err == nil {
+			err = os.WriteFile(filePath, item.New, 0644)
+		}
+		if err != nil {
+			msg.Fprintf(os.Stderr, "Error updating %s: %v\n", item.Result.Path, err)
+			failed++
+			continue
+		}
+		written++
+	}
+	msg.Printf("Updated %d files (%d errors)\n", written, failed)
+	if failed > 0 {
+		return exitFailure
+	}
+	return exitClean
+}
@@ -0,0 +1,46 @@
+// File: undo.go
+package main
+
+import (
+	"os"
+
+	"github.com/yourusername/pathfix/pkg/processor"
+)
+
+// undoLastRun restores the files beneath rootDir modified by the last run
+// that backed them up. Files changed since that run are left alone unless
+// force is set.
+func undoLastRun(p *processor.Processor, rootDir string, verbose, force bool) int {
+	dir := p.State()
+	backup, err := dir.LatestBackup()
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	if backup == nil {
+		msg.Printf("No backup to restore in %s\n", dir.Path())
+		return exitClean
+	}
+
+	restored, conflicts, err := backup.Restore(rootDir, force)
+	if verbose {
+		for _, path := range restored {
+			msg.Printf("Restored: %s\n", path)
+		}
+	}
+	for _, path := range conflicts {
+		msg.Fprintf(os.Stderr, "Not restoring %s: it changed after the run\n", path)
+	}
+	if err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	start, _ := backup.Time()
+	msg.Printf("Restored %d files modified by the run of %s\n", len(restored), start.Local().Format("2006-01-02 15:04:05"))
+	if len(conflicts) > 0 {
+		msg.Fprintf(os.Stderr, "%d files were left alone; pass --yes to restore them anyway. The backup is kept in %s\n", len(conflicts), backup.Path())
+		return exitFailure
+	}
+	return exitClean
+}
@@ -0,0 +1,141 @@
+// File: version.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/yourusername/pathfix/pkg/i18n"
+	"github.com/yourusername/pathfix/pkg/processor"
+	"github.com/yourusername/pathfix/pkg/systemlog"
+)
+
+// Build details, set by release builds with
+// -ldflags "-X main.version=v1.2.3 -X main.commit=abc1234 -X main.date=2024-01-02T15:04:05Z".
+// Builds without them fall back to the module and VCS information Go embeds.
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// versionInfo describes the build and its defaults
+type versionInfo struct {
+	Version   string          `json:"version"`
+	Commit    string          `json:"commit,omitempty"`
+	Modified  bool            `json:"modified,omitempty"` // Built from a working tree with uncommitted changes
+	Date      string          `json:"date,omitempty"`
+	GoVersion string          `json:"go_version"`
+	Platform  string          `json:"platform"`
+	Defaults  versionDefaults `json:"defaults"`
+	Features  []string        `json:"features"`
+}
+
+// versionDefaults are the settings used when no config file overrides them
+type versionDefaults struct {
+	CommentPrefix     string `json:"comment_prefix"`
+	PathNormalization string `json:"path_normalization"`
+	InvalidPathPolicy string `json:"invalid_path_policy"`
+	BinarySampleSize  int    `json:"binary_sample_size"`
+	FileTypes         int    `json:"file_types"`     // Extensions with a built-in comment style
+	FileNames         int    `json:"file_names"`     // File names, such as Dockerfile, with a built-in comment style
+	DocFileTypes      int    `json:"doc_file_types"` // Documentation extensions added by --include-docs
+}
+
+// runVersion prints the version, build details and defaults
+func runVersion(args []string) int {
+	var asJSON bool
+
+	flags := flag.NewFlagSet("version", flag.ContinueOnError)
+	flags.BoolVar(&asJSON, "json", false, "Print the build information as JSON")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	info := buildVersion()
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(info); err != nil {
+			msg.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			return exitFailure
+		}
+		return exitClean
+	}
+
+	msg.Printf("pathfix %s\n", info.Version)
+	if info.Commit != "" {
+		modified := ""
+		if info.Modified {
+			modified = msg.Sprintf(" (modified)")
+		}
+		msg.Printf("  commit:     %s%s\n", info.Commit, modified)
+	}
+	if info.Date != "" {
+		msg.Printf("  built:      %s\n", info.Date)
+	}
+	msg.Printf("  go:         %s %s\n", info.GoVersion, info.Platform)
+	d := info.Defaults
+	msg.Printf("  defaults:   prefix %q, normalization %s, invalid names %s, binary sample %d bytes\n",
+		d.CommentPrefix, d.PathNormalization, d.InvalidPathPolicy, d.BinarySampleSize)
+	msg.Printf("  file types: %d built-in, %d by file name, %d documentation (--include-docs)\n", d.FileTypes, d.FileNames, d.DocFileTypes)
+	msg.Printf("  features:   %s\n", strings.Join(info.Features, ", "))
+	return exitClean
+}
+
+// buildVersion collects the version information, preferring values set
+// with -ldflags over those embedded by the Go toolchain
+func buildVersion() versionInfo {
+	info := versionInfo{
+		Version:   "devel",
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				info.Date = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if version != "" {
+		info.Version = version
+	}
+	if commit != "" {
+		info.Commit = commit
+		info.Modified = false
+	}
+	if date != "" {
+		info.Date = date
+	}
+
+	code, docs := processor.DefaultFileTypes()
+	info.Defaults = versionDefaults{
+		CommentPrefix:     "File: ",
+		PathNormalization: "nfc",
+		InvalidPathPolicy: "skip",
+		BinarySampleSize:  processor.DefaultBinarySampleSize,
+		FileTypes:         len(code),
+		FileNames:         len(processor.DefaultFileNames()),
+		DocFileTypes:      len(docs),
+	}
+
+	info.Features = []string{"archives (tar, tar.gz, zip)", "plugins", "starlark scripts", "hooks", "validators", "log rotation"}
+	if systemlog.Supported {
+		info.Features = append(info.Features, systemlog.TargetSyslog, systemlog.TargetJournald)
+	}
+	info.Features = append(info.Features, "locales ("+strings.Join(i18n.Locales(), ", ")+")")
+	return info
+}
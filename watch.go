@@ -0,0 +1,57 @@
+// File: watch.go
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/pathfix/pkg/models"
+	"github.com/yourusername/pathfix/pkg/processor"
+	"github.com/yourusername/pathfix/pkg/server"
+)
+
+// watchFiles adds headers to the files created beneath rootDir until the
+// run is interrupted. Without verbose output, only the files that change
+// or fail are printed. With metricsAddr, Prometheus metrics of the runs
+// are served there.
+func watchFiles(ctx context.Context, p *processor.Processor, options *processor.Options, rootDir string, verbose bool, metricsAddr string) int {
+	options.OnFileStart = nil
+	if !verbose {
+		options.OnFileUpdated = func(result models.FileResult) {
+			if options.DryRun {
+				msg.Printf("Would update: %s\n", result.Path)
+			} else {
+				msg.Printf("Updated: %s\n", result.Path)
+			}
+		}
+		options.OnError = func(path string, err error) {
+			msg.Fprintf(os.Stderr, "Error processing file %s: %s\n", path, err)
+		}
+	}
+
+	if metricsAddr != "" {
+		metrics := server.NewWatchMetrics(filepath.Base(rootDir), server.ModeFix)
+		metrics.Observe(options)
+		listener, err := net.Listen("tcp", metricsAddr)
+		if err != nil {
+			msg.Fprintf(os.Stderr, "Error serving metrics: %v\n", err)
+			return exitFailure
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics)
+		httpServer := &http.Server{Handler: mux}
+		go httpServer.Serve(listener)
+		defer httpServer.Close()
+		msg.Printf("Serving metrics on http://%s/metrics\n", listener.Addr())
+	}
+
+	msg.Printf("Watching %s for new files; press Ctrl-C to stop\n", rootDir)
+	if err := p.Watch(ctx); err != nil {
+		msg.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	return exitClean
+}